@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github-okr-fetcher/internal/adapters/config"
+	"github-okr-fetcher/internal/adapters/github"
+	"github-okr-fetcher/internal/domain/entity"
+	"github-okr-fetcher/internal/domain/service"
+)
+
+var (
+	clearExpiredOnly bool
+	clearSyncState   bool
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Inspect or clear the persistent API cache",
+	Long: `API responses are cached on disk (under the user cache directory unless
+"cache.dir" is configured) so that repeated runs within the configured TTLs,
+such as re-rendering a report in another format, need no API calls.`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show what the API cache and sync state contain",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		appConfig, err := loadConfigOrDefaults()
+		if err != nil {
+			return err
+		}
+
+		dir, err := github.CacheDir(appConfig)
+		if err != nil {
+			return err
+		}
+		stats, err := github.NewFileCache(dir).Stats()
+		if err != nil {
+			return fmt.Errorf("error reading cache: %v", err)
+		}
+
+		fmt.Printf("📁 Cache directory: %s\n", stats.Dir)
+		fmt.Printf("📦 Live entries: %d\n", stats.Entries)
+		kinds := make([]string, 0, len(stats.ByKind))
+		for kind := range stats.ByKind {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		for _, kind := range kinds {
+			fmt.Printf("   - %s: %d\n", kind, stats.ByKind[kind])
+		}
+		fmt.Printf("⌛ Expired entries: %d\n", stats.Expired)
+		fmt.Printf("💾 Size on disk: %.1f KiB\n", float64(stats.SizeBytes)/1024)
+
+		syncPath, err := github.SyncStatePath(appConfig)
+		if err != nil {
+			return err
+		}
+		state, err := github.LoadSyncState(syncPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("🔄 Sync state: %d issues tracked in %s\n", len(state.Issues), syncPath)
+		return nil
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove cached API responses",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		appConfig, err := loadConfigOrDefaults()
+		if err != nil {
+			return err
+		}
+
+		dir, err := github.CacheDir(appConfig)
+		if err != nil {
+			return err
+		}
+		cache := github.NewFileCache(dir)
+
+		if clearExpiredOnly {
+			fmt.Printf("🧹 Removed %d expired cache entries from %s\n", cache.ClearExpired(), dir)
+		} else {
+			fmt.Printf("🧹 Removed %d cache entries from %s\n", cache.Clear(), dir)
+		}
+
+		if clearSyncState {
+			syncPath, err := github.SyncStatePath(appConfig)
+			if err != nil {
+				return err
+			}
+			if err := os.Remove(syncPath); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("error removing sync state: %v", err)
+			}
+			fmt.Printf("🧹 Removed sync state %s; the next run downloads all comments\n", syncPath)
+		}
+		return nil
+	},
+}
+
+// loadConfigOrDefaults loads the config file for commands that work without one. Defaults are
+// used only when there is no config file; one that cannot be read is an error, so that e.g.
+// cache clear never acts on the default cache directory instead of the configured cache.dir.
+// Such commands need no project, so the file is not validated.
+func loadConfigOrDefaults() (*entity.Config, error) {
+	configRepo := config.NewRepository()
+	configService := service.NewConfigService(configRepo)
+
+	path := configFile
+	if path == "" {
+		path = configRepo.FindConfigFile()
+	}
+	if path == "" {
+		return configService.SetDefaults(&entity.Config{}), nil
+	}
+
+	appConfig, err := configRepo.LoadConfig(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return configService.SetDefaults(appConfig), nil
+}
+
+func init() {
+	cacheClearCmd.Flags().BoolVar(&clearExpiredOnly, "expired", false, "Only remove entries whose TTL has passed")
+	cacheClearCmd.Flags().BoolVar(&clearSyncState, "sync-state", false, "Also forget the incremental comment sync state")
+
+	cacheCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Config file path (default: config.json)")
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,318 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github-okr-fetcher/internal/adapters/config"
+	"github-okr-fetcher/internal/adapters/github"
+	"github-okr-fetcher/internal/adapters/litellm"
+	"github-okr-fetcher/internal/domain/entity"
+	"github-okr-fetcher/internal/domain/service"
+)
+
+// doctorTimeout bounds the network checks so a hanging endpoint cannot stall the command
+const doctorTimeout = 2 * time.Minute
+
+// checkStatus is the outcome of a single doctor check
+type checkStatus int
+
+const (
+	checkPass checkStatus = iota
+	checkFail
+	checkSkip
+)
+
+// checkResult is one row of the doctor report
+type checkResult struct {
+	name   string
+	status checkStatus
+	detail string
+}
+
+// doctorReport collects check results in the order they ran
+type doctorReport struct {
+	results []checkResult
+}
+
+func (r *doctorReport) pass(name, format string, args ...interface{}) {
+	r.results = append(r.results, checkResult{name, checkPass, fmt.Sprintf(format, args...)})
+}
+
+func (r *doctorReport) fail(name, format string, args ...interface{}) {
+	r.results = append(r.results, checkResult{name, checkFail, fmt.Sprintf(format, args...)})
+}
+
+func (r *doctorReport) skip(name, format string, args ...interface{}) {
+	r.results = append(r.results, checkResult{name, checkSkip, fmt.Sprintf(format, args...)})
+}
+
+// failures returns the number of failed checks
+func (r *doctorReport) failures() int {
+	count := 0
+	for _, result := range r.results {
+		if result.status == checkFail {
+			count++
+		}
+	}
+	return count
+}
+
+// print writes the results as a table
+func (r *doctorReport) print() {
+	width := len("Check")
+	for _, result := range r.results {
+		if len(result.name) > width {
+			width = len(result.name)
+		}
+	}
+
+	fmt.Printf("\n%-*s  %-6s  %s\n", width, "Check", "Result", "Details")
+	fmt.Printf("%s  %s  %s\n", strings.Repeat("-", width), strings.Repeat("-", 6), strings.Repeat("-", 40))
+	for _, result := range r.results {
+		label := "✅ ok"
+		switch result.status {
+		case checkFail:
+			label = "❌ fail"
+		case checkSkip:
+			label = "⏭️ skip"
+		}
+		fmt.Printf("%-*s  %-6s  %s\n", width, result.name, label, result.detail)
+	}
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check configuration, credentials and connectivity",
+	Long: `Run the checks a report depends on and print a pass/fail table:
+configuration and regex patterns, GitHub authentication and token scopes,
+project and view access, the remaining rate limit, the LiteLLM endpoint and
+model, and Google OAuth credentials. Exits non-zero when any check fails.`,
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		report := &doctorReport{}
+		runDoctor(cmd, report)
+		report.print()
+
+		if failed := report.failures(); failed > 0 {
+			return fmt.Errorf("%d of %d checks failed", failed, len(report.results))
+		}
+		fmt.Printf("\n✅ All checks passed\n")
+		return nil
+	},
+}
+
+// runDoctor runs every check; checks that depend on a failed one are left out
+func runDoctor(cmd *cobra.Command, report *doctorReport) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+
+	// Environment file
+	loaded, err := config.LoadDotEnv(envFile)
+	switch {
+	case err != nil:
+		report.fail("Environment file", "%v", err)
+	case loaded:
+		report.pass("Environment file", "loaded %s", envFile)
+	case cmd.Flags().Changed("env-file"):
+		report.fail("Environment file", "%s not found", envFile)
+	default:
+		report.skip("Environment file", "no %s in the working directory", envFile)
+	}
+
+	// Configuration
+	configRepo := config.NewRepository()
+	configService := service.NewConfigService(configRepo)
+	path := configFile
+	if path == "" {
+		path = configRepo.FindConfigFile()
+	}
+	var appConfig *entity.Config
+	if path == "" {
+		report.skip("Config file", "none found, using defaults")
+	} else if appConfig, err = configService.GetConfig(path); err != nil {
+		report.fail("Config file", "%v", err)
+	} else {
+		report.pass("Config file", "parsed %s", path)
+	}
+	if appConfig == nil {
+		appConfig = configService.SetDefaults(&entity.Config{})
+	}
+	if projectURL != "" {
+		appConfig.GitHub.ProjectURL = projectURL
+	}
+
+	if err := compilePatterns(appConfig.Patterns); err != nil {
+		report.fail("Config patterns", "%v", err)
+	} else {
+		report.pass("Config patterns", "%d custom regex pattern(s) compile", countPatterns(appConfig.Patterns))
+	}
+
+	checkGitHub(ctx, report, appConfig)
+	checkLiteLLM(ctx, report, appConfig)
+	checkGoogleDocs(report, appConfig)
+}
+
+// checkGitHub checks authentication, scopes, project and view access and the rate limit
+func checkGitHub(ctx context.Context, report *doctorReport, appConfig *entity.Config) {
+	token, credentials, err := resolveGitHubCredentials(appConfig)
+	if err != nil {
+		report.fail("GitHub credentials", "%v", err)
+		return
+	}
+
+	githubRepo, err := github.NewRepository(token, appConfig)
+	if err != nil {
+		report.fail("GitHub credentials", "%v", err)
+		return
+	}
+	report.pass("GitHub credentials", "%s, host %s", credentials, appConfig.GitHub.GetHost())
+
+	if err := githubRepo.TestBasicAccess(ctx, ""); err != nil {
+		report.fail("GitHub auth and scopes", "%v", err)
+		return
+	}
+	report.pass("GitHub auth and scopes", "token accepted with the required scopes")
+
+	checkProject(ctx, report, githubRepo, appConfig)
+
+	limits, err := githubRepo.FetchRateLimits(ctx)
+	if err != nil {
+		report.fail("Rate limit", "%v", err)
+	} else {
+		var parts []string
+		exhausted := ""
+		for _, limit := range limits {
+			parts = append(parts, fmt.Sprintf("%s %d/%d", limit.Resource, limit.Remaining, limit.Limit))
+			if limit.Remaining == 0 && exhausted == "" {
+				exhausted = fmt.Sprintf("%s quota exhausted until %s", limit.Resource, limit.Reset.Format("15:04:05"))
+			}
+		}
+		if exhausted != "" {
+			report.fail("Rate limit", "%s (%s)", exhausted, strings.Join(parts, ", "))
+		} else {
+			report.pass("Rate limit", "%s", strings.Join(parts, ", "))
+		}
+	}
+
+	stats := githubRepo.Stats()
+	fmt.Printf("📈 GitHub API calls made by the checks: %d (retries: %d)\n", stats.APICallsCount, stats.RetryCount)
+}
+
+// checkProject checks that the configured project and view can be read
+func checkProject(ctx context.Context, report *doctorReport, githubRepo *github.Repository, appConfig *entity.Config) {
+	if appConfig.GitHub.ProjectURL == "" {
+		report.skip("Project access", "no project URL configured (use --url)")
+		return
+	}
+
+	projectInfo, err := githubRepo.ParseProjectURL(appConfig.GitHub.ProjectURL)
+	if err != nil {
+		report.fail("Project access", "%v", err)
+		return
+	}
+
+	if projectInfo.IsOrganizationProject() {
+		if err := githubRepo.TestBasicAccess(ctx, projectInfo.Owner); err != nil {
+			report.fail("Organization access", "%v", err)
+			return
+		}
+		report.pass("Organization access", "%s", projectInfo.Owner)
+	}
+
+	title, view, err := githubRepo.CheckProject(ctx, projectInfo)
+	if title == "" {
+		report.fail("Project access", "%v", err)
+		return
+	}
+	report.pass("Project access", "%q (%s)", title, projectInfo.WebURL())
+
+	switch {
+	case !projectInfo.HasView():
+		report.skip("Project view", "the URL names no view; the whole board is used")
+	case err != nil:
+		report.fail("Project view", "%v", err)
+	default:
+		report.pass("Project view", "%q (%s layout)", view.Name, strings.ToLower(view.Layout))
+	}
+}
+
+// checkLiteLLM checks that the AI analysis endpoint is reachable and serves the configured model
+func checkLiteLLM(ctx context.Context, report *doctorReport, appConfig *entity.Config) {
+	if !appConfig.LiteLLM.Enabled {
+		report.skip("LiteLLM", "AI analysis is disabled")
+		return
+	}
+	token := os.Getenv("LITELLM_TOKEN")
+	if token == "" {
+		report.fail("LiteLLM", "LITELLM_TOKEN is not set")
+		return
+	}
+
+	client := litellm.NewClient(appConfig.LiteLLM, token)
+	if err := client.CheckModel(ctx); err != nil {
+		report.fail("LiteLLM", "%v", err)
+		return
+	}
+	report.pass("LiteLLM", "%s serves %s", appConfig.LiteLLM.BaseURL, appConfig.LiteLLM.Model)
+}
+
+// checkGoogleDocs checks that OAuth credentials are present when Google Docs output is used
+func checkGoogleDocs(report *doctorReport, appConfig *entity.Config) {
+	var missing []string
+	for _, name := range []string{"GOOGLE_CLIENT_ID", "GOOGLE_CLIENT_SECRET"} {
+		if os.Getenv(name) == "" {
+			missing = append(missing, name)
+		}
+	}
+
+	required := appConfig.Output.Format == "google-docs" || appConfig.Output.GoogleDocs.URL != ""
+	switch {
+	case len(missing) == 0:
+		report.pass("Google OAuth", "GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET are set")
+	case required:
+		report.fail("Google OAuth", "Google Docs output is configured but %s is not set", strings.Join(missing, " and "))
+	default:
+		report.skip("Google OAuth", "Google Docs output is not configured")
+	}
+}
+
+// compilePatterns compiles the custom regex patterns of the configuration
+func compilePatterns(patterns entity.PatternsConfig) error {
+	all := patterns.ParentIssuePatterns
+	if patterns.WeeklyUpdateRegex != "" {
+		all = append([]string{patterns.WeeklyUpdateRegex}, all...)
+	}
+	for _, pattern := range all {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+		}
+	}
+	return nil
+}
+
+// countPatterns returns the number of custom regex patterns
+func countPatterns(patterns entity.PatternsConfig) int {
+	count := len(patterns.ParentIssuePatterns)
+	if patterns.WeeklyUpdateRegex != "" {
+		count++
+	}
+	return count
+}
+
+func init() {
+	doctorCmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path (default: config.json)")
+	doctorCmd.Flags().StringVarP(&projectURL, "url", "u", "", "GitHub project view URL (overrides config)")
+	doctorCmd.Flags().StringVar(&envFile, "env-file", ".env", "Load environment variables such as GITHUB_TOKEN from this file if it exists")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github-okr-fetcher/internal/adapters/config"
+	"github-okr-fetcher/internal/adapters/github"
+	"github-okr-fetcher/internal/domain/entity"
+)
+
+var (
+	projectsOrg        string
+	projectsJSON       bool
+	projectsShowClosed bool
+)
+
+var projectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "Discover GitHub projects to report on",
+}
+
+var projectsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the projects of an organization with their views and fields",
+	Long: `List the ProjectV2 boards of an organization with their number, title and URL,
+the saved views with their view URLs, and the custom fields. Any of the view URLs
+can be used as project_url (or --url) as is.`,
+	Example: `  github-okr-fetcher projects list --org my-org
+  github-okr-fetcher projects list --org my-org --json`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if _, err := config.LoadDotEnv(envFile); err != nil {
+			return fmt.Errorf("error loading environment file: %v", err)
+		}
+		appConfig, err := loadConfigOrDefaults()
+		if err != nil {
+			return err
+		}
+
+		token, _, err := resolveGitHubCredentials(appConfig)
+		if err != nil {
+			return err
+		}
+		githubRepo, err := github.NewRepository(token, appConfig)
+		if err != nil {
+			return fmt.Errorf("error setting up GitHub client: %v", err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		projects, err := githubRepo.ListOrganizationProjects(ctx, projectsOrg)
+		if err != nil {
+			return err
+		}
+		if !projectsShowClosed {
+			open := projects[:0]
+			for _, project := range projects {
+				if !project.Closed {
+					open = append(open, project)
+				}
+			}
+			projects = open
+		}
+
+		if projectsJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(projects)
+		}
+		printProjects(projectsOrg, projects)
+		return nil
+	},
+}
+
+// printProjects writes each project followed by tables of its views and custom fields
+func printProjects(org string, projects []*entity.ProjectSummary) {
+	if len(projects) == 0 {
+		fmt.Printf("No projects found in organization %s\n", org)
+		return
+	}
+
+	for i, project := range projects {
+		if i > 0 {
+			fmt.Println()
+		}
+		title := project.Title
+		if project.Closed {
+			title += " (closed)"
+		}
+		fmt.Printf("📋 #%d %s\n", project.Number, title)
+		fmt.Printf("   %s\n", project.URL)
+
+		if len(project.Views) > 0 {
+			views := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(views, "   VIEW\tNAME\tLAYOUT\tURL\n")
+			for _, view := range project.Views {
+				fmt.Fprintf(views, "   %d\t%s\t%s\t%s\n", view.Number, view.Name, view.Layout, view.URL)
+			}
+			views.Flush()
+		}
+		if len(project.Fields) > 0 {
+			fields := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintf(fields, "   FIELD\tTYPE\tOPTIONS\n")
+			for _, field := range project.Fields {
+				fmt.Fprintf(fields, "   %s\t%s\t%s\n", field.Name, field.DataType, strings.Join(field.Options, ", "))
+			}
+			fields.Flush()
+		}
+	}
+}
+
+func init() {
+	projectsListCmd.Flags().StringVar(&projectsOrg, "org", "", "Organization whose projects to list")
+	projectsListCmd.Flags().BoolVarP(&projectsJSON, "json", "j", false, "Output JSON instead of a table")
+	projectsListCmd.Flags().BoolVar(&projectsShowClosed, "all", false, "Include closed projects")
+	projectsListCmd.MarkFlagRequired("org")
+
+	projectsCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Config file path (default: config.json)")
+	projectsCmd.PersistentFlags().StringVar(&envFile, "env-file", ".env", "Load environment variables such as GITHUB_TOKEN from this file if it exists")
+	projectsCmd.AddCommand(projectsListCmd)
+	rootCmd.AddCommand(projectsCmd)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github-okr-fetcher/internal/adapters/github"
 	"github-okr-fetcher/internal/adapters/litellm"
 	"github-okr-fetcher/internal/adapters/output"
+	"github-okr-fetcher/internal/adapters/recorder"
 	"github-okr-fetcher/internal/domain/entity"
 	"github-okr-fetcher/internal/domain/service"
 	"github-okr-fetcher/internal/ports"
@@ -26,6 +28,15 @@ var (
 	skipLabelFilter  bool
 	customLabels     string
 	configFile       string
+	fullSync         bool
+	skipTimelines    bool
+	runTimeout       time.Duration
+	envFile          string
+	recordDir        string
+	replayDir        string
+	asOf             string
+	groupBy          string
+	jsonSchema       int
 )
 
 var rootCmd = &cobra.Command{
@@ -43,7 +54,7 @@ Features:
 - Progress tracking: Visual progress bars and completion metrics
 - Multiple output formats: Markdown reports and JSON data export`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runMain()
+		return runMain(cmd)
 	},
 }
 
@@ -59,16 +70,35 @@ func init() {
 	rootCmd.Flags().BoolVar(&skipLabelFilter, "skip-labels", false, "Skip label filtering and process all issues")
 	rootCmd.Flags().StringVarP(&customLabels, "labels", "l", "", "Comma-separated list of required labels (overrides config)")
 	rootCmd.Flags().StringVarP(&configFile, "config", "c", "", "Config file path (default: config.json)")
+	rootCmd.Flags().BoolVar(&fullSync, "full-sync", false, "Re-download all comments instead of only those changed since the last run")
+	rootCmd.Flags().BoolVar(&skipTimelines, "skip-timelines", false, "Skip fetching issue timelines: no completion dates, cycle times or pull request evidence")
+	rootCmd.Flags().StringVar(&envFile, "env-file", ".env", "Load environment variables such as GITHUB_TOKEN from this file if it exists")
+	rootCmd.Flags().DurationVar(&runTimeout, "timeout", 0, "Stop fetching after this long and write a partial report, e.g. 5m (default: no limit)")
+	rootCmd.Flags().StringVar(&recordDir, "record", "", "Record every GitHub, LiteLLM and Google Docs API exchange to fixture files in this directory")
+	rootCmd.Flags().StringVar(&replayDir, "replay", "", "Replay API exchanges from fixtures recorded with --record instead of calling the APIs")
+	rootCmd.Flags().StringVar(&asOf, "as-of", "", "Date the report and resolve @today as of this time, e.g. 2025-01-31 or 2025-01-31T09:00:00Z (default: now)")
+	rootCmd.Flags().StringVar(&groupBy, "group-by", "", "Also list key results grouped by: owner (overrides config)")
+	rootCmd.Flags().IntVar(&jsonSchema, "json-schema", 0, "JSON schema: 1 writes the objectives array, 2 adds the project with its incomplete flag and warnings (overrides config, default: 1)")
 }
 
-func runMain() error {
+func runMain(cmd *cobra.Command) error {
+	// Environment file: variables already set in the environment take precedence
+	loaded, err := config.LoadDotEnv(envFile)
+	if err != nil {
+		return fmt.Errorf("error loading environment file: %v", err)
+	}
+	if loaded {
+		fmt.Printf("✅ Loaded environment from: %s\n", envFile)
+	} else if cmd.Flags().Changed("env-file") {
+		return fmt.Errorf("environment file %s not found", envFile)
+	}
+
 	// Initialize repositories and services
 	configRepo := config.NewRepository()
 	configService := service.NewConfigService(configRepo)
 
 	// Load configuration
 	var appConfig *entity.Config
-	var err error
 
 	if configFile == "" {
 		configFile = configRepo.FindConfigFile()
@@ -90,10 +120,38 @@ func runMain() error {
 		appConfig = configService.SetDefaults(appConfig)
 	}
 
-	// GitHub token: environment variable only for security
-	token := os.Getenv("GITHUB_TOKEN")
-	if token == "" {
-		return fmt.Errorf("GitHub token required. Set GITHUB_TOKEN environment variable")
+	// Recording: --record saves API exchanges as fixtures, --replay answers from them offline
+	switch {
+	case recordDir != "" && replayDir != "":
+		return fmt.Errorf("--record and --replay cannot be used together")
+	case recordDir != "":
+		appConfig.Recording = entity.RecordingConfig{Mode: entity.RecordingRecord, Dir: recordDir}
+		fmt.Printf("⏺️ Recording API exchanges to: %s\n", recordDir)
+	case replayDir != "":
+		appConfig.Recording = entity.RecordingConfig{Mode: entity.RecordingReplay, Dir: replayDir}
+		fmt.Printf("▶️ Replaying API exchanges from: %s\n", replayDir)
+	}
+
+	// Clock: --as-of fixes the time reports are dated with, so identical data renders identically
+	if asOf != "" {
+		asOfTime, err := entity.ParseAsOf(asOf)
+		if err != nil {
+			return fmt.Errorf("invalid --as-of: %v", err)
+		}
+		appConfig.Clock = entity.FixedClock(asOfTime)
+		fmt.Printf("🕰️ Rendering the report as of: %s\n", asOfTime.Format("2006-01-02 15:04:05"))
+	}
+
+	// GitHub credentials: token from the environment, a file or a command, or a GitHub App.
+	// A replayed run needs none.
+	var token string
+	if !appConfig.Recording.IsReplay() {
+		var credentials string
+		token, credentials, err = resolveGitHubCredentials(appConfig)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("🔑 Authenticating with %s\n", credentials)
 	}
 
 	// Project URL: CLI flag > config file
@@ -124,17 +182,56 @@ func runMain() error {
 		appConfig.Output.Format = "google-docs"
 	}
 
+	// Grouping: CLI flag > config file
+	if groupBy != "" {
+		if groupBy != entity.GroupByOwner {
+			return fmt.Errorf("invalid --group-by %q: use %s", groupBy, entity.GroupByOwner)
+		}
+		appConfig.Output.GroupBy = groupBy
+	}
+
+	// JSON schema: CLI flag > config file
+	if jsonSchema != 0 {
+		if jsonSchema != entity.JSONSchemaObjectives && jsonSchema != entity.JSONSchemaWithProject {
+			return fmt.Errorf("invalid --json-schema %d: use %d or %d", jsonSchema, entity.JSONSchemaObjectives, entity.JSONSchemaWithProject)
+		}
+		appConfig.Output.JSONSchema = jsonSchema
+	}
+
+	// Skipped timelines and full sync: CLI flag > config file
+	if skipTimelines {
+		appConfig.GitHub.SkipTimelines = true
+	}
+	if fullSync {
+		appConfig.Cache.FullSync = true
+	}
+
 	// Initialize GitHub repository and service
-	githubRepo := github.NewRepository(token, appConfig)
-	okrService := service.NewOKRService(githubRepo)
+	githubRepo, err := github.NewRepository(token, appConfig)
+	if err != nil {
+		return fmt.Errorf("error setting up GitHub client: %v", err)
+	}
+	defer func() {
+		if err := githubRepo.Close(); err != nil {
+			fmt.Printf("⚠️ Warning: could not save sync state: %v\n", err)
+		}
+	}()
+	okrService := service.NewOKRServiceWithConfig(githubRepo, appConfig)
 
 	// Initialize LiteLLM analysis service if enabled
 	// Get LiteLLM token from environment variable for security
 	liteLLMToken := os.Getenv("LITELLM_TOKEN")
+	if liteLLMToken == "" && appConfig.Recording.IsReplay() {
+		liteLLMToken = "replay"
+	}
 	var analysisService *service.AnalysisService
 	if appConfig.LiteLLM.Enabled && liteLLMToken != "" {
+		liteLLMTransport, err := recorder.NewTransport(appConfig.Recording, nil)
+		if err != nil {
+			return fmt.Errorf("error setting up LiteLLM client: %v", err)
+		}
 		// Pass token via parameter instead of config for security
-		liteLLMClient := litellm.NewClient(appConfig.LiteLLM, liteLLMToken)
+		liteLLMClient := litellm.NewClientWithTransport(appConfig.LiteLLM, liteLLMToken, liteLLMTransport)
 		analysisService = service.NewAnalysisService(liteLLMClient, appConfig)
 		fmt.Printf("🤖 LiteLLM analysis enabled with model: %s\n", appConfig.LiteLLM.Model)
 	}
@@ -142,8 +239,34 @@ func runMain() error {
 	// Initialize output service
 	reportGenerator := output.NewReportGeneratorWithConfig(appConfig)
 
-	// Main application logic
-	ctx := context.Background()
+	// Main application logic: Ctrl-C or --timeout stop fetching, and a partial report is still written
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if runTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, runTimeout)
+		defer cancel()
+	}
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-finished:
+			return
+		case <-ctx.Done():
+		}
+		select {
+		case <-finished:
+			return // cancelled by the deferred cleanup of a completed run
+		default:
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			fmt.Printf("\n⏱️ Timed out after %v, writing a partial report\n", runTimeout)
+		} else {
+			fmt.Printf("\n🛑 Interrupted, writing a partial report (press Ctrl-C again to quit immediately)\n")
+		}
+		stop() // a second Ctrl-C terminates the process
+	}()
 
 	fmt.Printf("🚀 Starting OKR data collection...\n")
 
@@ -155,7 +278,9 @@ func runMain() error {
 
 	// Perform LiteLLM analysis if enabled
 	var analysisResult *service.AnalysisResult
-	if analysisService != nil {
+	if analysisService != nil && projectInfo.Incomplete {
+		fmt.Printf("⚠️ Skipping AI analysis of an incomplete report\n")
+	} else if analysisService != nil {
 		fmt.Printf("🔍 Analyzing OKR data with AI...\n")
 		
 		// Create a project entity for analysis
@@ -189,10 +314,10 @@ func runMain() error {
 		outputFile = appConfig.GetOutputFile(projectInfo.Owner, projectInfo.ProjectID, projectInfo.ViewID)
 		// Override extension if CLI flag was used
 		if jsonOutput {
-			timestamp := time.Now().Format("20060102_150405")
+			timestamp := appConfig.Now().Format("20060102_150405")
 			outputFile = fmt.Sprintf("okr-report_%s_%d_%d_%s.json", projectInfo.Owner, projectInfo.ProjectID, projectInfo.ViewID, timestamp)
 		} else if googleDocsOutput {
-			timestamp := time.Now().Format("20060102_150405")
+			timestamp := appConfig.Now().Format("20060102_150405")
 			outputFile = fmt.Sprintf("okr-report_%s_%d_%d_%s.txt", projectInfo.Owner, projectInfo.ProjectID, projectInfo.ViewID, timestamp)
 		}
 	}
@@ -201,6 +326,10 @@ func runMain() error {
 	// Get Google OAuth credentials from environment variables for security
 	googleClientID := os.Getenv("GOOGLE_CLIENT_ID")
 	googleClientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
+	if appConfig.Recording.IsReplay() {
+		// Replayed Docs API calls need no OAuth consent
+		googleClientID, googleClientSecret = "replay", "replay"
+	}
 	
 	if outputFormat == ports.OutputFormatGoogleDocs &&
 		appConfig.Output.GoogleDocs.URL != "" &&
@@ -258,7 +387,11 @@ func runMain() error {
 	}
 
 	// Success message
-	fmt.Printf("✅ Report generated successfully: %s\n", outputFile)
+	if projectInfo.Incomplete {
+		fmt.Printf("⚠️ Partial report generated: %s\n", outputFile)
+	} else {
+		fmt.Printf("✅ Report generated successfully: %s\n", outputFile)
+	}
 
 	// Calculate file size
 	if fileInfo, err := os.Stat(outputFile); err == nil {
@@ -276,4 +409,27 @@ func runMain() error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// resolveGitHubCredentials finds the token, or the GitHub App private key, the GitHub client
+// authenticates with and describes where the credentials came from. The token is looked up in
+// GITHUB_TOKEN, then the configured token file or command; never in the config itself.
+func resolveGitHubCredentials(appConfig *entity.Config) (string, string, error) {
+	token, tokenSource, err := github.ResolveToken(appConfig.GitHub)
+	if err != nil {
+		return "", "", err
+	}
+
+	// GitHub App private key: environment variable or the file named in the config
+	if privateKey := os.Getenv("GITHUB_APP_PRIVATE_KEY"); privateKey != "" {
+		appConfig.GitHub.App.PrivateKey = privateKey
+	}
+	if appConfig.GitHub.App.IsConfigured() {
+		return "", fmt.Sprintf("GitHub App %d (installation %d)", appConfig.GitHub.App.AppID, appConfig.GitHub.App.InstallationID), nil
+	}
+
+	if token == "" {
+		return "", "", fmt.Errorf("GitHub token required. Set GITHUB_TOKEN (in the environment or .env), github.token_file, github.token_command or github.app")
+	}
+	return token, "GitHub token from " + tokenSource, nil
+}
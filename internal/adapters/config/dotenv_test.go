@@ -0,0 +1,108 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUnquoteEnvValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"plain", "plain"},
+		{`"double quoted"`, "double quoted"},
+		{`'single quoted'`, "single quoted"},
+		{`"line one\nline two"`, "line one\nline two"},
+		{`'line one\nline two'`, `line one\nline two`},
+		{"value # a comment", "value"},
+		{`"value # not a comment"`, "value # not a comment"},
+		{"value#fragment", "value#fragment"},
+		{`"unbalanced`, `"unbalanced`},
+		{`"`, `"`},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := unquoteEnvValue(tt.value); got != tt.want {
+				t.Errorf("unquoteEnvValue(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+// unsetEnv unsets the variables for the test and restores them afterwards
+func unsetEnv(t *testing.T, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+// writeDotEnv writes content to a .env file in a temporary directory
+func writeDotEnv(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadDotEnv(t *testing.T) {
+	unsetEnv(t, "OKR_PLAIN", "OKR_QUOTED", "OKR_EXPORTED", "OKR_COMMENTED", "OKR_KEY", "OKR_EMPTY")
+	t.Setenv("OKR_PRESET", "from the environment")
+
+	path := writeDotEnv(t, `# Settings for the OKR fetcher
+
+OKR_PLAIN=plain
+OKR_QUOTED = "with spaces"
+export OKR_EXPORTED='exported'
+OKR_COMMENTED=value # trailing comment
+OKR_KEY="-----BEGIN KEY-----\nabc\n-----END KEY-----"
+OKR_EMPTY=
+OKR_PRESET=from the file
+`)
+
+	loaded, err := LoadDotEnv(path)
+	if err != nil || !loaded {
+		t.Fatalf("LoadDotEnv = (%v, %v), want (true, nil)", loaded, err)
+	}
+
+	for key, want := range map[string]string{
+		"OKR_PLAIN":     "plain",
+		"OKR_QUOTED":    "with spaces",
+		"OKR_EXPORTED":  "exported",
+		"OKR_COMMENTED": "value",
+		"OKR_KEY":       "-----BEGIN KEY-----\nabc\n-----END KEY-----",
+		"OKR_EMPTY":     "",
+		// Variables already set win over the file
+		"OKR_PRESET": "from the environment",
+	} {
+		got, set := os.LookupEnv(key)
+		if !set || got != want {
+			t.Errorf("%s = %q (set: %v), want %q", key, got, set, want)
+		}
+	}
+}
+
+func TestLoadDotEnvMissingFile(t *testing.T) {
+	loaded, err := LoadDotEnv(filepath.Join(t.TempDir(), ".env"))
+	if err != nil || loaded {
+		t.Errorf("LoadDotEnv = (%v, %v), want (false, nil)", loaded, err)
+	}
+}
+
+func TestLoadDotEnvRejectsMalformedLine(t *testing.T) {
+	unsetEnv(t, "OKR_FIRST")
+	path := writeDotEnv(t, "OKR_FIRST=1\nnot a setting\n")
+
+	_, err := LoadDotEnv(path)
+	if err == nil || !strings.Contains(err.Error(), ".env:2: expected KEY=VALUE") {
+		t.Errorf("error = %v, want the line of the malformed setting", err)
+	}
+}
@@ -109,11 +109,12 @@ func (r *Repository) extractRepoInfo(config *entity.Config) error {
 	// Extract from project URLs: https://github.com/orgs/org-name/projects/123/views/456
 	// Extract from repo URLs: https://github.com/owner/repo/projects/123/views/456
 	// Extract from issue URLs: https://github.com/owner/repo/issues/123
+	// The host may also be a GitHub Enterprise Server, e.g. https://github.example.com/orgs/...
 	patterns := []string{
-		`https://github\.com/orgs/([^/]+)/projects/`,
-		`https://github\.com/([^/]+)/([^/]+)/projects/`,
-		`https://github\.com/([^/]+)/([^/]+)/issues/`,
-		`https://github\.com/([^/]+)/([^/]+)/?$`,
+		`https?://[^/]+/orgs/([^/]+)/projects/`,
+		`https?://[^/]+/([^/]+)/([^/]+)/projects/`,
+		`https?://[^/]+/([^/]+)/([^/]+)/issues/`,
+		`https?://[^/]+/([^/]+)/([^/]+)/?$`,
 	}
 	
 	for i, pattern := range patterns {
@@ -0,0 +1,335 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+
+	"github-okr-fetcher/internal/domain/entity"
+)
+
+const (
+	// appJWTLifetime stays under the ten minutes GitHub accepts for an App JWT
+	appJWTLifetime = 9 * time.Minute
+	// appJWTClockSkew backdates the JWT in case the local clock runs ahead of GitHub's
+	appJWTClockSkew = time.Minute
+	// tokenRefreshMargin renews installation tokens this long before they expire, so a
+	// request never goes out with a token that expires in flight
+	tokenRefreshMargin = 5 * time.Minute
+)
+
+// requiredScopes are the classic token scopes needed to read issues, organizations and projects
+var requiredScopes = []string{"repo", "read:org", "read:project"}
+
+// impliedScopes lists the broader scopes that also grant a required scope
+var impliedScopes = map[string][]string{
+	"read:org":     {"write:org", "admin:org"},
+	"read:project": {"project"},
+}
+
+// tokenCommandTimeout bounds how long a token command such as `gh auth token` may take
+const tokenCommandTimeout = 30 * time.Second
+
+// ResolveToken finds the personal access token: the GITHUB_TOKEN environment variable, then
+// github.token_file, then the output of github.token_command. It returns an empty token when
+// none is configured, and names where the token came from.
+func ResolveToken(config entity.GitHubConfig) (token, source string, err error) {
+	if token := strings.TrimSpace(os.Getenv("GITHUB_TOKEN")); token != "" {
+		return token, "GITHUB_TOKEN", nil
+	}
+
+	if config.TokenFile != "" {
+		data, err := os.ReadFile(config.TokenFile)
+		if err != nil {
+			return "", "", fmt.Errorf("error reading token file: %v", err)
+		}
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", "", fmt.Errorf("token file %s is empty", config.TokenFile)
+		}
+		return token, config.TokenFile, nil
+	}
+
+	if config.TokenCommand != "" {
+		args := strings.Fields(config.TokenCommand)
+		if len(args) == 0 {
+			return "", "", fmt.Errorf("github.token_command is blank; set a command such as \"gh auth token\" or remove it")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), tokenCommandTimeout)
+		defer cancel()
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			if message := strings.TrimSpace(stderr.String()); message != "" {
+				return "", "", fmt.Errorf("error running token command %q: %v: %s", config.TokenCommand, err, message)
+			}
+			return "", "", fmt.Errorf("error running token command %q: %v", config.TokenCommand, err)
+		}
+		token := strings.TrimSpace(string(output))
+		if token == "" {
+			return "", "", fmt.Errorf("token command %q printed no token", config.TokenCommand)
+		}
+		return token, config.TokenCommand, nil
+	}
+
+	return "", "", nil
+}
+
+// checkTokenScopes compares the scopes GitHub reports for a classic token with the required ones.
+// Fine-grained tokens and App installation tokens report no scopes and are not checked here.
+func checkTokenScopes(resp *http.Response) error {
+	if resp == nil {
+		return nil
+	}
+	values, ok := resp.Header["X-Oauth-Scopes"]
+	if !ok {
+		return nil
+	}
+
+	granted := make(map[string]bool)
+	var grantedList []string
+	for _, value := range values {
+		for _, scope := range strings.Split(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				granted[scope] = true
+				grantedList = append(grantedList, scope)
+			}
+		}
+	}
+
+	var missing []string
+	for _, scope := range requiredScopes {
+		if granted[scope] {
+			continue
+		}
+		implied := false
+		for _, broader := range impliedScopes[scope] {
+			implied = implied || granted[broader]
+		}
+		if !implied {
+			missing = append(missing, scope)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &ScopeError{Missing: missing, Granted: grantedList}
+	}
+	return nil
+}
+
+// newTokenSource returns the credentials requests are made with: a GitHub App installation
+// when one is configured, otherwise the personal access token. Installation tokens expire
+// after an hour and are renewed transparently during long runs.
+func newTokenSource(token string, config entity.GitHubConfig, transport http.RoundTripper) (oauth2.TokenSource, error) {
+	if !config.App.IsConfigured() {
+		if token == "" {
+			return nil, fmt.Errorf("no GitHub token or GitHub App configured")
+		}
+		return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token}), nil
+	}
+
+	return newAppTokenSource(config, transport)
+}
+
+// contextTokenSource is a token source whose token requests can be cancelled
+type contextTokenSource interface {
+	TokenContext(ctx context.Context) (*oauth2.Token, error)
+}
+
+// authTransport authorizes requests with tokens from source. Unlike oauth2.Transport it hands
+// the request's context to sources that fetch tokens over the network, so a cancelled run
+// does not wait for a token exchange.
+type authTransport struct {
+	source oauth2.TokenSource
+	base   http.RoundTripper
+}
+
+// RoundTrip sends a copy of req with the Authorization header set
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var token *oauth2.Token
+	var err error
+	if source, ok := t.source.(contextTokenSource); ok {
+		token, err = source.TokenContext(req.Context())
+	} else {
+		token, err = t.source.Token()
+	}
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
+
+	authorized := req.Clone(req.Context())
+	token.SetAuthHeader(authorized)
+	return t.base.RoundTrip(authorized)
+}
+
+// appTokenSource exchanges a JWT signed with the App's private key for installation tokens,
+// and reuses each token until it is about to expire
+type appTokenSource struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	tokenURL       string
+	client         *http.Client
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+// newAppTokenSource creates a token source for the configured App installation
+func newAppTokenSource(config entity.GitHubConfig, transport http.RoundTripper) (*appTokenSource, error) {
+	app := config.App
+	if app.AppID == 0 || app.InstallationID == 0 {
+		return nil, fmt.Errorf("GitHub App authentication needs both github.app.app_id and github.app.installation_id")
+	}
+
+	keyPEM := []byte(app.PrivateKey)
+	if len(keyPEM) == 0 {
+		if app.PrivateKeyFile == "" {
+			return nil, fmt.Errorf("GitHub App authentication needs github.app.private_key_file or GITHUB_APP_PRIVATE_KEY")
+		}
+		data, err := os.ReadFile(app.PrivateKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading GitHub App private key: %v", err)
+		}
+		keyPEM = data
+	}
+
+	key, err := parsePrivateKey(keyPEM)
+	if err != nil {
+		return nil, err
+	}
+
+	return &appTokenSource{
+		appID:          app.AppID,
+		installationID: app.InstallationID,
+		key:            key,
+		tokenURL:       fmt.Sprintf("%s/app/installations/%d/access_tokens", strings.TrimSuffix(config.GetAPIURL(), "/"), app.InstallationID),
+		client:         &http.Client{Transport: transport, Timeout: 30 * time.Second},
+	}, nil
+}
+
+// Token returns the current installation token, requesting a new one when needed
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	return s.TokenContext(context.Background())
+}
+
+// TokenContext returns the current installation token, requesting a new one when it expires
+// within tokenRefreshMargin
+func (s *appTokenSource) TokenContext(ctx context.Context) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != nil && time.Until(s.token.Expiry) > tokenRefreshMargin {
+		return s.token, nil
+	}
+	token, err := s.exchange(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	return token, nil
+}
+
+// exchange requests a new installation token
+func (s *appTokenSource) exchange(ctx context.Context) (*oauth2.Token, error) {
+	jwt, err := s.signJWT(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", s.tokenURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating installation token request: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("User-Agent", "GitHub-OKR-Fetcher/1.0")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting installation token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("error requesting installation token for installation %d: %w", s.installationID, newHTTPStatusError(resp))
+	}
+
+	var result struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error decoding installation token: %v", err)
+	}
+
+	return &oauth2.Token{AccessToken: result.Token, TokenType: "Bearer", Expiry: result.ExpiresAt}, nil
+}
+
+// signJWT creates the RS256 JWT that identifies the App itself
+func (s *appTokenSource) signJWT(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]int64{
+		"iat": now.Add(-appJWTClockSkew).Unix(),
+		"exp": now.Add(appJWTLifetime).Unix(),
+		"iss": s.appID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing GitHub App JWT: %v", err)
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// parsePrivateKey parses a PEM-encoded RSA key; GitHub issues PKCS#1 keys, PKCS#8 is accepted too
+func parsePrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("GitHub App private key is not PEM-encoded")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing GitHub App private key: %v", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("GitHub App private key is not an RSA key")
+	}
+	return key, nil
+}
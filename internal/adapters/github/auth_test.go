@@ -0,0 +1,289 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github-okr-fetcher/internal/adapters/github/githubtest"
+	"github-okr-fetcher/internal/domain/entity"
+)
+
+func TestResolveToken(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		env        string
+		config     entity.GitHubConfig
+		wantToken  string
+		wantSource string
+		wantErr    string
+	}{
+		{"environment first", "env-token", entity.GitHubConfig{TokenFile: tokenFile}, "env-token", "GITHUB_TOKEN", ""},
+		{"token file", "", entity.GitHubConfig{TokenFile: tokenFile, TokenCommand: "echo command-token"}, "file-token", tokenFile, ""},
+		{"empty token file", "", entity.GitHubConfig{TokenFile: emptyFile}, "", "", "is empty"},
+		{"token command", "", entity.GitHubConfig{TokenCommand: "echo command-token"}, "command-token", "echo command-token", ""},
+		{"blank token command", "", entity.GitHubConfig{TokenCommand: "   "}, "", "", "github.token_command is blank"},
+		{"silent token command", "", entity.GitHubConfig{TokenCommand: "true"}, "", "", "printed no token"},
+		{"nothing configured", "", entity.GitHubConfig{}, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITHUB_TOKEN", tt.env)
+			token, source, err := ResolveToken(tt.config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveToken: %v", err)
+			}
+			if token != tt.wantToken || source != tt.wantSource {
+				t.Errorf("ResolveToken = (%q, %q), want (%q, %q)", token, source, tt.wantToken, tt.wantSource)
+			}
+		})
+	}
+}
+
+// testKey generates a small RSA key; it only has to sign test JWTs
+func testKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	return key
+}
+
+// pemBlock encodes der as a PEM block of the given type
+func pemBlock(blockType string, der []byte) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}))
+}
+
+func TestParsePrivateKey(t *testing.T) {
+	key := testKey(t)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecPKCS8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		pem     string
+		wantErr string
+	}{
+		{"PKCS#1", pemBlock("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)), ""},
+		{"PKCS#8", pemBlock("PRIVATE KEY", pkcs8), ""},
+		{"not PEM", "-----not a key-----", "not PEM-encoded"},
+		{"not a key", pemBlock("PRIVATE KEY", []byte("garbage")), "error parsing GitHub App private key"},
+		{"not RSA", pemBlock("PRIVATE KEY", ecPKCS8), "not an RSA key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parsePrivateKey([]byte(tt.pem))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePrivateKey: %v", err)
+			}
+			if !parsed.Equal(key) {
+				t.Error("parsed a different key")
+			}
+		})
+	}
+}
+
+func TestSignJWT(t *testing.T) {
+	key := testKey(t)
+	source := &appTokenSource{appID: 1234, key: key}
+	now := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+
+	jwt, err := source.signJWT(now)
+	if err != nil {
+		t.Fatalf("signJWT: %v", err)
+	}
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("JWT has %d parts, want 3: %s", len(parts), jwt)
+	}
+
+	var header map[string]string
+	var claims map[string]int64
+	for i, target := range []interface{}{&header, &claims} {
+		data, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			t.Fatalf("decoding part %d: %v", i, err)
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			t.Fatalf("parsing part %d: %v", i, err)
+		}
+	}
+	if header["alg"] != "RS256" || header["typ"] != "JWT" {
+		t.Errorf("header = %v", header)
+	}
+	if claims["iss"] != 1234 || claims["iat"] != now.Add(-appJWTClockSkew).Unix() || claims["exp"] != now.Add(appJWTLifetime).Unix() {
+		t.Errorf("claims = %v", claims)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("decoding signature: %v", err)
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], signature); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+}
+
+// appServer is a fake GitHub that records the Authorization header of every request by path
+type appServer struct {
+	*githubtest.Server
+	mu            sync.Mutex
+	authorization map[string][]string
+}
+
+// newAppServer starts a fake GitHub with an organization for installation 42 to read
+func newAppServer(t *testing.T) *appServer {
+	server := &appServer{Server: githubtest.NewServer(t), authorization: make(map[string][]string)}
+	server.AddIssue(githubtest.Issue{Ref: "acme/okrs#1", Title: "Objective"})
+	server.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		server.authorization[r.URL.Path] = append(server.authorization[r.URL.Path], r.Header.Get("Authorization"))
+		return false
+	}
+	return server
+}
+
+// headers returns the Authorization headers sent to path
+func (s *appServer) headers(path string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.authorization[path]...)
+}
+
+// configureApp authenticates as installation 42 of App 1234
+func configureApp(key *rsa.PrivateKey) func(*entity.Config) {
+	return func(config *entity.Config) {
+		config.GitHub.App = entity.GitHubAppConfig{
+			AppID:          1234,
+			InstallationID: 42,
+			PrivateKey:     pemBlock("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key)),
+		}
+	}
+}
+
+func TestAppTokenSourceExchangesAndRefreshes(t *testing.T) {
+	tests := []struct {
+		name          string
+		ttl           time.Duration
+		wantExchanges int
+		want          string
+	}{
+		{"reused while valid", time.Hour, 1, "Bearer ghs_installation-42-1, Bearer ghs_installation-42-1"},
+		{"renewed close to expiry", tokenRefreshMargin / 2, 2, "Bearer ghs_installation-42-1, Bearer ghs_installation-42-2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newAppServer(t)
+			server.InstallationTokenTTL = tt.ttl
+			repo := newTestRepository(t, server.Server, configureApp(testKey(t)))
+
+			for i := 0; i < 2; i++ {
+				if err := repo.TestBasicAccess(context.Background(), "acme"); err != nil {
+					t.Fatalf("TestBasicAccess: %v", err)
+				}
+			}
+
+			if got := strings.Join(server.headers("/api/v3/orgs/acme"), ", "); got != tt.want {
+				t.Errorf("API requests authorized with %s, want %s", got, tt.want)
+			}
+			exchanges := server.headers("/api/v3/app/installations/42/access_tokens")
+			if len(exchanges) != tt.wantExchanges {
+				t.Errorf("token exchanges = %d, want %d", len(exchanges), tt.wantExchanges)
+			}
+			// Exchanges authenticate as the App itself, with a JWT it issued
+			for _, header := range exchanges {
+				parts := strings.Split(strings.TrimPrefix(header, "Bearer "), ".")
+				if len(parts) != 3 {
+					t.Errorf("token exchange authorized with %q, want a JWT", header)
+					continue
+				}
+				data, _ := base64.RawURLEncoding.DecodeString(parts[1])
+				var claims map[string]int64
+				if err := json.Unmarshal(data, &claims); err != nil || claims["iss"] != 1234 {
+					t.Errorf("JWT claims = %s, want issued by App 1234", data)
+				}
+			}
+		})
+	}
+}
+
+func TestAppTokenExchangeHonorsCancellation(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		// The exchange never answers; only the client giving up ends it
+		<-r.Context().Done()
+		return true
+	}
+	config := &entity.Config{}
+	server.Configure(config)
+	configureApp(testKey(t))(config)
+
+	source, err := newAppTokenSource(config.GitHub, http.DefaultTransport)
+	if err != nil {
+		t.Fatalf("newAppTokenSource: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := source.TokenContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("TokenContext = %v, want the context deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("cancelled exchange took %v", elapsed)
+	}
+}
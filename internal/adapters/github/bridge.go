@@ -3,10 +3,12 @@ package github
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -16,6 +18,7 @@ import (
 	"github.com/google/go-github/v58/github"
 	"golang.org/x/oauth2"
 
+	"github-okr-fetcher/internal/adapters/recorder"
 	"github-okr-fetcher/internal/domain/entity"
 )
 
@@ -26,37 +29,78 @@ import (
 type BridgeClient struct {
 	client      *github.Client
 	httpClient  *http.Client
-	ctx         context.Context
-	token       string
+	tokens      oauth2.TokenSource
 	rateLimiter *RateLimiter
 	cache       *APICache
 	stats       *ClientStats
 	config      *entity.Config
 	mu          sync.RWMutex
+
+	// syncState carries comment sync state between runs; fullSync ignores what it remembers
+	syncState *SyncState
+	fullSync  bool
+
+	// host is the GitHub host projects are expected on; graphqlURL is its GraphQL endpoint
+	host       string
+	graphqlURL string
 }
 
 // NewBridgeClient creates a new bridge client with enhanced functionality
-func NewBridgeClient(token string, config *entity.Config) *BridgeClient {
-	ctx := context.Background()
+func NewBridgeClient(token string, config *entity.Config) (*BridgeClient, error) {
+	var githubConfig entity.GitHubConfig
+	var recording entity.RecordingConfig
+	if config != nil {
+		githubConfig = config.GitHub
+		recording = config.Recording
+	}
 
 	// Get timeout from config or use default
 	timeoutSec := 30
-	if config != nil && config.GitHub.TimeoutSec > 0 {
-		timeoutSec = config.GitHub.TimeoutSec
+	if githubConfig.TimeoutSec > 0 {
+		timeoutSec = githubConfig.TimeoutSec
 	}
 	timeout := time.Duration(timeoutSec) * time.Second
 
-	// Create HTTP client with timeout
+	transport, err := newTransport(githubConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replayed runs need no credentials; App token exchanges bypass the recorder so
+	// installation tokens never end up in fixtures
+	var tokens oauth2.TokenSource
+	if recording.IsReplay() {
+		tokens = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "replay"})
+	} else if tokens, err = newTokenSource(token, githubConfig, transport); err != nil {
+		return nil, err
+	}
+
+	// API requests are recorded to or replayed from fixtures with --record/--replay
+	apiTransport, err := recorder.NewTransport(recording, transport)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create HTTP client with timeout; it authenticates GraphQL and other hand-built requests
 	httpClient := &http.Client{
-		Timeout: timeout,
+		Transport: &authTransport{source: tokens, base: apiTransport},
+		Timeout:   timeout,
 	}
 
-	// Create OAuth2 client
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token})
-	tc := oauth2.NewClient(ctx, ts)
-	tc.Timeout = timeout
+	// The REST client authenticates the same way
+	tc := &http.Client{
+		Transport: &authTransport{source: tokens, base: apiTransport},
+		Timeout:   timeout,
+	}
 
 	client := github.NewClient(tc)
+	if githubConfig.IsEnterprise() || githubConfig.APIURL != "" {
+		// GitHub Enterprise Server serves the REST API under /api/v3/ on its own host
+		client, err = client.WithEnterpriseURLs(githubConfig.GetAPIURL(), githubConfig.GetAPIURL())
+		if err != nil {
+			return nil, fmt.Errorf("invalid GitHub API URL: %v", err)
+		}
+	}
 
 	// Configure rate limiter
 	rateLimit := 5000 // Default GitHub rate limit
@@ -67,22 +111,58 @@ func NewBridgeClient(token string, config *entity.Config) *BridgeClient {
 	}
 	rateLimiter := NewRateLimiter(rateLimit)
 
-	// Initialize cache if enabled
+	// Initialize cache if enabled; recorded and replayed runs always go through the transport
 	var cache *APICache
-	if config != nil && config.Performance.CacheEnabled {
-		cache = NewAPICache()
+	if config != nil && !recording.IsActive() && (config.Performance.CacheEnabled || config.Cache.Enabled) {
+		// Persist responses so later runs, e.g. re-rendering in another format, need no API calls
+		if dir, err := CacheDir(config); err == nil {
+			cache = NewFileCache(dir)
+		} else {
+			log.Printf("⚠️  Falling back to an in-memory cache: %v", err)
+			cache = NewAPICache()
+		}
+	}
+
+	// Load incremental sync state from earlier runs; without it recorded requests do not depend on earlier runs
+	var syncState *SyncState
+	if syncPath, err := SyncStatePath(config); err == nil && !recording.IsActive() {
+		state, err := LoadSyncState(syncPath)
+		if err != nil {
+			log.Printf("⚠️  Ignoring sync state: %v", err)
+		} else {
+			syncState = state
+		}
 	}
 
 	return &BridgeClient{
 		client:      client,
 		httpClient:  httpClient,
-		ctx:         ctx,
-		token:       token,
+		tokens:      tokens,
 		rateLimiter: rateLimiter,
 		cache:       cache,
 		stats:       &ClientStats{},
 		config:      config,
+		syncState:   syncState,
+		fullSync:    config != nil && config.Cache.FullSync,
+		host:        githubConfig.GetHost(),
+		graphqlURL:  githubConfig.GetGraphQLURL(),
+	}, nil
+}
+
+// cacheConfig returns the cache settings, which may be unset
+func (b *BridgeClient) cacheConfig() entity.CacheConfig {
+	if b.config == nil {
+		return entity.CacheConfig{}
+	}
+	return b.config.Cache
+}
+
+// refCacheKey names the cache entry of an issue; references without a host are on the configured one
+func (b *BridgeClient) refCacheKey(kind string, ref entity.IssueRef) string {
+	if ref.Host == "" {
+		ref.Host = b.host
 	}
+	return kind + ":" + ref.Key().String()
 }
 
 // GetStats returns a copy of the current client statistics
@@ -96,36 +176,43 @@ func (b *BridgeClient) parseProjectURL(url string) (*entity.ProjectInfo, error)
 		regex string
 		isOrg bool
 	}{
-		{`https://github\.com/orgs/([^/]+)/projects/(\d+)/views/(\d+)`, true},
-		{`https://github\.com/orgs/([^/]+)/projects/(\d+)`, true},
-		{`https://github\.com/([^/]+)/([^/]+)/projects/(\d+)/views/(\d+)`, false},
-		{`https://github\.com/([^/]+)/([^/]+)/projects/(\d+)`, false},
+		{`https?://([^/]+)/orgs/([^/]+)/projects/(\d+)/views/(\d+)`, true},
+		{`https?://([^/]+)/orgs/([^/]+)/projects/(\d+)`, true},
+		{`https?://([^/]+)/([^/]+)/([^/]+)/projects/(\d+)/views/(\d+)`, false},
+		{`https?://([^/]+)/([^/]+)/([^/]+)/projects/(\d+)`, false},
 	}
 
 	for _, pattern := range patterns {
 		re := regexp.MustCompile(pattern.regex)
 		matches := re.FindStringSubmatch(url)
 
-		if len(matches) >= 3 {
+		if len(matches) >= 4 {
 			var projectID int
 			var err error
 
+			// Requests go to the configured API, so the project has to live on the same host
+			host := strings.ToLower(matches[1])
+			if host != b.host {
+				return nil, fmt.Errorf("project URL host %s does not match the configured GitHub host %s", host, b.host)
+			}
+
 			if pattern.isOrg {
-				projectID, err = strconv.Atoi(matches[2])
+				projectID, err = strconv.Atoi(matches[3])
 				if err != nil {
 					return nil, fmt.Errorf("invalid project ID: %v", err)
 				}
 
 				info := &entity.ProjectInfo{
-					Owner:     matches[1],
+					Host:      host,
+					Owner:     matches[2],
 					ProjectID: projectID,
 					Type:      entity.ProjectTypeOrganization,
 					URL:       url,
 				}
 
 				// Check for view ID
-				if len(matches) >= 4 {
-					viewID, err := strconv.Atoi(matches[3])
+				if len(matches) >= 5 {
+					viewID, err := strconv.Atoi(matches[4])
 					if err != nil {
 						return nil, fmt.Errorf("invalid view ID: %v", err)
 					}
@@ -134,22 +221,23 @@ func (b *BridgeClient) parseProjectURL(url string) (*entity.ProjectInfo, error)
 
 				return info, nil
 			} else {
-				projectID, err = strconv.Atoi(matches[3])
+				projectID, err = strconv.Atoi(matches[4])
 				if err != nil {
 					return nil, fmt.Errorf("invalid project ID: %v", err)
 				}
 
 				info := &entity.ProjectInfo{
-					Owner:     matches[1],
-					Repo:      matches[2],
+					Host:      host,
+					Owner:     matches[2],
+					Repo:      matches[3],
 					ProjectID: projectID,
 					Type:      entity.ProjectTypeRepository,
 					URL:       url,
 				}
 
 				// Check for view ID
-				if len(matches) >= 5 {
-					viewID, err := strconv.Atoi(matches[4])
+				if len(matches) >= 6 {
+					viewID, err := strconv.Atoi(matches[5])
 					if err != nil {
 						return nil, fmt.Errorf("invalid view ID: %v", err)
 					}
@@ -165,66 +253,61 @@ func (b *BridgeClient) parseProjectURL(url string) (*entity.ProjectInfo, error)
 }
 
 // waitForRateLimit waits for rate limit if necessary
-func (b *BridgeClient) waitForRateLimit() error {
-	return b.rateLimiter.Wait(b.ctx)
+func (b *BridgeClient) waitForRateLimit(ctx context.Context, resource RateResource) error {
+	return b.rateLimiter.Wait(ctx, resource)
 }
 
-// retryWithBackoff retries an operation with exponential backoff
-func (b *BridgeClient) retryWithBackoff(maxRetries int, operation func() error) error {
-	for i := 0; i < maxRetries; i++ {
-		if i > 0 {
-			b.stats.IncrementRetry()
-			// Exponential backoff
-			delay := time.Duration(i*i) * time.Second
-			log.Printf("🔄 Retrying operation after %v (attempt %d/%d)", delay, i+1, maxRetries)
-			time.Sleep(delay)
-		}
-
-		if err := operation(); err != nil {
-			if i == maxRetries-1 || !b.isRetryableError(err) {
-				b.stats.IncrementError()
-				return err
-			}
-			log.Printf("⚠️  Operation failed, will retry: %v", err)
-			continue
-		}
-
-		return nil
+// retryWithBackoff runs an operation, retrying transient failures and rate limits with
+// jittered exponential backoff. Transient failures stop being retried once the configured
+// maximum elapsed time has passed; permanent failures (most 4xx responses) are not retried.
+func (b *BridgeClient) retryWithBackoff(ctx context.Context, maxRetries int, operation func() error) error {
+	maxElapsed := defaultRetryMaxElapsed
+	if b.config != nil && b.config.GitHub.RetryMaxElapsedSec > 0 {
+		maxElapsed = time.Duration(b.config.GitHub.RetryMaxElapsedSec) * time.Second
 	}
+	start := time.Now()
 
-	return fmt.Errorf("operation failed after %d retries", maxRetries)
-}
+	for attempt := 1; ; attempt++ {
+		err := operation()
+		if err == nil {
+			return nil
+		}
 
-// isRetryableError checks if an error is retryable
-func (b *BridgeClient) isRetryableError(err error) bool {
-	if err == nil {
-		return false
-	}
+		class, cause := classifyError(err)
+		if ctx.Err() != nil {
+			// A cancelled or expired context fails every further attempt too
+			class, cause = errorPermanent, "cancelled"
+		}
 
-	errorStr := strings.ToLower(err.Error())
-	retryableErrors := []string{
-		"timeout",
-		"connection reset",
-		"connection refused",
-		"temporary failure",
-		"rate limit",
-		"server error",
-		"503",
-		"502",
-		"500",
-	}
+		giveUp := class == errorPermanent || attempt >= maxRetries
+		if class == errorTransient && time.Since(start) >= maxElapsed {
+			giveUp = true
+		}
+		if giveUp {
+			b.stats.IncrementError()
+			return &RequestError{Cause: cause, Attempts: attempt, Err: err}
+		}
 
-	for _, retryable := range retryableErrors {
-		if strings.Contains(errorStr, retryable) {
-			return true
+		// Rate limits are waited out by the rate limiter itself before the next attempt
+		b.stats.IncrementRetry()
+		delay := backoffDelay(attempt - 1)
+		log.Printf("🔄 %s, retrying in %v (attempt %d/%d): %v", cause, delay.Round(time.Millisecond), attempt+1, maxRetries, err)
+		select {
+		case <-ctx.Done():
+			return &RequestError{Cause: "cancelled", Attempts: attempt, Err: ctx.Err()}
+		case <-time.After(delay):
 		}
 	}
-
-	return false
 }
 
-// updateRateLimitStats updates rate limit statistics from HTTP response headers
-func (b *BridgeClient) updateRateLimitStats(resp *http.Response) {
+// updateRateLimitStats updates rate limit statistics from HTTP response headers and lets
+// the rate limiter adapt its pace to the reported quota
+func (b *BridgeClient) updateRateLimitStats(resource RateResource, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	b.rateLimiter.Update(resource, resp)
+
 	if remaining := resp.Header.Get("X-RateLimit-Remaining"); remaining != "" {
 		if val, err := strconv.Atoi(remaining); err == nil {
 			var resetTime time.Time
@@ -237,160 +320,478 @@ func (b *BridgeClient) updateRateLimitStats(resp *http.Response) {
 		}
 	}
 
-	if resp.StatusCode == 429 {
+	if resp.StatusCode == http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "" ||
+		(resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0") {
 		b.stats.IncrementRateLimitHit()
 	}
 }
 
-// fetchProjectIssuesRobust fetches issues from a GitHub project with robust error handling
-func (b *BridgeClient) fetchProjectIssuesRobust(projectInfo *entity.ProjectInfo) ([]*github.Issue, error) {
+// fetchProjectIssuesRobust fetches the issues on a GitHub ProjectV2 board by paging through its items via GraphQL.
+// When keep is set, only the items it accepts are collected and count towards max_issues_limit.
+func (b *BridgeClient) fetchProjectIssuesRobust(ctx context.Context, projectInfo *entity.ProjectInfo, keep func(ItemNode) bool) ([]ItemNode, error) {
 	log.Printf("🎯 Fetching issues from project %d (owner: %s, type: %s)",
 		projectInfo.ProjectID, projectInfo.Owner, projectInfo.Type)
 
-	// For now, fallback to search-based approach
-	query := "is:issue"
-	owner := projectInfo.Owner
-	repo := "microservices" // Default repo
+	query := orgProjectItemsQuery
+	variables := map[string]interface{}{
+		"owner":  projectInfo.Owner,
+		"number": projectInfo.ProjectID,
+	}
+	if projectInfo.IsRepositoryProject() {
+		query = repoProjectItemsQuery
+		variables["repo"] = projectInfo.Repo
+	}
 
-	if b.config != nil {
-		if b.config.GitHub.Owner != "" {
-			owner = b.config.GitHub.Owner
+	// GraphQL connections are limited to 100 nodes per page
+	pageSize := 100
+	if b.config != nil && b.config.GitHub.PageSize > 0 && b.config.GitHub.PageSize < pageSize {
+		pageSize = b.config.GitHub.PageSize
+	}
+	variables["first"] = pageSize
+
+	maxIssues := 10000
+	if b.config != nil && b.config.GitHub.MaxIssuesLimit > 0 {
+		maxIssues = b.config.GitHub.MaxIssuesLimit
+	}
+
+	var items []ItemNode
+	var cursor interface{}
+
+	for {
+		variables["cursor"] = cursor
+
+		response, err := b.executeGraphQLQuery(ctx, query, variables)
+		if err != nil && ctx.Err() != nil && len(items) > 0 {
+			// Interrupted: report on the items fetched so far
+			log.Printf("🛑 Stopped fetching project items after %d: %v", len(items), ctx.Err())
+			projectInfo.AddWarning("Fetching the project board was interrupted after %d items", len(items))
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error fetching project items: %v", err)
+		}
+
+		page := response.Data.Organization.ProjectV2.Items
+		if projectInfo.IsRepositoryProject() {
+			page = response.Data.Repository.ProjectV2.Items
+		}
+
+		for _, node := range page.Nodes {
+			// Only issues can be part of the OKR hierarchy; archived items are hidden from the board
+			if node.Type != "ISSUE" || node.IsArchived || node.Content.Number == 0 {
+				continue
+			}
+			if keep != nil && !keep(node) {
+				continue
+			}
+			items = append(items, node)
+		}
+
+		// A page can take the items past the limit; keep exactly the first maxIssues
+		if len(items) > maxIssues || (len(items) == maxIssues && page.PageInfo.HasNextPage) {
+			log.Printf("⚠️  Limiting results to %d issues to prevent memory issues", maxIssues)
+			projectInfo.AddWarning("Only the first %d items on the project board were fetched (max_issues_limit)", maxIssues)
+			items = items[:maxIssues]
+			break
 		}
-		if b.config.GitHub.Repo != "" {
-			repo = b.config.GitHub.Repo
+		if !page.PageInfo.HasNextPage || page.PageInfo.EndCursor == "" {
+			break
 		}
+		cursor = page.PageInfo.EndCursor
 	}
 
-	return b.fetchIssuesBySearchQuery(owner, repo, query)
+	log.Printf("📊 Found %d issues on project board", len(items))
+	return items, nil
 }
 
-// fetchIssuesBySearchQuery fetches issues using GitHub search API with pagination
-func (b *BridgeClient) fetchIssuesBySearchQuery(owner, repo, searchQuery string) ([]*github.Issue, error) {
+// fetchProjectView fetches the definition of the project view referenced by the project URL
+func (b *BridgeClient) fetchProjectView(ctx context.Context, projectInfo *entity.ProjectInfo) (*ProjectViewNode, error) {
+	log.Printf("🔎 Fetching definition of view %d in project %d", projectInfo.ViewID, projectInfo.ProjectID)
+
+	query := orgProjectViewQuery
+	variables := map[string]interface{}{
+		"owner":  projectInfo.Owner,
+		"number": projectInfo.ProjectID,
+		"view":   projectInfo.ViewID,
+	}
+	if projectInfo.IsRepositoryProject() {
+		query = repoProjectViewQuery
+		variables["repo"] = projectInfo.Repo
+	}
+
+	response, err := b.executeGraphQLQuery(ctx, query, variables)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching project view: %v", err)
+	}
+
+	view := response.Data.Organization.ProjectV2.View
+	if projectInfo.IsRepositoryProject() {
+		view = response.Data.Repository.ProjectV2.View
+	}
+	if view == nil {
+		return nil, fmt.Errorf("view %d not found in project %d", projectInfo.ViewID, projectInfo.ProjectID)
+	}
+
+	return view, nil
+}
+
+// fetchProjectTitle checks that the project exists and can be read, returning its title
+func (b *BridgeClient) fetchProjectTitle(ctx context.Context, projectInfo *entity.ProjectInfo) (string, error) {
+	query := orgProjectQuery
+	variables := map[string]interface{}{
+		"owner":  projectInfo.Owner,
+		"number": projectInfo.ProjectID,
+	}
+	if projectInfo.IsRepositoryProject() {
+		query = repoProjectQuery
+		variables["repo"] = projectInfo.Repo
+	}
+
+	response, err := b.executeGraphQLQuery(ctx, query, variables)
+	if err != nil {
+		return "", fmt.Errorf("error fetching project: %w", err)
+	}
+
+	title := response.Data.Organization.ProjectV2.Title
+	if projectInfo.IsRepositoryProject() {
+		title = response.Data.Repository.ProjectV2.Title
+	}
+	if title == "" {
+		return "", fmt.Errorf("project %d not found or not accessible", projectInfo.ProjectID)
+	}
+
+	return title, nil
+}
+
+// fetchRateLimits fetches the quota left for each rate limit resource; the call itself costs none
+func (b *BridgeClient) fetchRateLimits(ctx context.Context) (*github.RateLimits, error) {
+	var limits *github.RateLimits
+	operation := func() error {
+		b.stats.IncrementAPICall()
+		result, resp, err := b.client.RateLimit.Get(ctx)
+		if resp != nil {
+			b.updateRateLimitStats(ResourceCore, resp.Response)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch rate limits: %w", err)
+		}
+		limits = result
+		return nil
+	}
+
+	if err := b.retryWithBackoff(ctx, 3, operation); err != nil {
+		return nil, err
+	}
+	return limits, nil
+}
+
+// searchResultCeiling is the most results GitHub's search API returns for a single query
+const searchResultCeiling = 1000
+
+// searchEpoch is where created-date partitioning starts; no issue predates GitHub itself
+var searchEpoch = time.Date(2008, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// IssueSearchResult holds the issues collected for a search query
+type IssueSearchResult struct {
+	Issues []*github.Issue
+	// Total is the number of issues GitHub reported as matching the query
+	Total int
+	// Truncated is set when not every matching issue could be collected
+	Truncated bool
+}
+
+// issueSearch collects the results of one search query across pages and created-date windows
+type issueSearch struct {
+	bridge     *BridgeClient
+	pageSize   int
+	maxIssues  int
+	maxRetries int
+	seen       map[int64]bool
+	result     *IssueSearchResult
+}
+
+// fetchIssuesBySearchQuery fetches issues using GitHub search API with pagination.
+// The scope is a search qualifier such as "repo:owner/repo", "org:owner" or "user:login".
+// Queries matching more than searchResultCeiling issues are split into created-date windows.
+func (b *BridgeClient) fetchIssuesBySearchQuery(ctx context.Context, scope, searchQuery string) (*IssueSearchResult, error) {
 	if searchQuery == "" {
 		return nil, fmt.Errorf("no search query specified")
 	}
 
-	log.Printf("🔍 Searching repository %s/%s with query: %s", owner, repo, searchQuery)
+	log.Printf("🔍 Searching %s with query: %s", scope, searchQuery)
 
 	// Check cache first
-	cacheKey := fmt.Sprintf("search:%s/%s:%s", owner, repo, searchQuery)
+	// The cache directory is shared between hosts, so keys name the host too
+	cacheKey := fmt.Sprintf("search:%s:%s:%s", b.host, scope, searchQuery)
 	if b.cache != nil {
-		if cached, found := b.cache.GetFromCache(cacheKey); found {
-			if issues, ok := cached.([]*github.Issue); ok {
-				b.stats.IncrementCacheHit()
-				log.Printf("📊 Found %d issues from cache", len(issues))
-				return issues, nil
-			}
+		var result IssueSearchResult
+		if b.cache.GetFromCache(cacheKey, &result) {
+			b.stats.IncrementCacheHit()
+			log.Printf("📊 Found %d issues from cache", len(result.Issues))
+			return &result, nil
 		}
 	}
 
-	var allIssues []*github.Issue
-
-	operation := func() error {
-		pageSize := 100
-		if b.config != nil && b.config.GitHub.PageSize > 0 {
-			pageSize = b.config.GitHub.PageSize
+	search := &issueSearch{
+		bridge:     b,
+		pageSize:   100,
+		maxIssues:  10000,
+		maxRetries: 3,
+		seen:       make(map[int64]bool),
+		result:     &IssueSearchResult{},
+	}
+	if b.config != nil {
+		if b.config.GitHub.PageSize > 0 && b.config.GitHub.PageSize < search.pageSize {
+			search.pageSize = b.config.GitHub.PageSize
 		}
-		
-		opt := &github.SearchOptions{
-			ListOptions: github.ListOptions{
-				PerPage: pageSize,
-			},
+		if b.config.GitHub.MaxIssuesLimit > 0 {
+			search.maxIssues = b.config.GitHub.MaxIssuesLimit
+		}
+		if b.config.GitHub.MaxRetries > 0 {
+			search.maxRetries = b.config.GitHub.MaxRetries
 		}
+	}
 
-		for {
-			// Wait for rate limit
-			if err := b.waitForRateLimit(); err != nil {
-				return fmt.Errorf("rate limit error: %v", err)
-			}
+	query := fmt.Sprintf("%s %s", scope, searchQuery)
+	first, err := search.fetchPage(ctx, query, 1)
+	if err != nil {
+		return nil, err
+	}
+	search.result.Total = first.GetTotal()
+
+	switch {
+	case search.result.Total <= searchResultCeiling:
+		err = search.collect(ctx, query, first)
+	case strings.Contains(strings.ToLower(searchQuery), "created:"):
+		// The query pins its own created range, so it cannot be partitioned further
+		log.Printf("⚠️  Search matched %d issues but already filters on created date; only the first %d can be fetched",
+			search.result.Total, searchResultCeiling)
+		search.result.Truncated = true
+		err = search.collect(ctx, query, first)
+	default:
+		log.Printf("✂️  Search matched %d issues, splitting it into created-date windows of at most %d",
+			search.result.Total, searchResultCeiling)
+		// The first page already showed that the whole range is too big, so split it right away
+		err = search.split(ctx, query, searchEpoch, b.config.Now().UTC().Truncate(time.Second))
+	}
+	if err != nil && ctx.Err() != nil && len(search.result.Issues) > 0 {
+		// Interrupted: hand back what was collected, but never cache a partial result
+		log.Printf("🛑 Stopped searching after %d issues: %v", len(search.result.Issues), ctx.Err())
+		search.result.Truncated = true
+		return search.result, nil
+	}
+	if err != nil {
+		return nil, err
+	}
 
-			b.stats.IncrementAPICall()
-			result, resp, err := b.client.Search.Issues(b.ctx, fmt.Sprintf("repo:%s/%s %s", owner, repo, searchQuery), opt)
-			if err != nil {
-				return fmt.Errorf("error searching issues: %v", err)
-			}
+	result := search.result
+	if len(result.Issues) < result.Total {
+		result.Truncated = true
+	}
 
-			b.updateRateLimitStats(resp.Response)
-			allIssues = append(allIssues, result.Issues...)
+	// Cache successful response
+	if b.cache != nil {
+		b.cache.SetCache(cacheKey, result, b.cacheConfig().GetIssuesTTL())
+	}
 
-			if resp.NextPage == 0 {
-				break
-			}
-			opt.Page = resp.NextPage
+	if result.Truncated {
+		log.Printf("⚠️  Collected %d of %d issues matching search query", len(result.Issues), result.Total)
+	} else {
+		log.Printf("📊 Found %d issues matching search query", len(result.Issues))
+	}
+	return result, nil
+}
+
+// partition collects the issues created between from and to (inclusive), halving the window
+// until each part matches no more than searchResultCeiling issues
+func (s *issueSearch) partition(ctx context.Context, query string, from, to time.Time) error {
+	if s.full() {
+		return nil
+	}
+
+	windowQuery := fmt.Sprintf("%s created:%s..%s", query, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	first, err := s.fetchPage(ctx, windowQuery, 1)
+	if err != nil {
+		return err
+	}
+
+	if first.GetTotal() <= searchResultCeiling {
+		return s.collect(ctx, windowQuery, first)
+	}
+
+	// Search qualifiers have one-second resolution; a window this narrow cannot be split again
+	if to.Sub(from) < 2*time.Second {
+		log.Printf("⚠️  %d issues were created at %s; only the first %d can be fetched",
+			first.GetTotal(), from.Format(time.RFC3339), searchResultCeiling)
+		s.result.Truncated = true
+		return s.collect(ctx, windowQuery, first)
+	}
 
-			// Prevent unlimited memory growth
-			maxIssues := 10000
-			if b.config != nil && b.config.GitHub.MaxIssuesLimit > 0 {
-				maxIssues = b.config.GitHub.MaxIssuesLimit
+	return s.split(ctx, query, from, to)
+}
+
+// split partitions the two halves of a window that matches more than searchResultCeiling issues
+func (s *issueSearch) split(ctx context.Context, query string, from, to time.Time) error {
+	mid := from.Add(to.Sub(from) / 2).Truncate(time.Second)
+	if err := s.partition(ctx, query, from, mid); err != nil {
+		return err
+	}
+	return s.partition(ctx, query, mid.Add(time.Second), to)
+}
+
+// collect adds the issues on the first page and every following page of a query
+func (s *issueSearch) collect(ctx context.Context, query string, first *github.IssuesSearchResult) error {
+	page := first
+	for number := 1; ; number++ {
+		if page.GetIncompleteResults() {
+			log.Printf("⚠️  GitHub returned incomplete results for page %d of %q", number, query)
+			s.result.Truncated = true
+		}
+
+		for _, issue := range page.Issues {
+			if s.full() {
+				log.Printf("⚠️  Limiting results to %d issues to prevent memory issues", s.maxIssues)
+				s.result.Truncated = true
+				return nil
 			}
-			if len(allIssues) > maxIssues {
-				log.Printf("⚠️  Limiting results to %d issues to prevent memory issues", maxIssues)
-				break
+			if s.seen[issue.GetID()] {
+				continue
 			}
+			s.seen[issue.GetID()] = true
+			s.result.Issues = append(s.result.Issues, issue)
 		}
 
-		return nil
-	}
+		if number*s.pageSize >= first.GetTotal() || number*s.pageSize >= searchResultCeiling || len(page.Issues) == 0 {
+			return nil
+		}
 
-	maxRetries := 3
-	if b.config != nil && b.config.GitHub.MaxRetries > 0 {
-		maxRetries = b.config.GitHub.MaxRetries
+		next, err := s.fetchPage(ctx, query, number+1)
+		if err != nil {
+			return err
+		}
+		page = next
 	}
-	if err := b.retryWithBackoff(maxRetries, operation); err != nil {
-		return nil, err
+}
+
+// fetchPage fetches a single page of search results, retrying transient failures
+func (s *issueSearch) fetchPage(ctx context.Context, query string, page int) (*github.IssuesSearchResult, error) {
+	b := s.bridge
+	opt := &github.SearchOptions{
+		ListOptions: github.ListOptions{
+			Page:    page,
+			PerPage: s.pageSize,
+		},
 	}
 
-	// Cache successful response
-	if b.cache != nil {
-		cacheTTL := 10 * time.Minute
-		if b.config != nil && b.config.Cache.IssuesTTLMin > 0 {
-			cacheTTL = time.Duration(b.config.Cache.IssuesTTLMin) * time.Minute
+	var result *github.IssuesSearchResult
+	operation := func() error {
+		// Wait for rate limit
+		if err := b.waitForRateLimit(ctx, ResourceSearch); err != nil {
+			return fmt.Errorf("rate limit error: %w", err)
+		}
+
+		b.stats.IncrementAPICall()
+		res, resp, err := b.client.Search.Issues(ctx, query, opt)
+		if resp != nil {
+			b.updateRateLimitStats(ResourceSearch, resp.Response)
 		}
-		b.cache.SetCache(cacheKey, allIssues, cacheTTL)
+		if err != nil {
+			return fmt.Errorf("error searching issues: %w", err)
+		}
+
+		result = res
+		return nil
 	}
 
-	log.Printf("📊 Found %d issues matching search query", len(allIssues))
-	return allIssues, nil
+	if err := b.retryWithBackoff(ctx, s.maxRetries, operation); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// full returns true once MaxIssuesLimit issues have been collected
+func (s *issueSearch) full() bool {
+	return len(s.result.Issues) >= s.maxIssues
 }
 
-// fetchIssueComments fetches comments from a GitHub issue
-func (b *BridgeClient) fetchIssueComments(owner, repo string, issueNumber int) ([]*github.IssueComment, error) {
-	log.Printf("📝 Fetching comments for issue #%d in %s/%s", issueNumber, owner, repo)
+// fetchIssueComments fetches comments from a GitHub issue.
+// With sync state from an earlier run, unchanged issues cost no request at all and changed
+// ones only download comments updated since the last sync, using a conditional request.
+// commentCount is how many comments the issue has according to the issue listing, or -1 when
+// unknown; stored comments beyond it were deleted, and the thread is downloaded again.
+func (b *BridgeClient) fetchIssueComments(ctx context.Context, ref entity.IssueRef, updatedAt time.Time, commentCount int) ([]*github.IssueComment, error) {
+	log.Printf("📝 Fetching comments for issue %s", ref)
 
 	// Check cache first
-	cacheKey := fmt.Sprintf("comments:%s/%s:%d", owner, repo, issueNumber)
+	cacheKey := b.refCacheKey("comments", ref)
 	if b.cache != nil {
-		if cached, found := b.cache.GetFromCache(cacheKey); found {
-			if comments, ok := cached.([]*github.IssueComment); ok {
-				b.stats.IncrementCacheHit()
-				return comments, nil
-			}
+		var comments []*github.IssueComment
+		if b.cache.GetFromCache(cacheKey, &comments) {
+			b.stats.IncrementCacheHit()
+			return comments, nil
 		}
 	}
 
-	var allComments []*github.IssueComment
+	var previous *IssueSyncState
+	if b.syncState != nil && !b.fullSync {
+		previous = b.syncState.Lookup(ref)
+	}
+
+	// Nothing happened on the issue since the last sync, so neither did its comments
+	if previous != nil && !updatedAt.IsZero() && !updatedAt.After(previous.IssueUpdatedAt) &&
+		(commentCount < 0 || len(previous.Comments) == commentCount) {
+		log.Printf("⏭️  Issue %s unchanged since last sync, reusing %d comments", ref, len(previous.Comments))
+		b.stats.IncrementCacheHit()
+		return previous.Comments, nil
+	}
+
+	var since time.Time
+	var etag string
+	if previous != nil {
+		since = previous.LastSeen
+		etag = previous.ETag
+	}
+
+	var changed []*github.IssueComment
+	var newETag string
+	notModified := false
 
 	operation := func() error {
+		changed = nil // start over on retry
+		notModified = false
+		newETag = ""
 		opt := &github.IssueListCommentsOptions{
 			ListOptions: github.ListOptions{
 				PerPage: 100,
 			},
 		}
+		if !since.IsZero() {
+			opt.Since = &since
+		}
 
 		for {
 			// Wait for rate limit
-			if err := b.waitForRateLimit(); err != nil {
-				return fmt.Errorf("rate limit error: %v", err)
+			if err := b.waitForRateLimit(ctx, ResourceCore); err != nil {
+				return fmt.Errorf("rate limit error: %w", err)
 			}
 
 			b.stats.IncrementAPICall()
-			comments, resp, err := b.client.Issues.ListComments(b.ctx, owner, repo, issueNumber, opt)
+			comments, resp, err := b.listIssueComments(ctx, ref, opt, etag)
+			if resp != nil {
+				b.updateRateLimitStats(ResourceCore, resp.Response)
+			}
+			if resp != nil && resp.StatusCode == http.StatusNotModified {
+				notModified = true
+				return nil
+			}
 			if err != nil {
-				return fmt.Errorf("error fetching comments: %v", err)
+				return fmt.Errorf("error fetching comments: %w", err)
 			}
 
-			b.updateRateLimitStats(resp.Response)
-			allComments = append(allComments, comments...)
+			if opt.Page == 0 {
+				newETag = resp.Header.Get("ETag")
+			}
+			changed = append(changed, comments...)
 
 			if resp.NextPage == 0 {
 				break
@@ -401,91 +802,297 @@ func (b *BridgeClient) fetchIssueComments(owner, repo string, issueNumber int) (
 		return nil
 	}
 
-	if err := b.retryWithBackoff(3, operation); err != nil {
+	if err := b.retryWithBackoff(ctx, 3, operation); err != nil {
 		return nil, err
 	}
 
+	var allComments []*github.IssueComment
+	switch {
+	case notModified:
+		log.Printf("✅ Comments for issue %s not modified since last sync", ref)
+		allComments = previous.Comments
+		newETag = etag
+	case previous != nil:
+		allComments = mergeComments(previous.Comments, changed)
+		log.Printf("🔄 %d comments changed on issue %s since last sync", len(changed), ref)
+	default:
+		allComments = changed
+	}
+
+	// Listing with "since" never reports deleted comments; with more comments stored than the
+	// issue has, some were deleted, so download the whole thread again
+	if previous != nil && commentCount >= 0 && len(allComments) > commentCount {
+		log.Printf("🧹 %d stored comments on issue %s were deleted, downloading them again", len(allComments)-commentCount, ref)
+		since, etag = time.Time{}, ""
+		if err := b.retryWithBackoff(ctx, 3, operation); err != nil {
+			return nil, err
+		}
+		allComments = changed
+	}
+
+	if b.syncState != nil {
+		lastSeen := newestCommentUpdate(allComments)
+		// The ETag only matches a request made with the same "since"
+		if !lastSeen.Equal(since) {
+			newETag = ""
+		}
+		b.syncState.Store(ref, &IssueSyncState{
+			IssueUpdatedAt: updatedAt,
+			LastSeen:       lastSeen,
+			ETag:           newETag,
+			Comments:       allComments,
+		})
+	}
+
 	// Cache the results
 	if b.cache != nil {
-		b.cache.SetCache(cacheKey, allComments, 5*time.Minute)
+		b.cache.SetCache(cacheKey, allComments, b.cacheConfig().GetCommentsTTL())
 	}
 
-	log.Printf("📊 Found %d comments for issue #%d", len(allComments), issueNumber)
+	log.Printf("📊 Found %d comments for issue %s", len(allComments), ref)
 	return allComments, nil
 }
 
-// findParentIssueFromRelationships attempts to find parent issue relationships
-func (b *BridgeClient) findParentIssueFromRelationships(owner, repo string, issueNumber int) (int, error) {
-	// This could be implemented to check GitHub issue relationships
-	// For now, return 0 (no parent found)
-	return 0, nil
+// listIssueComments lists one page of comments on an issue. When etag is set the first page
+// is requested conditionally; GitHub answers 304 Not Modified without charging the rate limit.
+func (b *BridgeClient) listIssueComments(ctx context.Context, ref entity.IssueRef, opt *github.IssueListCommentsOptions, etag string) ([]*github.IssueComment, *github.Response, error) {
+	if etag == "" || opt.Page > 0 {
+		return b.client.Issues.ListComments(ctx, ref.Owner, ref.Repo, ref.Number, opt)
+	}
+
+	query := url.Values{}
+	query.Set("per_page", fmt.Sprintf("%d", opt.PerPage))
+	if opt.Since != nil {
+		query.Set("since", opt.Since.Format(time.RFC3339))
+	}
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/comments?%s", ref.Owner, ref.Repo, ref.Number, query.Encode())
+
+	req, err := b.client.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("If-None-Match", etag)
+
+	var comments []*github.IssueComment
+	resp, err := b.client.Do(ctx, req, &comments)
+	if err != nil {
+		return nil, resp, err
+	}
+	return comments, resp, nil
 }
 
-// testBasicAccess tests basic access to GitHub organization
-func (b *BridgeClient) testBasicAccess(org string) error {
-	operation := func() error {
-		if err := b.waitForRateLimit(); err != nil {
-			return fmt.Errorf("rate limit error: %v", err)
+// SaveSyncState persists the incremental sync state for the next run
+func (b *BridgeClient) SaveSyncState() error {
+	if b.syncState == nil {
+		return nil
+	}
+	return b.syncState.Save()
+}
+
+// findParentIssue returns the parent of an issue through GitHub's sub-issue relationship, or nil
+func (b *BridgeClient) findParentIssue(ctx context.Context, ref entity.IssueRef) (*LinkedIssueNode, error) {
+	variables := map[string]interface{}{
+		"owner":  ref.Owner,
+		"repo":   ref.Repo,
+		"number": ref.Number,
+	}
+
+	response, err := b.executeGraphQLQuery(ctx, issueParentQuery, variables)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching parent of %s: %v", ref, err)
+	}
+
+	if response.Data.Repository.Issue == nil {
+		return nil, nil
+	}
+	return response.Data.Repository.Issue.Parent, nil
+}
+
+// fetchIssueTimeline fetches when an issue was opened together with its close, reopen, label and
+// project status events, oldest first. Like comments, the timeline of an issue that has not been
+// updated since the last sync is reused without a request.
+func (b *BridgeClient) fetchIssueTimeline(ctx context.Context, ref entity.IssueRef, updatedAt time.Time) (*IssueTimelineNode, error) {
+	cacheKey := b.refCacheKey("timeline", ref)
+	if b.cache != nil {
+		var cached IssueTimelineNode
+		if b.cache.GetFromCache(cacheKey, &cached) {
+			b.stats.IncrementCacheHit()
+			return &cached, nil
 		}
+	}
 
-		b.stats.IncrementAPICall()
-		_, resp, err := b.client.Organizations.Get(b.ctx, org)
+	if b.syncState != nil && !b.fullSync {
+		previous := b.syncState.LookupTimeline(ref)
+		if previous != nil && previous.Timeline != nil && !updatedAt.IsZero() && !updatedAt.After(previous.IssueUpdatedAt) {
+			log.Printf("⏭️  Issue %s unchanged since last sync, reusing its timeline", ref)
+			b.stats.IncrementCacheHit()
+			return previous.Timeline, nil
+		}
+	}
+
+	log.Printf("🕒 Fetching timeline for issue %s", ref)
+
+	pageSize := 100
+	if b.config != nil && b.config.GitHub.PageSize > 0 && b.config.GitHub.PageSize < pageSize {
+		pageSize = b.config.GitHub.PageSize
+	}
+	variables := map[string]interface{}{
+		"owner":  ref.Owner,
+		"repo":   ref.Repo,
+		"number": ref.Number,
+		"first":  pageSize,
+	}
+
+	timeline := &IssueTimelineNode{}
+	var cursor interface{}
+	for {
+		variables["cursor"] = cursor
+
+		response, err := b.executeGraphQLQuery(ctx, issueTimelineQuery, variables)
 		if err != nil {
-			return fmt.Errorf("failed to access organization %s: %v", org, err)
+			return nil, fmt.Errorf("error fetching timeline of %s: %w", ref, err)
 		}
 
-		if resp != nil {
-			b.updateRateLimitStats(resp.Response)
+		issue := response.Data.Repository.Issue
+		if issue == nil {
+			return nil, fmt.Errorf("issue %s not found", ref)
 		}
+		if cursor == nil {
+			timeline.CreatedAt = issue.CreatedAt
+			if issue.Author != nil {
+				timeline.Author = issue.Author.Login
+			}
+			timeline.ClosedBy = issue.ClosedBy.Nodes
+		}
+		timeline.Items = append(timeline.Items, issue.TimelineItems.Nodes...)
 
-		return nil
+		if !issue.TimelineItems.PageInfo.HasNextPage || issue.TimelineItems.PageInfo.EndCursor == "" {
+			break
+		}
+		cursor = issue.TimelineItems.PageInfo.EndCursor
 	}
 
-	return b.retryWithBackoff(3, operation)
+	if b.syncState != nil && !updatedAt.IsZero() {
+		b.syncState.StoreTimeline(ref, &TimelineSyncState{IssueUpdatedAt: updatedAt, Timeline: timeline})
+	}
+	if b.cache != nil {
+		b.cache.SetCache(cacheKey, timeline, b.cacheConfig().GetGraphQLTTL())
+	}
+
+	log.Printf("📊 Found %d timeline events for issue %s", len(timeline.Items), ref)
+	return timeline, nil
 }
 
-// listOrganizationProjects lists projects in a GitHub organization
-func (b *BridgeClient) listOrganizationProjects(org string) error {
+// testBasicAccess tests basic access to GitHub organization and checks the token's scopes.
+// Without an organization only the token itself is checked.
+func (b *BridgeClient) testBasicAccess(ctx context.Context, org string) error {
+	var scopeErr error
 	operation := func() error {
-		if err := b.waitForRateLimit(); err != nil {
-			return fmt.Errorf("rate limit error: %v", err)
+		if err := b.waitForRateLimit(ctx, ResourceCore); err != nil {
+			return fmt.Errorf("rate limit error: %w", err)
 		}
 
 		b.stats.IncrementAPICall()
-		projects, resp, err := b.client.Organizations.ListProjects(b.ctx, org, nil)
-		if err != nil {
-			return fmt.Errorf("failed to list projects for organization %s: %v", org, err)
+		var resp *github.Response
+		var err error
+		if org == "" {
+			// The rate limit endpoint costs no quota and still reports the token's scopes
+			_, resp, err = b.client.RateLimit.Get(ctx)
+		} else {
+			_, resp, err = b.client.Organizations.Get(ctx, org)
 		}
-
 		if resp != nil {
-			b.updateRateLimitStats(resp.Response)
+			b.updateRateLimitStats(ResourceCore, resp.Response)
+			// Missing scopes explain a failure better than the 404 GitHub returns for them
+			if scopeErr = checkTokenScopes(resp.Response); scopeErr != nil {
+				return nil
+			}
 		}
-
-		log.Printf("📊 Found %d projects in organization %s", len(projects), org)
-		for _, project := range projects {
-			if project.Name != nil && project.ID != nil {
-				log.Printf("  - %s (ID: %d)", *project.Name, *project.ID)
+		if err != nil {
+			if org == "" {
+				return fmt.Errorf("failed to authenticate with GitHub: %w", err)
 			}
+			return fmt.Errorf("failed to access organization %s: %w", org, err)
 		}
 
 		return nil
 	}
 
-	return b.retryWithBackoff(3, operation)
+	err := b.retryWithBackoff(ctx, 3, operation)
+	if scopeErr != nil {
+		return scopeErr
+	}
+	return err
+}
+
+// checkAccess runs testBasicAccess unless a check of the same organization passed within the
+// GraphQL cache TTL, so runs served from the cache make no API calls. Failures are never cached.
+func (b *BridgeClient) checkAccess(ctx context.Context, org string) error {
+	cacheKey := fmt.Sprintf("access:%s:%s", b.host, org)
+	if b.cache != nil {
+		var passed bool
+		if b.cache.GetFromCache(cacheKey, &passed) && passed {
+			b.stats.IncrementCacheHit()
+			return nil
+		}
+	}
+
+	if err := b.testBasicAccess(ctx, org); err != nil {
+		return err
+	}
+
+	if b.cache != nil {
+		b.cache.SetCache(cacheKey, true, b.cacheConfig().GetGraphQLTTL())
+	}
+	return nil
+}
+
+// listOrganizationProjects lists the ProjectV2 boards of an organization with their views and fields
+func (b *BridgeClient) listOrganizationProjects(ctx context.Context, org string) ([]ProjectListNode, error) {
+	variables := map[string]interface{}{
+		"owner": org,
+		"first": 20,
+	}
+
+	var projects []ProjectListNode
+	var cursor interface{}
+	for {
+		variables["cursor"] = cursor
+
+		response, err := b.executeGraphQLQuery(ctx, orgProjectsQuery, variables)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects for organization %s: %w", org, err)
+		}
+
+		page := response.Data.Organization.ProjectsV2
+		projects = append(projects, page.Nodes...)
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		// A next page without a new cursor would request the same page forever
+		if page.PageInfo.EndCursor == "" || page.PageInfo.EndCursor == cursor {
+			return nil, fmt.Errorf("failed to list projects for organization %s: next page has no new cursor after %d projects", org, len(projects))
+		}
+		cursor = page.PageInfo.EndCursor
+	}
+
+	log.Printf("📊 Found %d projects in organization %s", len(projects), org)
+	return projects, nil
 }
 
 // executeGraphQLQuery executes a GraphQL query against GitHub API
-func (b *BridgeClient) executeGraphQLQuery(query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+func (b *BridgeClient) executeGraphQLQuery(ctx context.Context, query string, variables map[string]interface{}) (*GraphQLResponse, error) {
 	// Create cache key
-	cacheKey := fmt.Sprintf("graphql:%x", Hash(query+fmt.Sprint(variables)))
+	// The key must be collision-free now that entries outlive the process and are shared between hosts
+	sum := sha256.Sum256([]byte(query + fmt.Sprint(variables)))
+	cacheKey := fmt.Sprintf("graphql:%s:%x", b.host, sum)
 
 	// Check cache first
 	if b.cache != nil {
-		if cached, found := b.cache.GetFromCache(cacheKey); found {
-			if response, ok := cached.(*GraphQLResponse); ok {
-				b.stats.IncrementCacheHit()
-				return response, nil
-			}
+		var cached GraphQLResponse
+		if b.cache.GetFromCache(cacheKey, &cached) {
+			b.stats.IncrementCacheHit()
+			return &cached, nil
 		}
 	}
 
@@ -502,29 +1109,32 @@ func (b *BridgeClient) executeGraphQLQuery(query string, variables map[string]in
 			return fmt.Errorf("error marshaling request: %v", err)
 		}
 
-		req, err := http.NewRequestWithContext(b.ctx, "POST", "https://api.github.com/graphql", bytes.NewBuffer(jsonBody))
+		req, err := http.NewRequestWithContext(ctx, "POST", b.graphqlURL, bytes.NewBuffer(jsonBody))
 		if err != nil {
 			return fmt.Errorf("error creating request: %v", err)
 		}
 
-		req.Header.Set("Authorization", "Bearer "+b.token)
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("User-Agent", "GitHub-OKR-Fetcher/1.0")
+		req.Header.Set("GraphQL-Features", "sub_issues") // parent/subIssues fields
 
 		// Wait for rate limit
-		if err := b.waitForRateLimit(); err != nil {
-			return fmt.Errorf("rate limit error: %v", err)
+		if err := b.waitForRateLimit(ctx, ResourceGraphQL); err != nil {
+			return fmt.Errorf("rate limit error: %w", err)
 		}
 
 		b.stats.IncrementAPICall()
 		resp, err := b.httpClient.Do(req)
 		if err != nil {
-			return fmt.Errorf("error executing request: %v", err)
+			return fmt.Errorf("error executing request: %w", err)
 		}
 		defer resp.Body.Close()
 
 		// Update rate limit stats
-		b.updateRateLimitStats(resp)
+		b.updateRateLimitStats(ResourceGraphQL, resp)
+		if resp.StatusCode >= 400 {
+			return newHTTPStatusError(resp)
+		}
 
 		var graphqlResp GraphQLResponse
 		if err := json.NewDecoder(resp.Body).Decode(&graphqlResp); err != nil {
@@ -532,20 +1142,20 @@ func (b *BridgeClient) executeGraphQLQuery(query string, variables map[string]in
 		}
 
 		if len(graphqlResp.Errors) > 0 {
-			return fmt.Errorf("GraphQL errors: %v", graphqlResp.Errors)
+			return graphqlResp.Errors
 		}
 
 		response = &graphqlResp
 		return nil
 	}
 
-	if err := b.retryWithBackoff(3, operation); err != nil {
+	if err := b.retryWithBackoff(ctx, 3, operation); err != nil {
 		return nil, err
 	}
 
 	// Cache successful response
 	if b.cache != nil {
-		b.cache.SetCache(cacheKey, response, 5*time.Minute)
+		b.cache.SetCache(cacheKey, response, b.cacheConfig().GetGraphQLTTL())
 	}
 
 	return response, nil
@@ -573,29 +1183,306 @@ func (b *BridgeClient) hasRequiredLabels(issue *github.Issue, requiredLabels []s
 	return true
 }
 
+// projectItemFields selects the project item data needed to build the OKR hierarchy
+const projectItemFields = `
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          type
+          isArchived
+          content {
+            ... on Issue {
+              number
+              title
+              url
+              state
+              body
+              repository { owner { login } name }
+              labels(first: 100) { nodes { name } }
+              author { login }
+              assignees(first: 20) { nodes { login } }
+              milestone { title dueOn state }
+              createdAt
+              updatedAt
+              comments { totalCount }
+              parent { ...LinkedIssue }
+            }
+          }
+          fieldValues(first: 50) {
+            nodes {
+              __typename
+              ... on ProjectV2ItemFieldTextValue { text field { ...FieldName } }
+              ... on ProjectV2ItemFieldNumberValue { number field { ...FieldName } }
+              ... on ProjectV2ItemFieldDateValue { date field { ...FieldName } }
+              ... on ProjectV2ItemFieldSingleSelectValue { name field { ...FieldName } }
+              ... on ProjectV2ItemFieldIterationValue { title startDate duration field { ...FieldName } }
+            }
+          }
+        }`
+
+// linkedIssueFragment selects an issue reached through the sub-issue relationship
+const linkedIssueFragment = `
+fragment LinkedIssue on Issue {
+  number
+  title
+  url
+  state
+  body
+  repository { owner { login } name }
+  labels(first: 100) { nodes { name } }
+  author { login }
+  assignees(first: 20) { nodes { login } }
+  milestone { title dueOn state }
+  createdAt
+  updatedAt
+}`
+
+// fieldNameFragment resolves the name of any project field configuration
+const fieldNameFragment = `
+fragment FieldName on ProjectV2FieldConfiguration {
+  ... on ProjectV2FieldCommon { name }
+}`
+
+// orgProjectItemsQuery pages through the items of an organization ProjectV2 board
+const orgProjectItemsQuery = `query($owner: String!, $number: Int!, $first: Int!, $cursor: String) {
+  organization(login: $owner) {
+    projectV2(number: $number) {
+      items(first: $first, after: $cursor) {` + projectItemFields + `
+      }
+    }
+  }
+}` + fieldNameFragment + linkedIssueFragment
+
+// repoProjectItemsQuery pages through the items of a repository ProjectV2 board
+const repoProjectItemsQuery = `query($owner: String!, $repo: String!, $number: Int!, $first: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    projectV2(number: $number) {
+      items(first: $first, after: $cursor) {` + projectItemFields + `
+      }
+    }
+  }
+}` + fieldNameFragment + linkedIssueFragment
+
+// projectViewFields selects a view definition together with the fields it sorts and groups by
+const projectViewFields = `
+      view(number: $view) {
+        number
+        name
+        layout
+        filter
+        sortByFields(first: 10) {
+          nodes { direction field { ...FieldDefinition } }
+        }
+        groupByFields(first: 5) {
+          nodes { ...FieldDefinition }
+        }
+      }`
+
+// fieldDefinitionFragment resolves the name and, for single select fields, the option order of a field
+const fieldDefinitionFragment = `
+fragment FieldDefinition on ProjectV2FieldConfiguration {
+  ... on ProjectV2FieldCommon { name }
+  ... on ProjectV2SingleSelectField { options { name } }
+}`
+
+// orgProjectQuery fetches the title of an organization ProjectV2 board
+const orgProjectQuery = `query($owner: String!, $number: Int!) {
+  organization(login: $owner) {
+    projectV2(number: $number) { title }
+  }
+}`
+
+// repoProjectQuery fetches the title of a repository ProjectV2 board
+const repoProjectQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    projectV2(number: $number) { title }
+  }
+}`
+
+// orgProjectsQuery pages through the ProjectV2 boards of an organization with their views and fields
+const orgProjectsQuery = `query($owner: String!, $first: Int!, $cursor: String) {
+  organization(login: $owner) {
+    projectsV2(first: $first, after: $cursor, orderBy: {field: NUMBER, direction: ASC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number
+        title
+        url
+        closed
+        views(first: 50) { nodes { number name layout } }
+        fields(first: 50) {
+          nodes {
+            ... on ProjectV2FieldCommon { name dataType }
+            ... on ProjectV2SingleSelectField { options { name } }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// orgProjectViewQuery fetches a view definition of an organization ProjectV2 board
+const orgProjectViewQuery = `query($owner: String!, $number: Int!, $view: Int!) {
+  organization(login: $owner) {
+    projectV2(number: $number) {` + projectViewFields + `
+    }
+  }
+}` + fieldDefinitionFragment
+
+// repoProjectViewQuery fetches a view definition of a repository ProjectV2 board
+const repoProjectViewQuery = `query($owner: String!, $repo: String!, $number: Int!, $view: Int!) {
+  repository(owner: $owner, name: $repo) {
+    projectV2(number: $number) {` + projectViewFields + `
+    }
+  }
+}` + fieldDefinitionFragment
+
+// issueParentQuery fetches the sub-issue parent of an issue, which may live in another repository
+const issueParentQuery = `query($owner: String!, $repo: String!, $number: Int!) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) {
+      parent { ...LinkedIssue }
+    }
+  }
+}` + linkedIssueFragment
+
+// issueTimelineQuery pages through the events of an issue that make up its OKR history
+const issueTimelineQuery = `query($owner: String!, $repo: String!, $number: Int!, $first: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    issue(number: $number) {
+      createdAt
+      author { login }
+      closedByPullRequestsReferences(first: 25, includeClosedPrs: true) {
+        nodes { ...LinkedPullRequest }
+      }
+      timelineItems(first: $first, after: $cursor, itemTypes: [CLOSED_EVENT, REOPENED_EVENT, LABELED_EVENT, UNLABELED_EVENT, PROJECT_V2_ITEM_STATUS_CHANGED_EVENT, CROSS_REFERENCED_EVENT]) {
+        pageInfo { hasNextPage endCursor }
+        nodes {
+          __typename
+          ... on ClosedEvent { createdAt actor { login } stateReason }
+          ... on ReopenedEvent { createdAt actor { login } }
+          ... on LabeledEvent { createdAt actor { login } label { name } }
+          ... on UnlabeledEvent { createdAt actor { login } label { name } }
+          ... on ProjectV2ItemStatusChangedEvent { createdAt actor { login } previousStatus status project { title } }
+          ... on CrossReferencedEvent { createdAt actor { login } willCloseTarget source { __typename ...LinkedPullRequest } }
+        }
+      }
+    }
+  }
+}` + linkedPullRequestFragment
+
+// linkedPullRequestFragment selects a pull request that references or closes an issue
+const linkedPullRequestFragment = `
+fragment LinkedPullRequest on PullRequest {
+  number
+  title
+  url
+  state
+  createdAt
+  mergedAt
+  repository { nameWithOwner }
+}`
+
 // GraphQL response structures
 type GraphQLResponse struct {
 	Data struct {
 		Organization struct {
-			ProjectV2 struct {
-				Items struct {
-					PageInfo PageInfo   `json:"pageInfo"`
-					Nodes    []ItemNode `json:"nodes"`
-				} `json:"items"`
-			} `json:"projectV2"`
+			ProjectV2  ProjectV2Node `json:"projectV2"`
+			ProjectsV2 struct {
+				PageInfo PageInfo          `json:"pageInfo"`
+				Nodes    []ProjectListNode `json:"nodes"`
+			} `json:"projectsV2"`
 		} `json:"organization"`
 		Repository struct {
-			ProjectV2 struct {
-				Items struct {
-					PageInfo PageInfo   `json:"pageInfo"`
-					Nodes    []ItemNode `json:"nodes"`
-				} `json:"items"`
-			} `json:"projectV2"`
+			ProjectV2 ProjectV2Node `json:"projectV2"`
+			Issue     *struct {
+				Parent    *LinkedIssueNode `json:"parent"`
+				CreatedAt time.Time        `json:"createdAt"`
+				Author    *ActorNode       `json:"author"`
+				ClosedBy  struct {
+					Nodes []PullRequestNode `json:"nodes"`
+				} `json:"closedByPullRequestsReferences"`
+				TimelineItems struct {
+					PageInfo PageInfo           `json:"pageInfo"`
+					Nodes    []TimelineItemNode `json:"nodes"`
+				} `json:"timelineItems"`
+			} `json:"issue"`
 		} `json:"repository"`
 	} `json:"data"`
-	Errors []struct {
-		Message string `json:"message"`
-	} `json:"errors"`
+	Errors GraphQLErrors `json:"errors"`
+}
+
+// ProjectV2Node represents a ProjectV2 board from GraphQL
+type ProjectV2Node struct {
+	Title string `json:"title"`
+	Items struct {
+		PageInfo PageInfo   `json:"pageInfo"`
+		Nodes    []ItemNode `json:"nodes"`
+	} `json:"items"`
+	View *ProjectViewNode `json:"view"`
+}
+
+// ProjectListNode represents a ProjectV2 board in a list of projects
+type ProjectListNode struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Closed bool   `json:"closed"`
+	Views  struct {
+		Nodes []struct {
+			Number int    `json:"number"`
+			Name   string `json:"name"`
+			Layout string `json:"layout"`
+		} `json:"nodes"`
+	} `json:"views"`
+	Fields struct {
+		Nodes []struct {
+			Name     string `json:"name"`
+			DataType string `json:"dataType"`
+			Options  []struct {
+				Name string `json:"name"`
+			} `json:"options"`
+		} `json:"nodes"`
+	} `json:"fields"`
+}
+
+// ProjectViewNode represents a saved project view with its filter, sort and grouping
+type ProjectViewNode struct {
+	Number       int    `json:"number"`
+	Name         string `json:"name"`
+	Layout       string `json:"layout"`
+	Filter       string `json:"filter"`
+	SortByFields struct {
+		Nodes []struct {
+			Direction string           `json:"direction"`
+			Field     ProjectFieldNode `json:"field"`
+		} `json:"nodes"`
+	} `json:"sortByFields"`
+	GroupByFields struct {
+		Nodes []ProjectFieldNode `json:"nodes"`
+	} `json:"groupByFields"`
+}
+
+// ProjectFieldNode represents a project field definition
+type ProjectFieldNode struct {
+	Name    string `json:"name"`
+	Options []struct {
+		Name string `json:"name"`
+	} `json:"options"`
+}
+
+// FieldValueNode represents the value of a project field on an item
+type FieldValueNode struct {
+	Typename  string   `json:"__typename"`
+	Text      string   `json:"text"`
+	Number    *float64 `json:"number"`
+	Date      string   `json:"date"`
+	Name      string   `json:"name"`
+	Title     string   `json:"title"`
+	StartDate string   `json:"startDate"`
+	Duration  int      `json:"duration"`
+	Field     struct {
+		Name string `json:"name"`
+	} `json:"field"`
 }
 
 // PageInfo contains pagination information
@@ -606,8 +1493,9 @@ type PageInfo struct {
 
 // ItemNode represents a project item node from GraphQL
 type ItemNode struct {
-	Type    string `json:"type"`
-	Content struct {
+	Type       string `json:"type"`
+	IsArchived bool   `json:"isArchived"`
+	Content    struct {
 		Number     int    `json:"number"`
 		Title      string `json:"title"`
 		URL        string `json:"url"`
@@ -624,5 +1512,103 @@ type ItemNode struct {
 				Name string `json:"name"`
 			} `json:"nodes"`
 		} `json:"labels"`
+		Author    *ActorNode `json:"author"`
+		Assignees struct {
+			Nodes []ActorNode `json:"nodes"`
+		} `json:"assignees"`
+		Milestone *MilestoneNode   `json:"milestone"`
+		CreatedAt time.Time        `json:"createdAt"`
+		UpdatedAt time.Time        `json:"updatedAt"`
+		Comments  *CountNode       `json:"comments"`
+		Parent    *LinkedIssueNode `json:"parent"`
 	} `json:"content"`
+	FieldValues struct {
+		Nodes []FieldValueNode `json:"nodes"`
+	} `json:"fieldValues"`
+}
+
+// LinkedIssueNode represents an issue reached through the sub-issue relationship
+type LinkedIssueNode struct {
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	URL        string `json:"url"`
+	State      string `json:"state"`
+	Body       string `json:"body"`
+	Repository struct {
+		Owner struct {
+			Login string `json:"login"`
+		} `json:"owner"`
+		Name string `json:"name"`
+	} `json:"repository"`
+	Labels struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Author    *ActorNode `json:"author"`
+	Assignees struct {
+		Nodes []ActorNode `json:"nodes"`
+	} `json:"assignees"`
+	Milestone *MilestoneNode `json:"milestone"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// CountNode is the size of a connection, such as the comments of an issue
+type CountNode struct {
+	TotalCount int `json:"totalCount"`
+}
+
+// ActorNode represents the user behind an issue or event; it is null for deleted accounts
+type ActorNode struct {
+	Login string `json:"login"`
+}
+
+// MilestoneNode represents the milestone of an issue
+type MilestoneNode struct {
+	Title string     `json:"title"`
+	DueOn *time.Time `json:"dueOn"`
+	State string     `json:"state"`
+}
+
+// TimelineItemNode represents a close, reopen, label or project status event of an issue
+type TimelineItemNode struct {
+	Typename    string     `json:"__typename"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	Actor       *ActorNode `json:"actor"`
+	StateReason string     `json:"stateReason"`
+	Label       *struct {
+		Name string `json:"name"`
+	} `json:"label"`
+	PreviousStatus string `json:"previousStatus"`
+	Status         string `json:"status"`
+	Project        *struct {
+		Title string `json:"title"`
+	} `json:"project"`
+	WillCloseTarget bool             `json:"willCloseTarget"`
+	Source          *PullRequestNode `json:"source"`
+}
+
+// PullRequestNode represents a pull request linked to an issue. As the source of a
+// cross-reference it may also be an issue, which Typename tells apart.
+type PullRequestNode struct {
+	Typename   string     `json:"__typename"`
+	Number     int        `json:"number"`
+	Title      string     `json:"title"`
+	URL        string     `json:"url"`
+	State      string     `json:"state"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	MergedAt   *time.Time `json:"mergedAt"`
+	Repository struct {
+		NameWithOwner string `json:"nameWithOwner"`
+	} `json:"repository"`
+}
+
+// IssueTimelineNode holds when an issue was opened and by whom, with its timeline events
+// and the pull requests that will close it
+type IssueTimelineNode struct {
+	CreatedAt time.Time
+	Author    string
+	Items     []TimelineItemNode
+	ClosedBy  []PullRequestNode
 }
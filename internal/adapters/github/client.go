@@ -1,6 +1,9 @@
 package github
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/go-github/v58/github"
 	
 	"github-okr-fetcher/internal/domain/entity"
@@ -12,11 +15,14 @@ type GitHubClient struct {
 }
 
 // NewGitHubClient creates a new GitHub client
-func NewGitHubClient(token string, config *entity.Config) *GitHubClient {
-	bridge := NewBridgeClient(token, config)
+func NewGitHubClient(token string, config *entity.Config) (*GitHubClient, error) {
+	bridge, err := NewBridgeClient(token, config)
+	if err != nil {
+		return nil, err
+	}
 	return &GitHubClient{
 		bridge: bridge,
-	}
+	}, nil
 }
 
 // Bridge methods that delegate to the bridge implementation
@@ -25,26 +31,42 @@ func (c *GitHubClient) parseProjectURL(url string) (*entity.ProjectInfo, error)
 	return c.bridge.parseProjectURL(url)
 }
 
-func (c *GitHubClient) fetchProjectIssuesRobust(projectInfo *entity.ProjectInfo) ([]*github.Issue, error) {
-	return c.bridge.fetchProjectIssuesRobust(projectInfo)
+func (c *GitHubClient) fetchProjectIssuesRobust(ctx context.Context, projectInfo *entity.ProjectInfo, keep func(ItemNode) bool) ([]ItemNode, error) {
+	return c.bridge.fetchProjectIssuesRobust(ctx, projectInfo, keep)
+}
+
+func (c *GitHubClient) fetchProjectView(ctx context.Context, projectInfo *entity.ProjectInfo) (*ProjectViewNode, error) {
+	return c.bridge.fetchProjectView(ctx, projectInfo)
+}
+
+func (c *GitHubClient) fetchProjectTitle(ctx context.Context, projectInfo *entity.ProjectInfo) (string, error) {
+	return c.bridge.fetchProjectTitle(ctx, projectInfo)
+}
+
+func (c *GitHubClient) fetchRateLimits(ctx context.Context) (*github.RateLimits, error) {
+	return c.bridge.fetchRateLimits(ctx)
+}
+
+func (c *GitHubClient) fetchIssuesBySearchQuery(ctx context.Context, scope, query string) (*IssueSearchResult, error) {
+	return c.bridge.fetchIssuesBySearchQuery(ctx, scope, query)
 }
 
-func (c *GitHubClient) fetchIssuesBySearchQuery(owner, repo, query string) ([]*github.Issue, error) {
-	return c.bridge.fetchIssuesBySearchQuery(owner, repo, query)
+func (c *GitHubClient) fetchIssueComments(ctx context.Context, ref entity.IssueRef, updatedAt time.Time, commentCount int) ([]*github.IssueComment, error) {
+	return c.bridge.fetchIssueComments(ctx, ref, updatedAt, commentCount)
 }
 
-func (c *GitHubClient) fetchIssueComments(owner, repo string, issueNumber int) ([]*github.IssueComment, error) {
-	return c.bridge.fetchIssueComments(owner, repo, issueNumber)
+func (c *GitHubClient) fetchIssueTimeline(ctx context.Context, ref entity.IssueRef, updatedAt time.Time) (*IssueTimelineNode, error) {
+	return c.bridge.fetchIssueTimeline(ctx, ref, updatedAt)
 }
 
-func (c *GitHubClient) findParentIssueFromRelationships(owner, repo string, issueNumber int) (int, error) {
-	return c.bridge.findParentIssueFromRelationships(owner, repo, issueNumber)
+func (c *GitHubClient) findParentIssue(ctx context.Context, ref entity.IssueRef) (*LinkedIssueNode, error) {
+	return c.bridge.findParentIssue(ctx, ref)
 }
 
-func (c *GitHubClient) testBasicAccess(org string) error {
-	return c.bridge.testBasicAccess(org)
+func (c *GitHubClient) testBasicAccess(ctx context.Context, org string) error {
+	return c.bridge.testBasicAccess(ctx, org)
 }
 
-func (c *GitHubClient) listOrganizationProjects(org string) error {
-	return c.bridge.listOrganizationProjects(org)
+func (c *GitHubClient) listOrganizationProjects(ctx context.Context, org string) ([]ProjectListNode, error) {
+	return c.bridge.listOrganizationProjects(ctx, org)
 }
\ No newline at end of file
@@ -0,0 +1,252 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+)
+
+// Backoff settings for retried requests
+const (
+	retryBaseDelay = time.Second
+	retryMaxDelay  = 30 * time.Second
+	// defaultRetryMaxElapsed bounds how long transient failures are retried
+	defaultRetryMaxElapsed = 2 * time.Minute
+)
+
+// errorClass decides whether and how a failed request is retried
+type errorClass int
+
+const (
+	// errorPermanent will fail the same way again, e.g. a 404 or a malformed query
+	errorPermanent errorClass = iota
+	// errorTransient may succeed on a later attempt, e.g. a 502 or a network timeout
+	errorTransient
+	// errorRateLimited succeeds once the rate limiter has waited out the limit
+	errorRateLimited
+)
+
+// GraphQLError is one entry of the "errors" list in a GraphQL response
+type GraphQLError struct {
+	Type    string        `json:"type,omitempty"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// GraphQLErrors is returned when a GraphQL response reports errors
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, graphqlErr := range e {
+		if graphqlErr.Type != "" {
+			messages = append(messages, fmt.Sprintf("%s: %s", graphqlErr.Type, graphqlErr.Message))
+		} else {
+			messages = append(messages, graphqlErr.Message)
+		}
+	}
+	return "GraphQL errors: " + strings.Join(messages, "; ")
+}
+
+// HTTPStatusError is a non-success status of a request made without go-github
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+	// RetryAfter is the advertised Retry-After, or zero when the response named none
+	RetryAfter time.Duration
+	// RateLimitRemaining is the X-RateLimit-Remaining header, or -1 when it is missing
+	RateLimitRemaining int
+	// Message is the "message" of a JSON error body
+	Message string
+}
+
+func (e *HTTPStatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("unexpected HTTP status %s: %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("unexpected HTTP status %s", e.Status)
+}
+
+// newHTTPStatusError builds an HTTPStatusError from a response
+func newHTTPStatusError(resp *http.Response) *HTTPStatusError {
+	status := resp.Status
+	if status == "" {
+		status = fmt.Sprintf("%d %s", resp.StatusCode, http.StatusText(resp.StatusCode))
+	}
+	statusErr := &HTTPStatusError{
+		StatusCode:         resp.StatusCode,
+		Status:             status,
+		RateLimitRemaining: -1,
+		Message:            responseMessage(resp),
+	}
+	if seconds, ok := headerInt(resp.Header, "Retry-After"); ok {
+		statusErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	if remaining, ok := headerInt(resp.Header, "X-RateLimit-Remaining"); ok {
+		statusErr.RateLimitRemaining = remaining
+	}
+	return statusErr
+}
+
+// maxErrorBodySize bounds how much of an error response is read for its message
+const maxErrorBodySize = 64 << 10
+
+// responseMessage returns the "message" of a JSON error body, leaving the body readable
+func responseMessage(resp *http.Response) string {
+	if resp.Body == nil {
+		return ""
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return ""
+	}
+	return body.Message
+}
+
+// RequestError reports the final cause of a request that could not be completed
+type RequestError struct {
+	// Cause describes the failure in a few words, e.g. "HTTP 404" or "secondary rate limit"
+	Cause    string
+	Attempts int
+	Err      error
+}
+
+func (e *RequestError) Error() string {
+	if e.Attempts > 1 {
+		return fmt.Sprintf("%s, giving up after %d attempts: %v", e.Cause, e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.Cause, e.Err)
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// ScopeError reports a classic token that lacks scopes the tool needs
+type ScopeError struct {
+	Missing []string
+	Granted []string
+}
+
+func (e *ScopeError) Error() string {
+	granted := strings.Join(e.Granted, ", ")
+	if granted == "" {
+		granted = "none"
+	}
+	return fmt.Sprintf("GitHub token is missing the %s scope(s) (granted: %s); create a token with %s or run `gh auth refresh -s %s`",
+		strings.Join(e.Missing, ", "), granted, strings.Join(requiredScopes, ", "), strings.Join(e.Missing, ","))
+}
+
+// classifyError decides whether a failed request is worth retrying and names the cause.
+// 5xx responses and network failures are transient; other 4xx responses are permanent.
+func classifyError(err error) (errorClass, string) {
+	var rateLimitErr *github.RateLimitError
+	var abuseErr *github.AbuseRateLimitError
+	var acceptedErr *github.AcceptedError
+	var responseErr *github.ErrorResponse
+	var statusErr *HTTPStatusError
+	var graphqlErrs GraphQLErrors
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return errorPermanent, "cancelled"
+	case errors.As(err, &rateLimitErr):
+		return errorRateLimited, "rate limit exhausted"
+	case errors.As(err, &abuseErr):
+		return errorRateLimited, "secondary rate limit"
+	case errors.As(err, &acceptedErr):
+		return errorTransient, "GitHub is still preparing the data"
+	case errors.As(err, &responseErr) && responseErr.Response != nil:
+		return classifyStatus(responseErr.Response.StatusCode)
+	case errors.As(err, &statusErr):
+		return classifyStatusError(statusErr)
+	case errors.As(err, &graphqlErrs):
+		return classifyGraphQLErrors(graphqlErrs)
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return errorTransient, "network timeout"
+	case errors.As(err, &netErr), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return errorTransient, "network error"
+	}
+	return errorPermanent, "request failed"
+}
+
+// classifyStatus classifies an HTTP status code
+func classifyStatus(code int) (errorClass, string) {
+	cause := fmt.Sprintf("HTTP %d %s", code, http.StatusText(code))
+	switch {
+	case code == http.StatusTooManyRequests:
+		return errorRateLimited, cause
+	case code >= 500:
+		return errorTransient, cause
+	}
+	return errorPermanent, cause
+}
+
+// classifyStatusError classifies a status error, telling rate limits apart from other 403s:
+// GitHub answers both secondary and exhausted primary rate limits with 403
+func classifyStatusError(statusErr *HTTPStatusError) (errorClass, string) {
+	if statusErr.StatusCode == http.StatusForbidden {
+		switch {
+		case statusErr.RetryAfter > 0:
+			return errorRateLimited, "secondary rate limit"
+		case statusErr.RateLimitRemaining == 0:
+			return errorRateLimited, "rate limit exhausted"
+		case isSecondaryRateLimitMessage(statusErr.Message):
+			return errorRateLimited, "secondary rate limit"
+		}
+	}
+	return classifyStatus(statusErr.StatusCode)
+}
+
+// isSecondaryRateLimitMessage tells whether an error message reports a secondary rate limit
+func isSecondaryRateLimitMessage(message string) bool {
+	message = strings.ToLower(message)
+	return strings.Contains(message, "secondary rate limit") || strings.Contains(message, "abuse detection")
+}
+
+// classifyGraphQLErrors classifies the errors of a GraphQL response by their type
+func classifyGraphQLErrors(errs GraphQLErrors) (errorClass, string) {
+	for _, graphqlErr := range errs {
+		switch graphqlErr.Type {
+		case "RATE_LIMITED":
+			return errorRateLimited, "GraphQL rate limit"
+		case "":
+			// Query timeouts come back without a type
+			if strings.Contains(strings.ToLower(graphqlErr.Message), "timeout") {
+				return errorTransient, "GraphQL query timeout"
+			}
+		}
+	}
+	if errs[0].Type != "" {
+		return errorPermanent, "GraphQL " + errs[0].Type
+	}
+	return errorPermanent, "GraphQL error"
+}
+
+// backoffDelay returns a randomized exponential delay before the given retry ("full jitter")
+func backoffDelay(retry int) time.Duration {
+	ceiling := retryBaseDelay << uint(retry)
+	if ceiling <= 0 || ceiling > retryMaxDelay {
+		ceiling = retryMaxDelay
+	}
+	return retryBaseDelay/2 + time.Duration(rand.Int63n(int64(ceiling)))
+}
@@ -0,0 +1,144 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+
+	"github-okr-fetcher/internal/adapters/github/githubtest"
+	"github-okr-fetcher/internal/domain/entity"
+)
+
+// timeoutError is a net.Error that timed out
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// statusResponse builds a response with the given status, headers and body
+func statusResponse(code int, header map[string]string, body string) *http.Response {
+	resp := &http.Response{StatusCode: code, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+	for name, value := range header {
+		resp.Header.Set(name, value)
+	}
+	return resp
+}
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantClass errorClass
+		wantCause string
+	}{
+		{"cancelled", fmt.Errorf("rate limit error: %w", context.Canceled), errorPermanent, "cancelled"},
+		{"deadline", context.DeadlineExceeded, errorPermanent, "cancelled"},
+		{"primary rate limit", &github.RateLimitError{}, errorRateLimited, "rate limit exhausted"},
+		{"secondary rate limit", &github.AbuseRateLimitError{}, errorRateLimited, "secondary rate limit"},
+		{"accepted", &github.AcceptedError{}, errorTransient, "GitHub is still preparing the data"},
+		{"REST 404", &github.ErrorResponse{Response: statusResponse(http.StatusNotFound, nil, "")}, errorPermanent, "HTTP 404 Not Found"},
+		{"REST 502", &github.ErrorResponse{Response: statusResponse(http.StatusBadGateway, nil, "")}, errorTransient, "HTTP 502 Bad Gateway"},
+		{"429", newHTTPStatusError(statusResponse(http.StatusTooManyRequests, nil, "")), errorRateLimited, "HTTP 429 Too Many Requests"},
+		{"403 forbidden", newHTTPStatusError(statusResponse(http.StatusForbidden,
+			map[string]string{"X-RateLimit-Remaining": "4000"}, `{"message":"Resource not accessible by integration"}`)),
+			errorPermanent, "HTTP 403 Forbidden"},
+		{"403 with Retry-After", newHTTPStatusError(statusResponse(http.StatusForbidden,
+			map[string]string{"Retry-After": "30"}, "")), errorRateLimited, "secondary rate limit"},
+		{"403 with quota exhausted", newHTTPStatusError(statusResponse(http.StatusForbidden,
+			map[string]string{"X-RateLimit-Remaining": "0"}, "")), errorRateLimited, "rate limit exhausted"},
+		{"403 secondary limit message", newHTTPStatusError(statusResponse(http.StatusForbidden,
+			map[string]string{"X-RateLimit-Remaining": "4000"}, `{"message":"You have exceeded a secondary rate limit."}`)),
+			errorRateLimited, "secondary rate limit"},
+		{"500", fmt.Errorf("wrapped: %w", newHTTPStatusError(statusResponse(http.StatusInternalServerError, nil, ""))),
+			errorTransient, "HTTP 500 Internal Server Error"},
+		{"GraphQL rate limit", GraphQLErrors{{Type: "RATE_LIMITED", Message: "API rate limit exceeded"}}, errorRateLimited, "GraphQL rate limit"},
+		{"GraphQL timeout", GraphQLErrors{{Message: "Something went wrong: timeout"}}, errorTransient, "GraphQL query timeout"},
+		{"GraphQL not found", GraphQLErrors{{Type: "NOT_FOUND", Message: "Could not resolve"}}, errorPermanent, "GraphQL NOT_FOUND"},
+		{"GraphQL untyped", GraphQLErrors{{Message: "Parse error"}}, errorPermanent, "GraphQL error"},
+		{"network timeout", fmt.Errorf("error executing request: %w", timeoutError{}), errorTransient, "network timeout"},
+		{"unexpected EOF", io.ErrUnexpectedEOF, errorTransient, "network error"},
+		{"other", fmt.Errorf("boom"), errorPermanent, "request failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class, cause := classifyError(tt.err)
+			if class != tt.wantClass || cause != tt.wantCause {
+				t.Errorf("classifyError = (%d, %q), want (%d, %q)", class, cause, tt.wantClass, tt.wantCause)
+			}
+		})
+	}
+}
+
+func TestHTTPStatusErrorKeepsBodyReadable(t *testing.T) {
+	resp := statusResponse(http.StatusForbidden, nil, `{"message":"Must have admin rights"}`)
+
+	err := newHTTPStatusError(resp)
+	if err.Message != "Must have admin rights" || err.RateLimitRemaining != -1 || err.RetryAfter != 0 {
+		t.Errorf("got %+v", err)
+	}
+	if body, _ := io.ReadAll(resp.Body); string(body) != `{"message":"Must have admin rights"}` {
+		t.Errorf("body after reading the message = %q", body)
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		retry    int
+		min, max time.Duration
+	}{
+		{0, retryBaseDelay / 2, retryBaseDelay/2 + retryBaseDelay},
+		{1, retryBaseDelay / 2, retryBaseDelay/2 + 2*retryBaseDelay},
+		{3, retryBaseDelay / 2, retryBaseDelay/2 + 8*retryBaseDelay},
+		{10, retryBaseDelay / 2, retryBaseDelay/2 + retryMaxDelay},
+		// Shifting this far overflows; the delay stays capped
+		{70, retryBaseDelay / 2, retryBaseDelay/2 + retryMaxDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.retry), func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if delay := backoffDelay(tt.retry); delay < tt.min || delay >= tt.max {
+					t.Fatalf("backoffDelay(%d) = %v, want within [%v, %v)", tt.retry, delay, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestGraphQLRetriesRateLimitedForbidden(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(githubtest.Issue{Ref: "acme/okrs#1", Title: "Objective"})
+
+	var failed int32
+	server.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path != "/api/graphql" || atomic.AddInt32(&failed, 1) > 1 {
+			return false
+		}
+		// An exhausted quota that has already reset, so the limiter does not hold the test up
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Unix()))
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"message":"API rate limit exceeded"}`)
+		return true
+	}
+	repo := newTestRepository(t, server, nil)
+
+	if _, err := repo.FindParentIssue(context.Background(), entity.IssueRef{Owner: "acme", Repo: "okrs", Number: 1}); err != nil {
+		t.Fatalf("FindParentIssue: %v", err)
+	}
+	if got := server.CountRequests("POST graphql parent"); got != 1 {
+		t.Errorf("parent lookups served = %d, want 1 after the retry", got)
+	}
+	if stats := repo.client.GetStats(); stats.RetryCount != 1 {
+		t.Errorf("retries = %d, want 1", stats.RetryCount)
+	}
+}
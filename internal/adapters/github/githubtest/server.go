@@ -0,0 +1,1080 @@
+// Package githubtest provides an in-process fake GitHub that serves fixture issues, comments,
+// ProjectV2 boards and search results over the REST and GraphQL APIs the fetcher uses.
+package githubtest
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github-okr-fetcher/internal/domain/entity"
+)
+
+// WebHost is the host of the issue and project URLs the fake server hands out
+const WebHost = "github.com"
+
+// DefaultScopes are the token scopes reported unless a test sets others
+const DefaultScopes = "repo, read:org, read:project"
+
+// Issue is a fixture issue. Ref is "owner/repo#number"; Parent, when set, is the
+// Ref of its sub-issue parent.
+type Issue struct {
+	Ref       string
+	Title     string
+	Body      string
+	State     string // "open" unless set
+	Labels    []string
+	Parent    string
+	Comments  []Comment
+	UpdatedAt time.Time
+	// CreatedAt defaults to 2025-01-01; Author and Events make up the issue's timeline
+	CreatedAt    time.Time
+	Author       string
+	Events       []Event
+	PullRequests []PullRequest
+	Assignees    []string
+	Milestone    *Milestone
+}
+
+// Milestone is a fixture milestone; DueOn is optional
+type Milestone struct {
+	Title string
+	DueOn time.Time
+	State string // "OPEN" unless set
+}
+
+// PullRequest is a fixture pull request that cross-references an issue when it is created.
+// Closes also lists it among the issue's closing references.
+type PullRequest struct {
+	Ref       string // owner/repo#number
+	Title     string
+	State     string // "OPEN" unless set
+	CreatedAt time.Time
+	MergedAt  time.Time
+	Closes    bool
+}
+
+// Event is a fixture timeline event; set the members that matter for Type
+type Event struct {
+	Type        entity.IssueEventType
+	At          time.Time
+	Actor       string
+	Label       string
+	StateReason string // e.g. "COMPLETED"
+	Project     string
+	From, To    string
+}
+
+// eventTypenames maps domain event types to the GraphQL timeline item types
+var eventTypenames = map[entity.IssueEventType]string{
+	entity.EventClosed:               "ClosedEvent",
+	entity.EventReopened:             "ReopenedEvent",
+	entity.EventLabeled:              "LabeledEvent",
+	entity.EventUnlabeled:            "UnlabeledEvent",
+	entity.EventProjectStatusChanged: "ProjectV2ItemStatusChangedEvent",
+}
+
+// Comment is a fixture issue comment. ID defaults to its position in the issue, starting at 1;
+// set it to keep IDs stable when a test deletes comments.
+type Comment struct {
+	ID        int64
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// Project is a fixture ProjectV2 board. Repo is empty for organization projects.
+type Project struct {
+	Owner  string
+	Repo   string
+	Number int
+	Title  string
+	Closed bool
+	Items  []Item
+	Views  []View
+}
+
+// Item places an issue on a project board with its custom field values
+type Item struct {
+	Issue    string
+	Archived bool
+	Fields   []FieldValue
+}
+
+// FieldValue is a custom field value of a board item; set the member that matches Type
+type FieldValue struct {
+	Name   string
+	Type   entity.ProjectFieldType
+	Text   string
+	Number float64
+	Date   string
+}
+
+// View is a saved view of a project board
+type View struct {
+	Number  int
+	Name    string
+	Layout  string // e.g. "TABLE_LAYOUT"
+	Filter  string
+	SortBy  []SortField
+	GroupBy []string
+}
+
+// SortField is a view's sort on one field
+type SortField struct {
+	Field     string
+	Direction string // "ASC" or "DESC"
+}
+
+// Server is a fake GitHub API. Create it with NewServer, add fixtures, then point a
+// configuration at it with Configure.
+type Server struct {
+	// Scopes is reported in X-OAuth-Scopes; empty means DefaultScopes
+	Scopes string
+	// Intercept, when set, sees every request first and answers it instead of the fake
+	// when it returns true, e.g. to fail or stall a request
+	Intercept func(w http.ResponseWriter, r *http.Request) bool
+	// InstallationTokenTTL is how long App installation tokens last; zero means an hour
+	InstallationTokenTTL time.Duration
+
+	t        testing.TB
+	srv      *httptest.Server
+	mu       sync.Mutex
+	issues   map[string]*Issue
+	order    []string
+	projects []*Project
+	requests []string
+	tokens   int
+	// notModified counts the requests answered with 304 Not Modified
+	notModified int
+}
+
+// NewServer starts a fake GitHub that is shut down when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{t: t, issues: make(map[string]*Issue)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/graphql", s.handleGraphQL)
+	mux.HandleFunc("/api/v3/", s.handleREST)
+	s.srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.Intercept != nil && s.Intercept(w, r) {
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.srv.Close)
+	return s
+}
+
+// URL returns the base URL of the server
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Configure points the GitHub settings of config at the server. Sync state goes to a
+// temporary file and the rate limiter is opened up so tests never wait.
+func (s *Server) Configure(config *entity.Config) {
+	config.GitHub.APIURL = s.srv.URL + "/api/v3/"
+	config.GitHub.GraphQLURL = s.srv.URL + "/api/graphql"
+	config.GitHub.RateLimit = 3600000
+	config.Cache.SyncStateFile = filepath.Join(s.t.TempDir(), "sync-state.json")
+}
+
+// AddIssue adds fixture issues
+func (s *Server) AddIssue(issues ...Issue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range issues {
+		issue := issues[i]
+		if _, err := parseRef(issue.Ref); err != nil {
+			s.t.Fatalf("githubtest: %v", err)
+		}
+		if _, exists := s.issues[issue.Ref]; !exists {
+			s.order = append(s.order, issue.Ref)
+		}
+		s.issues[issue.Ref] = &issue
+	}
+}
+
+// AddProject adds a fixture project board; its items must name issues added with AddIssue
+func (s *Server) AddProject(project Project) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.projects = append(s.projects, &project)
+}
+
+// ProjectURL returns the web URL of a fixture project, with the view when view is not zero
+func (s *Server) ProjectURL(owner, repo string, number, view int) string {
+	url := fmt.Sprintf("https://%s/orgs/%s/projects/%d", WebHost, owner, number)
+	if repo != "" {
+		url = fmt.Sprintf("https://%s/%s/%s/projects/%d", WebHost, owner, repo, number)
+	}
+	if view != 0 {
+		url += fmt.Sprintf("/views/%d", view)
+	}
+	return url
+}
+
+// Requests returns the requests served so far, e.g. "GET /repos/acme/okrs/issues/1/comments"
+// or "POST graphql items"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// CountRequests returns how many served requests start with prefix
+func (s *Server) CountRequests(prefix string) int {
+	count := 0
+	for _, request := range s.Requests() {
+		if strings.HasPrefix(request, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+// NotModified returns how many conditional requests were answered with 304 Not Modified
+func (s *Server) NotModified() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.notModified
+}
+
+func (s *Server) logRequest(request string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, request)
+}
+
+// handleREST serves the REST endpoints: rate limit, organizations, search, comments and
+// App installation tokens
+func (s *Server) handleREST(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+	s.logRequest(r.Method + " " + path)
+
+	scopes := s.Scopes
+	if scopes == "" {
+		scopes = DefaultScopes
+	}
+	w.Header().Set("X-OAuth-Scopes", scopes)
+
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case path == "/rate_limit":
+		reset := time.Now().Add(time.Hour).Unix()
+		rate := map[string]interface{}{"limit": 5000, "remaining": 4999, "reset": reset}
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"resources": map[string]interface{}{"core": rate, "search": rate, "graphql": rate},
+		})
+	case len(parts) == 2 && parts[0] == "orgs":
+		if !s.hasOwner(parts[1]) {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"login": parts[1]})
+	case path == "/search/issues":
+		s.handleSearch(w, r)
+	case len(parts) == 6 && parts[0] == "repos" && parts[3] == "issues" && parts[5] == "comments":
+		s.handleComments(w, r, fmt.Sprintf("%s/%s#%s", parts[1], parts[2], parts[4]))
+	case r.Method == http.MethodPost && len(parts) == 4 && parts[0] == "app" && parts[1] == "installations" && parts[3] == "access_tokens":
+		s.handleInstallationToken(w, parts[2])
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+	}
+}
+
+// handleInstallationToken issues a new token for an App installation on every call,
+// e.g. "ghs_installation-42-1" for the first token of installation 42
+func (s *Server) handleInstallationToken(w http.ResponseWriter, installation string) {
+	ttl := s.InstallationTokenTTL
+	if ttl == 0 {
+		ttl = time.Hour
+	}
+
+	s.mu.Lock()
+	s.tokens++
+	token := fmt.Sprintf("ghs_installation-%s-%d", installation, s.tokens)
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"token":      token,
+		"expires_at": time.Now().Add(ttl).UTC().Format(time.RFC3339),
+	})
+}
+
+// handleSearch answers issue searches. It understands the repo:, org:, user:, label:, state:,
+// is: and created:from..to qualifiers and ignores any other term.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	var matched []map[string]interface{}
+	for _, issue := range s.snapshot() {
+		if matchesSearch(issue, r.URL.Query().Get("q")) {
+			matched = append(matched, s.restIssue(issue))
+		}
+	}
+
+	page, perPage := pagination(r)
+	start, end := pageBounds(len(matched), page, perPage)
+	if end < len(matched) {
+		setNextLink(w, r, page+1)
+	}
+
+	items := matched[start:end]
+	if items == nil {
+		items = []map[string]interface{}{}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total_count":        len(matched),
+		"incomplete_results": false,
+		"items":              items,
+	})
+}
+
+// handleComments lists the comments of an issue, honoring per_page, page and since. Each
+// page carries an ETag, and a request whose If-None-Match matches it gets 304 Not Modified.
+func (s *Server) handleComments(w http.ResponseWriter, r *http.Request, ref string) {
+	issue := s.issue(ref)
+	if issue == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+		return
+	}
+
+	var since time.Time
+	if value := r.URL.Query().Get("since"); value != "" {
+		since, _ = time.Parse(time.RFC3339, value)
+	}
+
+	var comments []map[string]interface{}
+	for i, comment := range issue.Comments {
+		created := comment.CreatedAt
+		if created.IsZero() {
+			created = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour)
+		}
+		if !since.IsZero() && created.Before(since) {
+			continue
+		}
+		id := comment.ID
+		if id == 0 {
+			id = int64(i + 1)
+		}
+		comments = append(comments, map[string]interface{}{
+			"id":         id,
+			"body":       comment.Body,
+			"user":       map[string]interface{}{"login": comment.Author},
+			"created_at": created.Format(time.RFC3339),
+			"updated_at": created.Format(time.RFC3339),
+		})
+	}
+
+	page, perPage := pagination(r)
+	start, end := pageBounds(len(comments), page, perPage)
+	if end < len(comments) {
+		setNextLink(w, r, page+1)
+	}
+	result := comments[start:end]
+	if result == nil {
+		result = []map[string]interface{}{}
+	}
+
+	data, _ := json.Marshal(result)
+	etag := fmt.Sprintf(`"%x"`, sha256.Sum256(data))
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		s.mu.Lock()
+		s.notModified++
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// graphQLRequest is the body of a GraphQL request
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// handleGraphQL answers the fetcher's GraphQL queries, telling them apart by their selections
+func (s *Server) handleGraphQL(w http.ResponseWriter, r *http.Request) {
+	var request graphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+	query := request.Query
+	vars := request.Variables
+
+	switch {
+	case strings.Contains(query, "timelineItems("):
+		s.logRequest("POST graphql timeline")
+		s.answerTimeline(w, vars)
+	case strings.Contains(query, "issue(number: $number)"):
+		s.logRequest("POST graphql parent")
+		s.answerParent(w, vars)
+	case strings.Contains(query, "projectsV2("):
+		s.logRequest("POST graphql projects")
+		s.answerProjects(w, vars)
+	case strings.Contains(query, "view(number: $view)"):
+		s.logRequest("POST graphql view")
+		s.answerProject(w, vars, func(project *Project) map[string]interface{} {
+			return map[string]interface{}{"view": s.graphQLView(project, intVar(vars, "view"))}
+		})
+	case strings.Contains(query, "items(first: $first"):
+		s.logRequest("POST graphql items")
+		s.answerProject(w, vars, func(project *Project) map[string]interface{} {
+			return map[string]interface{}{"items": s.graphQLItems(project, intVar(vars, "first"), vars["cursor"])}
+		})
+	case strings.Contains(query, "projectV2(number: $number) { title }"):
+		s.logRequest("POST graphql title")
+		s.answerProject(w, vars, func(project *Project) map[string]interface{} {
+			return map[string]interface{}{"title": project.Title}
+		})
+	default:
+		s.t.Errorf("githubtest: unexpected GraphQL query:\n%s", query)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"errors": []map[string]string{{"message": "unsupported query"}}})
+	}
+}
+
+// answerProject resolves the project a query names and answers with the selection build makes of it
+func (s *Server) answerProject(w http.ResponseWriter, vars map[string]interface{}, build func(*Project) map[string]interface{}) {
+	owner, _ := vars["owner"].(string)
+	repo, _ := vars["repo"].(string)
+	project := s.project(owner, repo, intVar(vars, "number"))
+	if project == nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data":   nil,
+			"errors": []map[string]string{{"type": "NOT_FOUND", "message": fmt.Sprintf("Could not resolve to a ProjectV2 with the number %d.", intVar(vars, "number"))}},
+		})
+		return
+	}
+
+	container := "organization"
+	if repo != "" {
+		container = "repository"
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{container: map[string]interface{}{"projectV2": build(project)}},
+	})
+}
+
+// answerParent answers the sub-issue parent query
+func (s *Server) answerParent(w http.ResponseWriter, vars map[string]interface{}) {
+	owner, _ := vars["owner"].(string)
+	repo, _ := vars["repo"].(string)
+	issue := s.issue(fmt.Sprintf("%s/%s#%d", owner, repo, intVar(vars, "number")))
+
+	var node interface{}
+	if issue != nil {
+		node = map[string]interface{}{"parent": s.linkedIssue(issue.Parent)}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{"repository": map[string]interface{}{"issue": node}},
+	})
+}
+
+// answerTimeline answers an issue's timeline, paginated by first and cursor
+func (s *Server) answerTimeline(w http.ResponseWriter, vars map[string]interface{}) {
+	owner, _ := vars["owner"].(string)
+	repo, _ := vars["repo"].(string)
+	issue := s.issue(fmt.Sprintf("%s/%s#%d", owner, repo, intVar(vars, "number")))
+	if issue == nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"repository": map[string]interface{}{"issue": nil}},
+		})
+		return
+	}
+
+	nodes := []interface{}{}
+	for _, event := range issue.Events {
+		node := map[string]interface{}{
+			"__typename": eventTypenames[event.Type],
+			"createdAt":  event.At,
+			"actor":      map[string]interface{}{"login": event.Actor},
+		}
+		switch event.Type {
+		case entity.EventClosed:
+			node["stateReason"] = event.StateReason
+		case entity.EventLabeled, entity.EventUnlabeled:
+			node["label"] = map[string]interface{}{"name": event.Label}
+		case entity.EventProjectStatusChanged:
+			node["previousStatus"] = event.From
+			node["status"] = event.To
+			node["project"] = map[string]interface{}{"title": event.Project}
+		}
+		nodes = append(nodes, node)
+	}
+	closedBy := []interface{}{}
+	for _, pr := range issue.PullRequests {
+		nodes = append(nodes, map[string]interface{}{
+			"__typename":      "CrossReferencedEvent",
+			"createdAt":       pr.CreatedAt,
+			"actor":           map[string]interface{}{"login": "dev"},
+			"willCloseTarget": pr.Closes,
+			"source":          s.pullRequestNode(pr),
+		})
+		if pr.Closes {
+			closedBy = append(closedBy, s.pullRequestNode(pr))
+		}
+	}
+
+	var author interface{}
+	if issue.Author != "" {
+		author = map[string]interface{}{"login": issue.Author}
+	}
+	start, end := cursorBounds(len(nodes), intVar(vars, "first"), vars["cursor"])
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{"repository": map[string]interface{}{"issue": map[string]interface{}{
+			"createdAt":                      issueCreatedAt(issue),
+			"author":                         author,
+			"closedByPullRequestsReferences": map[string]interface{}{"nodes": closedBy},
+			"timelineItems": map[string]interface{}{
+				"pageInfo": pageInfo(end, len(nodes)),
+				"nodes":    nodes[start:end],
+			},
+		}}},
+	})
+}
+
+// pullRequestNode renders a pull request the way the LinkedPullRequest fragment selects it
+func (s *Server) pullRequestNode(pr PullRequest) map[string]interface{} {
+	parsed, err := parseRef(pr.Ref)
+	if err != nil {
+		s.t.Errorf("githubtest: %v", err)
+	}
+	state := pr.State
+	if state == "" {
+		state = "OPEN"
+	}
+	var mergedAt interface{}
+	if !pr.MergedAt.IsZero() {
+		mergedAt = pr.MergedAt
+	}
+	return map[string]interface{}{
+		"__typename": "PullRequest",
+		"number":     parsed.Number,
+		"title":      pr.Title,
+		"url":        fmt.Sprintf("https://%s/%s/%s/pull/%d", WebHost, parsed.Owner, parsed.Repo, parsed.Number),
+		"state":      state,
+		"createdAt":  pr.CreatedAt,
+		"mergedAt":   mergedAt,
+		"repository": map[string]interface{}{"nameWithOwner": parsed.Owner + "/" + parsed.Repo},
+	}
+}
+
+// answerProjects answers the organization project list, one project per page of size first
+func (s *Server) answerProjects(w http.ResponseWriter, vars map[string]interface{}) {
+	owner, _ := vars["owner"].(string)
+
+	var nodes []interface{}
+	for _, project := range s.projectSnapshot() {
+		if project.Repo != "" || !strings.EqualFold(project.Owner, owner) {
+			continue
+		}
+		var views []interface{}
+		for _, view := range project.Views {
+			views = append(views, map[string]interface{}{"number": view.Number, "name": view.Name, "layout": view.Layout})
+		}
+		fields := []interface{}{
+			map[string]interface{}{"name": "Title", "dataType": "TITLE"},
+			map[string]interface{}{"name": "Status", "dataType": "SINGLE_SELECT", "options": statusOptions(project)},
+		}
+		nodes = append(nodes, map[string]interface{}{
+			"number": project.Number,
+			"title":  project.Title,
+			"url":    s.ProjectURL(project.Owner, "", project.Number, 0),
+			"closed": project.Closed,
+			"views":  map[string]interface{}{"nodes": views},
+			"fields": map[string]interface{}{"nodes": fields},
+		})
+	}
+
+	start, end := cursorBounds(len(nodes), intVar(vars, "first"), vars["cursor"])
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"data": map[string]interface{}{"organization": map[string]interface{}{"projectsV2": map[string]interface{}{
+			"pageInfo": pageInfo(end, len(nodes)),
+			"nodes":    nodes[start:end],
+		}}},
+	})
+}
+
+// graphQLItems renders a page of project items
+func (s *Server) graphQLItems(project *Project, first int, cursor interface{}) map[string]interface{} {
+	start, end := cursorBounds(len(project.Items), first, cursor)
+
+	nodes := []interface{}{}
+	for _, item := range project.Items[start:end] {
+		issue := s.issue(item.Issue)
+		if issue == nil {
+			s.t.Errorf("githubtest: project %d item names unknown issue %s", project.Number, item.Issue)
+			continue
+		}
+
+		content := s.linkedIssue(item.Issue)
+		content["comments"] = map[string]interface{}{"totalCount": len(issue.Comments)}
+		content["parent"] = s.linkedIssue(issue.Parent)
+
+		fieldValues := []interface{}{
+			map[string]interface{}{"__typename": "ProjectV2ItemFieldTextValue", "text": issue.Title, "field": map[string]interface{}{"name": "Title"}},
+		}
+		for _, field := range item.Fields {
+			fieldValues = append(fieldValues, graphQLFieldValue(field))
+		}
+
+		nodes = append(nodes, map[string]interface{}{
+			"type":        "ISSUE",
+			"isArchived":  item.Archived,
+			"content":     content,
+			"fieldValues": map[string]interface{}{"nodes": fieldValues},
+		})
+	}
+
+	return map[string]interface{}{"pageInfo": pageInfo(end, len(project.Items)), "nodes": nodes}
+}
+
+// graphQLView renders a view definition, or nil when the project has no such view
+func (s *Server) graphQLView(project *Project, number int) interface{} {
+	for _, view := range project.Views {
+		if view.Number != number {
+			continue
+		}
+		var sortBy []interface{}
+		for _, sortField := range view.SortBy {
+			sortBy = append(sortBy, map[string]interface{}{
+				"direction": sortField.Direction,
+				"field":     s.fieldDefinition(project, sortField.Field),
+			})
+		}
+		var groupBy []interface{}
+		for _, name := range view.GroupBy {
+			groupBy = append(groupBy, s.fieldDefinition(project, name))
+		}
+		return map[string]interface{}{
+			"number":        view.Number,
+			"name":          view.Name,
+			"layout":        view.Layout,
+			"filter":        view.Filter,
+			"sortByFields":  map[string]interface{}{"nodes": sortBy},
+			"groupByFields": map[string]interface{}{"nodes": groupBy},
+		}
+	}
+	return nil
+}
+
+// fieldDefinition describes a field; single select options are listed in order of first use
+func (s *Server) fieldDefinition(project *Project, name string) map[string]interface{} {
+	var options []interface{}
+	seen := make(map[string]bool)
+	for _, item := range project.Items {
+		for _, field := range item.Fields {
+			if field.Name == name && field.Type == entity.FieldTypeSingleSelect && !seen[field.Text] {
+				seen[field.Text] = true
+				options = append(options, map[string]interface{}{"name": field.Text})
+			}
+		}
+	}
+	return map[string]interface{}{"name": name, "options": options}
+}
+
+// linkedIssue renders an issue the way the LinkedIssue fragment selects it, or nil
+func (s *Server) linkedIssue(ref string) map[string]interface{} {
+	if ref == "" {
+		return nil
+	}
+	issue := s.issue(ref)
+	if issue == nil {
+		s.t.Errorf("githubtest: unknown issue %s", ref)
+		return nil
+	}
+	parsed, _ := parseRef(ref)
+
+	labels := []interface{}{}
+	for _, label := range issue.Labels {
+		labels = append(labels, map[string]interface{}{"name": label})
+	}
+	return map[string]interface{}{
+		"number": parsed.Number,
+		"title":  issue.Title,
+		"url":    issueURL(parsed),
+		"state":  strings.ToUpper(issueState(issue)),
+		"body":   issue.Body,
+		"repository": map[string]interface{}{
+			"owner": map[string]interface{}{"login": parsed.Owner},
+			"name":  parsed.Repo,
+		},
+		"labels":    map[string]interface{}{"nodes": labels},
+		"author":    author(issue),
+		"assignees": map[string]interface{}{"nodes": assignees(issue)},
+		"milestone": graphQLMilestone(issue.Milestone),
+		"createdAt": issueCreatedAt(issue).Format(time.RFC3339),
+		"updatedAt": issueUpdatedAt(issue).Format(time.RFC3339),
+	}
+}
+
+// restIssue renders an issue the way the REST API returns it
+func (s *Server) restIssue(issue *Issue) map[string]interface{} {
+	parsed, _ := parseRef(issue.Ref)
+
+	labels := []interface{}{}
+	for _, label := range issue.Labels {
+		labels = append(labels, map[string]interface{}{"name": label})
+	}
+	return map[string]interface{}{
+		"id":             issueID(parsed),
+		"number":         parsed.Number,
+		"title":          issue.Title,
+		"body":           issue.Body,
+		"state":          issueState(issue),
+		"labels":         labels,
+		"html_url":       issueURL(parsed),
+		"repository_url": fmt.Sprintf("%s/api/v3/repos/%s/%s", s.srv.URL, parsed.Owner, parsed.Repo),
+		"user":           author(issue),
+		"assignees":      assignees(issue),
+		"milestone":      restMilestone(issue.Milestone),
+		"comments":       len(issue.Comments),
+		"created_at":     issueCreatedAt(issue).Format(time.RFC3339),
+		"updated_at":     issueUpdatedAt(issue).Format(time.RFC3339),
+	}
+}
+
+// author renders the user who opened an issue, or nil when the fixture names none
+func author(issue *Issue) interface{} {
+	if issue.Author == "" {
+		return nil
+	}
+	return map[string]interface{}{"login": issue.Author}
+}
+
+// assignees renders the users assigned to an issue
+func assignees(issue *Issue) []interface{} {
+	users := []interface{}{}
+	for _, login := range issue.Assignees {
+		users = append(users, map[string]interface{}{"login": login})
+	}
+	return users
+}
+
+// graphQLMilestone renders a milestone the way GraphQL returns it, or nil
+func graphQLMilestone(milestone *Milestone) interface{} {
+	if milestone == nil {
+		return nil
+	}
+	node := map[string]interface{}{"title": milestone.Title, "state": milestoneState(milestone), "dueOn": nil}
+	if !milestone.DueOn.IsZero() {
+		node["dueOn"] = milestone.DueOn.Format(time.RFC3339)
+	}
+	return node
+}
+
+// restMilestone renders a milestone the way the REST API returns it, or nil
+func restMilestone(milestone *Milestone) interface{} {
+	if milestone == nil {
+		return nil
+	}
+	node := map[string]interface{}{"title": milestone.Title, "state": strings.ToLower(milestoneState(milestone)), "due_on": nil}
+	if !milestone.DueOn.IsZero() {
+		node["due_on"] = milestone.DueOn.Format(time.RFC3339)
+	}
+	return node
+}
+
+func milestoneState(milestone *Milestone) string {
+	if milestone.State == "" {
+		return "OPEN"
+	}
+	return strings.ToUpper(milestone.State)
+}
+
+// graphQLFieldValue renders a custom field value with its GraphQL type name
+func graphQLFieldValue(field FieldValue) map[string]interface{} {
+	value := map[string]interface{}{"field": map[string]interface{}{"name": field.Name}}
+	switch field.Type {
+	case entity.FieldTypeNumber:
+		value["__typename"] = "ProjectV2ItemFieldNumberValue"
+		value["number"] = field.Number
+	case entity.FieldTypeDate:
+		value["__typename"] = "ProjectV2ItemFieldDateValue"
+		value["date"] = field.Date
+	case entity.FieldTypeSingleSelect:
+		value["__typename"] = "ProjectV2ItemFieldSingleSelectValue"
+		value["name"] = field.Text
+	case entity.FieldTypeIteration:
+		value["__typename"] = "ProjectV2ItemFieldIterationValue"
+		value["title"] = field.Text
+		value["startDate"] = field.Date
+		value["duration"] = 14
+	default:
+		value["__typename"] = "ProjectV2ItemFieldTextValue"
+		value["text"] = field.Text
+	}
+	return value
+}
+
+// matchesSearch reports whether an issue matches every qualifier of a search query
+func matchesSearch(issue *Issue, query string) bool {
+	parsed, _ := parseRef(issue.Ref)
+	for _, term := range searchTerms(query) {
+		name, value, found := strings.Cut(term, ":")
+		if !found {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+
+		switch strings.ToLower(name) {
+		case "repo":
+			if !strings.EqualFold(value, parsed.Owner+"/"+parsed.Repo) {
+				return false
+			}
+		case "org", "user":
+			if !strings.EqualFold(value, parsed.Owner) {
+				return false
+			}
+		case "label":
+			if !hasLabel(issue, value) {
+				return false
+			}
+		case "state":
+			if issueState(issue) != strings.ToLower(value) {
+				return false
+			}
+		case "is":
+			if (value == "open" || value == "closed") && issueState(issue) != value {
+				return false
+			}
+		case "created":
+			from, to, _ := strings.Cut(value, "..")
+			created := issueCreatedAt(issue)
+			if start, err := time.Parse(time.RFC3339, from); err == nil && created.Before(start) {
+				return false
+			}
+			if end, err := time.Parse(time.RFC3339, to); err == nil && created.After(end) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// searchTerms splits a search query on spaces outside quotes
+func searchTerms(query string) []string {
+	var terms []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ' ' && !quoted:
+			if current.Len() > 0 {
+				terms = append(terms, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		terms = append(terms, current.String())
+	}
+	return terms
+}
+
+func hasLabel(issue *Issue, label string) bool {
+	for _, l := range issue.Labels {
+		if strings.EqualFold(l, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusOptions lists the Status options used on a project's items
+func statusOptions(project *Project) []interface{} {
+	var options []interface{}
+	seen := make(map[string]bool)
+	for _, item := range project.Items {
+		for _, field := range item.Fields {
+			if field.Name == "Status" && !seen[field.Text] {
+				seen[field.Text] = true
+				options = append(options, map[string]interface{}{"name": field.Text})
+			}
+		}
+	}
+	return options
+}
+
+func (s *Server) issue(ref string) *Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.issues[ref]
+}
+
+// snapshot returns the issues in the order they were added
+func (s *Server) snapshot() []*Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issues := make([]*Issue, 0, len(s.order))
+	for _, ref := range s.order {
+		issues = append(issues, s.issues[ref])
+	}
+	return issues
+}
+
+func (s *Server) projectSnapshot() []*Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	projects := append([]*Project(nil), s.projects...)
+	sort.SliceStable(projects, func(i, j int) bool { return projects[i].Number < projects[j].Number })
+	return projects
+}
+
+func (s *Server) project(owner, repo string, number int) *Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, project := range s.projects {
+		if strings.EqualFold(project.Owner, owner) && strings.EqualFold(project.Repo, repo) && project.Number == number {
+			return project
+		}
+	}
+	return nil
+}
+
+// hasOwner reports whether any fixture belongs to the organization or user
+func (s *Server) hasOwner(owner string) bool {
+	for _, issue := range s.snapshot() {
+		if parsed, _ := parseRef(issue.Ref); strings.EqualFold(parsed.Owner, owner) {
+			return true
+		}
+	}
+	for _, project := range s.projectSnapshot() {
+		if strings.EqualFold(project.Owner, owner) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRef parses an "owner/repo#number" fixture reference
+func parseRef(ref string) (entity.IssueRef, error) {
+	parsed, err := entity.ParseIssueRef(ref, entity.IssueRef{})
+	if err != nil || parsed.Owner == "" || parsed.Repo == "" {
+		return entity.IssueRef{}, fmt.Errorf("invalid issue reference %q, expected owner/repo#number", ref)
+	}
+	return parsed, nil
+}
+
+func issueURL(ref entity.IssueRef) string {
+	return fmt.Sprintf("https://%s/%s/%s/issues/%d", WebHost, ref.Owner, ref.Repo, ref.Number)
+}
+
+// issueID derives a stable numeric ID, which search uses to drop duplicates
+func issueID(ref entity.IssueRef) int64 {
+	var hash int64
+	for _, r := range ref.Owner + "/" + ref.Repo {
+		hash = hash*31 + int64(r)
+	}
+	return (hash%1000000)*100000 + int64(ref.Number)
+}
+
+func issueState(issue *Issue) string {
+	if issue.State == "" {
+		return "open"
+	}
+	return strings.ToLower(issue.State)
+}
+
+func issueUpdatedAt(issue *Issue) time.Time {
+	if issue.UpdatedAt.IsZero() {
+		return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return issue.UpdatedAt
+}
+
+func issueCreatedAt(issue *Issue) time.Time {
+	if issue.CreatedAt.IsZero() {
+		return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return issue.CreatedAt
+}
+
+// intVar reads a numeric GraphQL variable, which JSON decodes as float64
+func intVar(vars map[string]interface{}, name string) int {
+	if value, ok := vars[name].(float64); ok {
+		return int(value)
+	}
+	return 0
+}
+
+// cursorBounds turns a GraphQL page size and cursor (the offset as a string) into slice bounds
+func cursorBounds(total, first int, cursor interface{}) (int, int) {
+	start := 0
+	if value, ok := cursor.(string); ok {
+		start, _ = strconv.Atoi(value)
+	}
+	if first <= 0 {
+		first = 100
+	}
+	if start > total {
+		start = total
+	}
+	end := start + first
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+func pageInfo(end, total int) map[string]interface{} {
+	return map[string]interface{}{"hasNextPage": end < total, "endCursor": strconv.Itoa(end)}
+}
+
+// pagination reads the REST page and per_page parameters
+func pagination(r *http.Request) (int, int) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+	if perPage < 1 {
+		perPage = 30
+	}
+	return page, perPage
+}
+
+func pageBounds(total, page, perPage int) (int, int) {
+	start := (page - 1) * perPage
+	if start > total {
+		start = total
+	}
+	end := start + perPage
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// setNextLink adds the Link header go-github reads the next page from
+func setNextLink(w http.ResponseWriter, r *http.Request, page int) {
+	query := r.URL.Query()
+	query.Set("page", strconv.Itoa(page))
+	next := *r.URL
+	next.RawQuery = query.Encode()
+	w.Header().Set("Link", fmt.Sprintf(`<http://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
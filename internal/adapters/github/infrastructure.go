@@ -1,11 +1,18 @@
 package github
 
 import (
-	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
-	"golang.org/x/time/rate"
+	"github-okr-fetcher/internal/domain/entity"
 )
 
 // ClientStats tracks API usage statistics
@@ -22,30 +29,75 @@ type ClientStats struct {
 	mu             sync.RWMutex
 }
 
-// APICache provides simple in-memory caching for API responses
+// APICache caches API responses in memory and, when it has a directory, on disk so
+// that they survive between runs
 type APICache struct {
 	data map[string]CacheEntry
+	dir  string
 	mu   sync.RWMutex
 }
 
 // CacheEntry represents a cached API response
 type CacheEntry struct {
-	Data      interface{}
-	ExpiresAt time.Time
+	Key       string          `json:"key"`
+	Data      json.RawMessage `json:"data"`
+	ExpiresAt time.Time       `json:"expires_at"`
 }
 
-// NewAPICache creates a new API cache
+// CacheStats describes the contents of the API cache
+type CacheStats struct {
+	Dir       string
+	Entries   int
+	Expired   int
+	SizeBytes int64
+	// ByKind counts live entries per kind of response (graphql, search, comments)
+	ByKind map[string]int
+}
+
+// NewAPICache creates a new in-memory API cache
 func NewAPICache() *APICache {
 	return &APICache{
 		data: make(map[string]CacheEntry),
 	}
 }
 
+// NewFileCache creates an API cache that persists entries as files in dir
+func NewFileCache(dir string) *APICache {
+	return &APICache{
+		data: make(map[string]CacheEntry),
+		dir:  dir,
+	}
+}
+
+// CacheDir returns the directory of the persistent API cache: the configured one,
+// or a directory under the user cache directory
+func CacheDir(config *entity.Config) (string, error) {
+	if config != nil && config.Cache.Dir != "" {
+		return config.Cache.Dir, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating user cache directory: %v", err)
+	}
+	return filepath.Join(dir, "github-okr-fetcher", "api"), nil
+}
+
 // GetStats returns a copy of the current client statistics
 func (s *ClientStats) GetStats() ClientStats {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return *s
+	// Copy field by field; copying the struct would copy its mutex
+	return ClientStats{
+		APICallsCount:  s.APICallsCount,
+		CacheHitsCount: s.CacheHitsCount,
+		ErrorsCount:    s.ErrorsCount,
+		RetryCount:     s.RetryCount,
+		RateLimitHits:  s.RateLimitHits,
+		ProcessingTime: s.ProcessingTime,
+		LastAPICall:    s.LastAPICall,
+		RemainingQuota: s.RemainingQuota,
+		QuotaResetTime: s.QuotaResetTime,
+	}
 }
 
 // IncrementAPICall safely increments the API call counter
@@ -92,84 +144,238 @@ func (s *ClientStats) UpdateQuota(remaining int, resetTime time.Time) {
 	s.QuotaResetTime = resetTime
 }
 
-// GetFromCache retrieves an item from the cache
-func (c *APICache) GetFromCache(key string) (interface{}, bool) {
+// GetFromCache decodes a cached item into out and reports whether a live entry was found
+func (c *APICache) GetFromCache(key string, out interface{}) bool {
 	if c == nil {
-		return nil, false
+		return false
 	}
 
 	c.mu.RLock()
-	defer c.mu.RUnlock()
-
 	entry, exists := c.data[key]
+	c.mu.RUnlock()
+
+	// Read the file without holding the lock, so a slow disk does not stall other lookups
+	if !exists && c.dir != "" {
+		diskEntry, err := c.readEntry(c.entryPath(key))
+		if err == nil && diskEntry.Key == key {
+			c.mu.Lock()
+			if current, stored := c.data[key]; stored {
+				// Another goroutine stored the entry meanwhile; it is at least as recent
+				entry = current
+			} else {
+				entry = *diskEntry
+				c.data[key] = entry
+			}
+			c.mu.Unlock()
+			exists = true
+		}
+	}
 	if !exists {
-		return nil, false
+		return false
 	}
 
 	if time.Now().After(entry.ExpiresAt) {
 		// Entry expired, remove it
-		delete(c.data, key)
-		return nil, false
+		c.removeEntry(key, entry)
+		return false
 	}
 
-	return entry.Data, true
+	if err := json.Unmarshal(entry.Data, out); err != nil {
+		log.Printf("⚠️  Dropping unreadable cache entry %s: %v", key, err)
+		c.removeEntry(key, entry)
+		return false
+	}
+	return true
 }
 
 // SetCache stores an item in the cache with TTL
 func (c *APICache) SetCache(key string, data interface{}, ttl time.Duration) {
-	if c == nil {
+	if c == nil || ttl <= 0 {
+		return
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("⚠️  Not caching %s: %v", key, err)
 		return
 	}
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.data[key] = CacheEntry{
-		Data:      data,
+	entry := CacheEntry{
+		Key:       key,
+		Data:      encoded,
 		ExpiresAt: time.Now().Add(ttl),
 	}
+	c.data[key] = entry
+
+	if c.dir != "" {
+		if err := c.writeEntry(entry); err != nil {
+			log.Printf("⚠️  Could not persist cache entry %s: %v", key, err)
+		}
+	}
+}
+
+// ClearExpired removes expired entries from the cache and returns how many were removed
+func (c *APICache) ClearExpired() int {
+	return c.clear(true)
 }
 
-// ClearExpired removes expired entries from the cache
-func (c *APICache) ClearExpired() {
+// Clear removes every entry from the cache and returns how many were removed
+func (c *APICache) Clear() int {
+	return c.clear(false)
+}
+
+// Stats reports the number and size of the entries in the cache
+func (c *APICache) Stats() (*CacheStats, error) {
+	stats := &CacheStats{ByKind: make(map[string]int)}
 	if c == nil {
-		return
+		return stats, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats.Dir = c.dir
+	now := time.Now()
+	count := func(entry CacheEntry, size int64) {
+		stats.SizeBytes += size
+		if now.After(entry.ExpiresAt) {
+			stats.Expired++
+			return
+		}
+		stats.Entries++
+		stats.ByKind[cacheKind(entry.Key)]++
+	}
+
+	if c.dir == "" {
+		for _, entry := range c.data {
+			count(entry, int64(len(entry.Data)))
+		}
+		return stats, nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing cache directory: %v", err)
+	}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		entry, err := c.readEntry(path)
+		if err != nil {
+			// Unreadable files are counted as expired so that clearing removes them
+			stats.Expired++
+			stats.SizeBytes += info.Size()
+			continue
+		}
+		count(*entry, info.Size())
+	}
+	return stats, nil
+}
+
+// clear removes all entries, or only the expired ones, from memory and disk. Caller must not hold the lock.
+func (c *APICache) clear(expiredOnly bool) int {
+	if c == nil {
+		return 0
 	}
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	now := time.Now()
+	removed := 0
 	for key, entry := range c.data {
-		if now.After(entry.ExpiresAt) {
+		if !expiredOnly || now.After(entry.ExpiresAt) {
 			delete(c.data, key)
+			if c.dir == "" {
+				removed++
+			}
 		}
 	}
+
+	if c.dir == "" {
+		return removed
+	}
+
+	paths, _ := filepath.Glob(filepath.Join(c.dir, "*.json"))
+	for _, path := range paths {
+		if expiredOnly {
+			entry, err := c.readEntry(path)
+			if err == nil && !now.After(entry.ExpiresAt) {
+				continue
+			}
+		}
+		if err := os.Remove(path); err == nil {
+			removed++
+		}
+	}
+	return removed
 }
 
-// RateLimiter wraps the rate limiter with additional functionality
-type RateLimiter struct {
-	limiter *rate.Limiter
+// removeEntry drops entry unless SetCache replaced it since it was looked up. Caller must not hold the lock.
+func (c *APICache) removeEntry(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if current, stored := c.data[key]; stored && !current.ExpiresAt.Equal(entry.ExpiresAt) {
+		return
+	}
+	c.remove(key)
 }
 
-// NewRateLimiter creates a new rate limiter
-func NewRateLimiter(requestsPerHour int) *RateLimiter {
-	if requestsPerHour <= 0 {
-		requestsPerHour = 5000 // Default GitHub rate limit
+// remove drops an entry from memory and disk. Caller must hold the lock.
+func (c *APICache) remove(key string) {
+	delete(c.data, key)
+	if c.dir != "" {
+		os.Remove(c.entryPath(key))
 	}
+}
 
-	// Convert to requests per second with burst capacity
-	rps := rate.Limit(float64(requestsPerHour) / 3600)
-	limiter := rate.NewLimiter(rps, 10)
+// entryPath returns the file that stores the entry for key
+func (c *APICache) entryPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// readEntry reads a cache entry file
+func (c *APICache) readEntry(path string) (*CacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
 
-	return &RateLimiter{
-		limiter: limiter,
+// writeEntry writes a cache entry file, going through a temporary file so readers never see a partial entry
+func (c *APICache) writeEntry(entry CacheEntry) error {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
 	}
+	path := c.entryPath(entry.Key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
 }
 
-// Wait waits for the rate limiter to allow the request
-func (r *RateLimiter) Wait(ctx context.Context) error {
-	return r.limiter.Wait(ctx)
+// cacheKind returns the kind of response a cache key belongs to, e.g. "graphql" or "comments"
+func cacheKind(key string) string {
+	if i := strings.Index(key, ":"); i > 0 {
+		return key[:i]
+	}
+	return key
 }
 
 // Simple hash function for cache keys
@@ -0,0 +1,141 @@
+package github
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// storeExpired writes an entry that expired a minute ago straight to the cache directory
+func storeExpired(t *testing.T, cache *APICache, key string) {
+	t.Helper()
+	if err := cache.writeEntry(CacheEntry{Key: key, Data: []byte(`"stale"`), ExpiresAt: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatalf("writeEntry: %v", err)
+	}
+}
+
+func TestFileCacheSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	NewFileCache(dir).SetCache("comments:acme/okrs#1", []string{"first", "second"}, time.Hour)
+
+	// A new process starts with an empty memory
+	var got []string
+	if !NewFileCache(dir).GetFromCache("comments:acme/okrs#1", &got) || fmt.Sprint(got) != "[first second]" {
+		t.Errorf("cached comments = %v, want [first second]", got)
+	}
+	if NewFileCache(dir).GetFromCache("comments:acme/okrs#2", &got) {
+		t.Error("found an entry that was never stored")
+	}
+	// Entries without a TTL are not worth storing
+	NewFileCache(dir).SetCache("graphql:uncached", "value", 0)
+	if NewFileCache(dir).GetFromCache("graphql:uncached", &got) {
+		t.Error("found an entry stored without a TTL")
+	}
+}
+
+func TestFileCacheExpiresEntries(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewFileCache(dir)
+	storeExpired(t, cache, "graphql:expired")
+
+	var got string
+	if cache.GetFromCache("graphql:expired", &got) {
+		t.Errorf("expired entry returned %q", got)
+	}
+	if _, err := os.Stat(cache.entryPath("graphql:expired")); !os.IsNotExist(err) {
+		t.Errorf("expired entry file still exists: %v", err)
+	}
+
+	// An entry expires in memory too
+	cache.SetCache("graphql:short", "value", 20*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	if cache.GetFromCache("graphql:short", &got) {
+		t.Error("entry outlived its TTL")
+	}
+}
+
+func TestFileCacheDropsUnreadableEntries(t *testing.T) {
+	cache := NewFileCache(t.TempDir())
+	cache.SetCache("search:okr", map[string]int{"total": 3}, time.Hour)
+
+	// The entry no longer decodes into what the caller expects
+	var got []string
+	if cache.GetFromCache("search:okr", &got) {
+		t.Errorf("decoded %v from an incompatible entry", got)
+	}
+	if _, err := os.Stat(cache.entryPath("search:okr")); !os.IsNotExist(err) {
+		t.Errorf("unreadable entry file still exists: %v", err)
+	}
+}
+
+func TestFileCacheStatsAndClear(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewFileCache(dir)
+	cache.SetCache("comments:acme/okrs#1", "a", time.Hour)
+	cache.SetCache("comments:acme/okrs#2", "b", time.Hour)
+	cache.SetCache("graphql:items", "c", time.Hour)
+	storeExpired(t, cache, "search:old")
+	if err := os.WriteFile(filepath.Join(dir, "corrupt.json"), []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := NewFileCache(dir).Stats()
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Dir != dir || stats.Entries != 3 || stats.Expired != 2 || stats.SizeBytes == 0 {
+		t.Errorf("stats = %+v, want 3 live and 2 expired entries in %s", stats, dir)
+	}
+	if fmt.Sprint(stats.ByKind) != "map[comments:2 graphql:1]" {
+		t.Errorf("entries by kind = %v", stats.ByKind)
+	}
+
+	// Clearing expired entries also removes files that cannot be read
+	if removed := NewFileCache(dir).ClearExpired(); removed != 2 {
+		t.Errorf("ClearExpired removed %d entries, want 2", removed)
+	}
+	var got string
+	if !cache.GetFromCache("graphql:items", &got) || got != "c" {
+		t.Errorf("live entry after ClearExpired = %q, want c", got)
+	}
+
+	if removed := cache.Clear(); removed != 3 {
+		t.Errorf("Clear removed %d entries, want 3", removed)
+	}
+	if cache.GetFromCache("graphql:items", &got) {
+		t.Error("entry survived Clear")
+	}
+	if paths, _ := filepath.Glob(filepath.Join(dir, "*")); len(paths) != 0 {
+		t.Errorf("files left after Clear: %v", paths)
+	}
+}
+
+func TestFileCacheConcurrentAccess(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewFileCache(dir)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				key := fmt.Sprintf("comments:acme/okrs#%d", j%5)
+				var got int
+				if !cache.GetFromCache(key, &got) {
+					cache.SetCache(key, j%5, time.Hour)
+				} else if got != j%5 {
+					t.Errorf("%s = %d, want %d", key, got, j%5)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if stats, err := cache.Stats(); err != nil || stats.Entries != 5 {
+		t.Errorf("stats = %+v (%v), want 5 entries", stats, err)
+	}
+}
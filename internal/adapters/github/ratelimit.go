@@ -0,0 +1,166 @@
+package github
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// RateResource names one of the separately metered GitHub rate limit budgets
+type RateResource string
+
+const (
+	ResourceCore    RateResource = "core"
+	ResourceSearch  RateResource = "search"
+	ResourceGraphQL RateResource = "graphql"
+)
+
+// secondaryLimitWait is how long to back off from a secondary rate limit that names no Retry-After
+const secondaryLimitWait = time.Minute
+
+// RateLimiter paces requests separately for each rate limit resource. It starts from a fixed
+// pace and then follows the quota GitHub reports back: the remaining budget is spread evenly
+// until the reset, so it slows down as the budget runs low and waits for the reset when it
+// is exhausted. Secondary rate limits pause the resource for the advertised Retry-After.
+type RateLimiter struct {
+	budgets map[RateResource]*rateBudget
+	mu      sync.Mutex
+}
+
+// rateBudget tracks the pace and last reported quota of a single resource
+type rateBudget struct {
+	limiter *rate.Limiter
+	// ceiling is the fastest pace allowed however much quota is left
+	ceiling rate.Limit
+	// pausedUntil is set when the quota is exhausted or a secondary limit was hit
+	pausedUntil time.Time
+}
+
+// NewRateLimiter creates a rate limiter; requestsPerHour sets the initial REST and GraphQL pace
+func NewRateLimiter(requestsPerHour int) *RateLimiter {
+	if requestsPerHour <= 0 {
+		requestsPerHour = 5000 // Default GitHub rate limit
+	}
+	initial := rate.Limit(float64(requestsPerHour) / 3600)
+
+	return &RateLimiter{
+		budgets: map[RateResource]*rateBudget{
+			ResourceCore:    {limiter: rate.NewLimiter(initial, 10), ceiling: 10},
+			ResourceGraphQL: {limiter: rate.NewLimiter(initial, 10), ceiling: 10},
+			// Search allows 30 requests per minute
+			ResourceSearch: {limiter: rate.NewLimiter(rate.Every(2*time.Second), 5), ceiling: 1},
+		},
+	}
+}
+
+// Wait blocks until a request against resource may be made
+func (r *RateLimiter) Wait(ctx context.Context, resource RateResource) error {
+	budget := r.budget(resource)
+
+	r.mu.Lock()
+	pausedUntil := budget.pausedUntil
+	r.mu.Unlock()
+
+	if delay := time.Until(pausedUntil); delay > 0 {
+		log.Printf("⏳ %s rate limit reached, waiting %v until %s", resource, delay.Round(time.Second), pausedUntil.Format("15:04:05"))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	return budget.limiter.Wait(ctx)
+}
+
+// Update adapts the pace of a resource to the rate limit headers of a response.
+// GitHub names the resource in X-RateLimit-Resource, which takes precedence over the given one.
+func (r *RateLimiter) Update(resource RateResource, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+	if name := resp.Header.Get("X-RateLimit-Resource"); name != "" {
+		resource = RateResource(name)
+	}
+	budget := r.budget(resource)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	remaining, hasRemaining := headerInt(resp.Header, "X-RateLimit-Remaining")
+	resetUnix, hasReset := headerInt(resp.Header, "X-RateLimit-Reset")
+	reset := time.Unix(int64(resetUnix), 0)
+
+	// Secondary rate limits say how long to back off; without Retry-After GitHub asks for a minute.
+	// A 403 is only a secondary limit when its message says so; others are missing permissions.
+	if resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, ok := headerInt(resp.Header, "Retry-After"); ok {
+			budget.pause(now.Add(time.Duration(seconds) * time.Second))
+		} else if hasRemaining && remaining == 0 && hasReset {
+			budget.pause(reset)
+		} else if resp.StatusCode == http.StatusTooManyRequests || isSecondaryRateLimitMessage(responseMessage(resp)) {
+			budget.pause(now.Add(secondaryLimitWait))
+		}
+	}
+
+	if !hasRemaining || !hasReset {
+		return
+	}
+
+	if remaining == 0 {
+		budget.pause(reset)
+		return
+	}
+
+	// Spread what is left evenly over the time until the reset
+	window := reset.Sub(now).Seconds()
+	if window < 1 {
+		window = 1
+	}
+	pace := rate.Limit(float64(remaining) / window)
+	if pace > budget.ceiling {
+		pace = budget.ceiling
+	}
+	if pace != budget.limiter.Limit() {
+		budget.limiter.SetLimit(pace)
+	}
+}
+
+// budget returns the budget of a resource, creating a core-like one for resources GitHub adds later
+func (r *RateLimiter) budget(resource RateResource) *rateBudget {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	budget, ok := r.budgets[resource]
+	if !ok {
+		budget = &rateBudget{limiter: rate.NewLimiter(r.budgets[ResourceCore].limiter.Limit(), 10), ceiling: 10}
+		r.budgets[resource] = budget
+	}
+	return budget
+}
+
+// pause stops requests until the given time unless a later pause is already in place
+func (b *rateBudget) pause(until time.Time) {
+	if until.After(b.pausedUntil) {
+		b.pausedUntil = until
+	}
+}
+
+// headerInt parses an integer response header
+func headerInt(header http.Header, name string) (int, bool) {
+	value := header.Get(name)
+	if value == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
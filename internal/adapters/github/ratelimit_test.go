@@ -0,0 +1,158 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimitResponse records a response with the given status, rate limit headers and body
+func rateLimitResponse(code int, header map[string]string, body string) *http.Response {
+	rec := httptest.NewRecorder()
+	for name, value := range header {
+		rec.Header().Set(name, value)
+	}
+	rec.WriteHeader(code)
+	io.WriteString(rec, body)
+	return rec.Result()
+}
+
+// pausedFor returns how much longer a resource is paused
+func pausedFor(r *RateLimiter, resource RateResource) time.Duration {
+	budget := r.budget(resource)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Until(budget.pausedUntil)
+}
+
+func TestRateLimiterPacesToRemainingQuota(t *testing.T) {
+	reset := fmt.Sprint(time.Now().Add(100 * time.Second).Unix())
+	tests := []struct {
+		name      string
+		remaining string
+		resource  RateResource
+		want      rate.Limit
+	}{
+		{"spread over the window", "50", ResourceCore, 0.5},
+		{"capped at the ceiling", "1000000", ResourceCore, 10},
+		{"search has a lower ceiling", "1000000", ResourceSearch, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(5000)
+			limiter.Update(tt.resource, rateLimitResponse(http.StatusOK,
+				map[string]string{"X-RateLimit-Remaining": tt.remaining, "X-RateLimit-Reset": reset}, "{}"))
+
+			got := limiter.budget(tt.resource).limiter.Limit()
+			if got < tt.want*0.95 || got > tt.want*1.05 {
+				t.Errorf("pace = %.3f/s, want about %.3f/s", got, tt.want)
+			}
+			if paused := pausedFor(limiter, tt.resource); paused > 0 {
+				t.Errorf("paused for %v with quota left", paused)
+			}
+		})
+	}
+}
+
+func TestRateLimiterPauses(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   int
+		header map[string]string
+		body   string
+		want   time.Duration
+	}{
+		{"quota exhausted until the reset", http.StatusOK,
+			map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": fmt.Sprint(time.Now().Add(90 * time.Second).Unix())}, "", 90 * time.Second},
+		{"forbidden with the quota exhausted", http.StatusForbidden,
+			map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": fmt.Sprint(time.Now().Add(30 * time.Second).Unix())}, "", 30 * time.Second},
+		{"Retry-After", http.StatusForbidden,
+			map[string]string{"Retry-After": "45", "X-RateLimit-Remaining": "4000"}, "", 45 * time.Second},
+		{"429 without Retry-After", http.StatusTooManyRequests, nil, "", secondaryLimitWait},
+		{"secondary limit without Retry-After", http.StatusForbidden,
+			map[string]string{"X-RateLimit-Remaining": "4000"}, `{"message":"You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`, secondaryLimitWait},
+		{"missing permissions", http.StatusForbidden,
+			map[string]string{"X-RateLimit-Remaining": "4000"}, `{"message":"Resource not accessible by integration"}`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(5000)
+			limiter.Update(ResourceGraphQL, rateLimitResponse(tt.code, tt.header, tt.body))
+
+			paused := pausedFor(limiter, ResourceGraphQL)
+			if tt.want == 0 && paused > 0 {
+				t.Errorf("paused for %v, want no pause", paused)
+			}
+			if tt.want > 0 && (paused < tt.want-2*time.Second || paused > tt.want) {
+				t.Errorf("paused for %v, want about %v", paused, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterFollowsReportedResource(t *testing.T) {
+	limiter := NewRateLimiter(5000)
+	reset := fmt.Sprint(time.Now().Add(time.Minute).Unix())
+
+	// GitHub names the budget a response was charged to, whichever the caller assumed
+	limiter.Update(ResourceCore, rateLimitResponse(http.StatusOK,
+		map[string]string{"X-RateLimit-Resource": "search", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}, ""))
+	if pausedFor(limiter, ResourceSearch) <= 0 {
+		t.Error("search is not paused")
+	}
+	if paused := pausedFor(limiter, ResourceCore); paused > 0 {
+		t.Errorf("core is paused for %v", paused)
+	}
+
+	// Resources GitHub adds later get a budget of their own
+	limiter.Update(ResourceCore, rateLimitResponse(http.StatusOK,
+		map[string]string{"X-RateLimit-Resource": "code_scanning_upload", "X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}, ""))
+	if pausedFor(limiter, "code_scanning_upload") <= 0 {
+		t.Error("code_scanning_upload is not paused")
+	}
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	limiter := NewRateLimiter(3600000)
+
+	// Requests go through at once while the budget lasts
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := limiter.Wait(context.Background(), ResourceCore); err != nil {
+			t.Fatalf("Wait: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("five requests within the burst took %v", elapsed)
+	}
+
+	// A short pause is waited out
+	limiter.Update(ResourceCore, rateLimitResponse(http.StatusForbidden, map[string]string{"Retry-After": "1"}, ""))
+	start = time.Now()
+	if err := limiter.Wait(context.Background(), ResourceCore); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("Wait returned after %v during a one second pause", elapsed)
+	}
+
+	// A long one is cut short by the context, and other resources are unaffected
+	limiter.Update(ResourceCore, rateLimitResponse(http.StatusTooManyRequests, nil, ""))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, ResourceCore); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait during a pause = %v, want the context deadline", err)
+	}
+	if err := limiter.Wait(context.Background(), ResourceGraphQL); err != nil {
+		t.Errorf("Wait on graphql: %v", err)
+	}
+}
@@ -2,9 +2,12 @@ package github
 
 import (
 	"context"
+	"fmt"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/go-github/v58/github"
 
@@ -14,14 +17,34 @@ import (
 // Repository implements the GitHubRepository interface
 type Repository struct {
 	client *BridgeClient
+
+	// parents remembers sub-issue parents already seen, keyed by canonical ref; nil means none
+	parents map[entity.IssueRef]*entity.Issue
+	// updated remembers when each fetched issue last changed, so unchanged comment threads are not re-downloaded
+	updated map[entity.IssueRef]time.Time
+	// comments remembers how many comments each fetched issue has, so deleted ones are noticed
+	comments map[entity.IssueRef]int
+	// mu guards the maps above; the service looks issues up from several goroutines
+	mu sync.RWMutex
 }
 
 // NewRepository creates a new GitHub repository adapter
-func NewRepository(token string, config *entity.Config) *Repository {
-	client := NewBridgeClient(token, config)
-	return &Repository{
-		client: client,
+func NewRepository(token string, config *entity.Config) (*Repository, error) {
+	client, err := NewBridgeClient(token, config)
+	if err != nil {
+		return nil, err
 	}
+	return &Repository{
+		client:   client,
+		parents:  make(map[entity.IssueRef]*entity.Issue),
+		updated:  make(map[entity.IssueRef]time.Time),
+		comments: make(map[entity.IssueRef]int),
+	}, nil
+}
+
+// Close persists the incremental sync state gathered during the run
+func (r *Repository) Close() error {
+	return r.client.SaveSyncState()
 }
 
 // ParseProjectURL parses a GitHub project URL and returns project information
@@ -31,27 +54,83 @@ func (r *Repository) ParseProjectURL(url string) (*entity.ProjectInfo, error) {
 
 // FetchProjectIssues fetches issues from a GitHub project
 func (r *Repository) FetchProjectIssues(ctx context.Context, projectInfo *entity.ProjectInfo) ([]*entity.Issue, error) {
-	githubIssues, err := r.client.fetchProjectIssuesRobust(projectInfo)
+	// Mirror the view the URL points to: its filter, sort order and grouping
+	var view *ProjectViewNode
+	if projectInfo.HasView() {
+		var err error
+		view, err = r.client.fetchProjectView(ctx, projectInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Filter while paging, so max_issues_limit caps the items the view shows rather than the whole board
+	items, err := r.client.fetchProjectIssuesRobust(ctx, projectInfo, viewItemFilter(view, r.client.config.Now()))
 	if err != nil {
 		return nil, err
 	}
 
-	return r.convertGitHubIssuesToDomain(githubIssues), nil
+	// Board items carry their sub-issue parent, which saves a lookup per issue later
+	r.mu.Lock()
+	for _, item := range items {
+		ref, err := entity.ParseIssueRef(item.Content.URL, entity.IssueRef{})
+		if err != nil {
+			continue
+		}
+		r.parents[ref.Key()] = r.convertLinkedIssueToDomain(item.Content.Parent)
+		r.updated[ref.Key()] = item.Content.UpdatedAt
+		if item.Content.Comments != nil {
+			r.comments[ref.Key()] = item.Content.Comments.TotalCount
+		}
+	}
+	r.mu.Unlock()
+
+	if view != nil {
+		items = orderProjectItems(items, view)
+		projectInfo.View = convertProjectView(view)
+	}
+
+	return r.convertProjectItemsToDomain(items), nil
 }
 
-// FetchIssuesBySearch searches for issues using GitHub's search API
-func (r *Repository) FetchIssuesBySearch(ctx context.Context, owner, repo, query string) ([]*entity.Issue, error) {
-	githubIssues, err := r.client.fetchIssuesBySearchQuery(owner, repo, query)
+// FetchIssuesBySearch searches for issues within a scope ("repo:o/r", "org:o" or "user:u") using GitHub's search API
+func (r *Repository) FetchIssuesBySearch(ctx context.Context, scope, query string) (*entity.SearchResult, error) {
+	result, err := r.client.fetchIssuesBySearchQuery(ctx, scope, query)
 	if err != nil {
 		return nil, err
 	}
 
-	return r.convertGitHubIssuesToDomain(githubIssues), nil
+	r.mu.Lock()
+	for _, ghIssue := range result.Issues {
+		ref, err := entity.ParseIssueRef(ghIssue.GetHTMLURL(), entity.IssueRef{})
+		if err != nil {
+			continue
+		}
+		r.updated[ref.Key()] = ghIssue.GetUpdatedAt().Time
+		if ghIssue.Comments != nil {
+			r.comments[ref.Key()] = ghIssue.GetComments()
+		}
+	}
+	r.mu.Unlock()
+
+	return &entity.SearchResult{
+		Issues:     r.convertGitHubIssuesToDomain(result.Issues),
+		TotalCount: result.Total,
+		Truncated:  result.Truncated,
+	}, nil
 }
 
 // FetchIssueComments fetches comments from a GitHub issue and extracts weekly updates
-func (r *Repository) FetchIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*entity.WeeklyUpdate, error) {
-	comments, err := r.client.fetchIssueComments(owner, repo, issueNumber)
+func (r *Repository) FetchIssueComments(ctx context.Context, ref entity.IssueRef) ([]*entity.WeeklyUpdate, error) {
+	r.mu.RLock()
+	updatedAt := r.updated[ref.Key()]
+	commentCount, known := r.comments[ref.Key()]
+	r.mu.RUnlock()
+	if !known {
+		commentCount = -1
+	}
+
+	comments, err := r.client.fetchIssueComments(ctx, ref, updatedAt, commentCount)
 	if err != nil {
 		return nil, err
 	}
@@ -59,33 +138,169 @@ func (r *Repository) FetchIssueComments(ctx context.Context, owner, repo string,
 	return r.convertGitHubCommentsToWeeklyUpdates(comments), nil
 }
 
-// FindParentIssue attempts to find the parent issue of a given issue
-func (r *Repository) FindParentIssue(ctx context.Context, owner, repo string, issueNumber int) (int, error) {
-	return r.client.findParentIssueFromRelationships(owner, repo, issueNumber)
+// FetchIssueTimeline fetches the history of an issue, starting with when it was opened,
+// and the pull requests that reference or close it
+func (r *Repository) FetchIssueTimeline(ctx context.Context, ref entity.IssueRef) (*entity.IssueTimeline, error) {
+	r.mu.RLock()
+	updatedAt := r.updated[ref.Key()]
+	r.mu.RUnlock()
+
+	timeline, err := r.client.fetchIssueTimeline(ctx, ref, updatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.convertTimelineToDomain(timeline), nil
+}
+
+// FindParentIssue returns the sub-issue parent of an issue, or nil when it has none.
+// The parent may live in a different repository than the issue itself.
+func (r *Repository) FindParentIssue(ctx context.Context, ref entity.IssueRef) (*entity.Issue, error) {
+	key := ref.Key()
+	r.mu.RLock()
+	parent, found := r.parents[key]
+	r.mu.RUnlock()
+	if found {
+		return parent, nil
+	}
+
+	node, err := r.client.findParentIssue(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	parent = r.convertLinkedIssueToDomain(node)
+	r.mu.Lock()
+	r.parents[key] = parent
+	r.mu.Unlock()
+	return parent, nil
 }
 
 // ExtractOwnerRepoFromIssue extracts owner and repo from an issue URL
 func (r *Repository) ExtractOwnerRepoFromIssue(issue *entity.Issue) (owner, repo string) {
-	if issue.URL == "" {
-		return "", ""
+	ref := issue.Ref()
+	return ref.Owner, ref.Repo
+}
+
+// CheckProject verifies that the project, and the view the URL points to, exist and can be read.
+// It returns the project title and the view, which is nil without a view in the URL.
+func (r *Repository) CheckProject(ctx context.Context, projectInfo *entity.ProjectInfo) (string, *entity.ProjectView, error) {
+	title, err := r.client.fetchProjectTitle(ctx, projectInfo)
+	if err != nil {
+		return "", nil, err
+	}
+	if !projectInfo.HasView() {
+		return title, nil, nil
 	}
 
-	re := regexp.MustCompile(`https://github\.com/([^/]+)/([^/]+)/issues/\d+`)
-	matches := re.FindStringSubmatch(issue.URL)
-	if len(matches) == 3 {
-		return matches[1], matches[2]
+	view, err := r.client.fetchProjectView(ctx, projectInfo)
+	if err != nil {
+		return title, nil, err
 	}
-	return "", ""
+	return title, convertProjectView(view), nil
+}
+
+// RateLimitStatus is the quota left for one rate limit resource
+type RateLimitStatus struct {
+	Resource  RateResource
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// FetchRateLimits returns the quota left for the core, search and GraphQL resources
+func (r *Repository) FetchRateLimits(ctx context.Context) ([]RateLimitStatus, error) {
+	limits, err := r.client.fetchRateLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []RateLimitStatus
+	for _, limit := range []struct {
+		resource RateResource
+		rate     *github.Rate
+	}{
+		{ResourceCore, limits.GetCore()},
+		{ResourceSearch, limits.GetSearch()},
+		{ResourceGraphQL, limits.GetGraphQL()},
+	} {
+		if limit.rate == nil {
+			continue
+		}
+		statuses = append(statuses, RateLimitStatus{
+			Resource:  limit.resource,
+			Limit:     limit.rate.Limit,
+			Remaining: limit.rate.Remaining,
+			Reset:     limit.rate.Reset.Time,
+		})
+	}
+	return statuses, nil
+}
+
+// Stats returns the API usage of this run so far
+func (r *Repository) Stats() ClientStats {
+	return r.client.GetStats()
 }
 
 // TestBasicAccess tests basic access to GitHub organization
 func (r *Repository) TestBasicAccess(ctx context.Context, org string) error {
-	return r.client.testBasicAccess(org)
+	return r.client.testBasicAccess(ctx, org)
 }
 
-// ListOrganizationProjects lists projects in a GitHub organization
-func (r *Repository) ListOrganizationProjects(ctx context.Context, org string) error {
-	return r.client.listOrganizationProjects(org)
+// CheckAccess is TestBasicAccess for report runs: a passed check is reused from the API cache
+func (r *Repository) CheckAccess(ctx context.Context, org string) error {
+	return r.client.checkAccess(ctx, org)
+}
+
+// ListOrganizationProjects lists the ProjectV2 boards of an organization with their views and custom fields
+func (r *Repository) ListOrganizationProjects(ctx context.Context, org string) ([]*entity.ProjectSummary, error) {
+	nodes, err := r.client.listOrganizationProjects(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]*entity.ProjectSummary, 0, len(nodes))
+	for _, node := range nodes {
+		project := &entity.ProjectSummary{
+			Number: node.Number,
+			Title:  node.Title,
+			URL:    node.URL,
+			Closed: node.Closed,
+			Views:  []entity.ProjectViewSummary{},
+			Fields: []entity.ProjectFieldSummary{},
+		}
+		for _, view := range node.Views.Nodes {
+			project.Views = append(project.Views, entity.ProjectViewSummary{
+				Number: view.Number,
+				Name:   view.Name,
+				Layout: strings.TrimSuffix(strings.ToLower(view.Layout), "_layout"),
+				URL:    fmt.Sprintf("%s/views/%d", node.URL, view.Number),
+			})
+		}
+		for _, field := range node.Fields.Nodes {
+			// Built-in fields such as Title, Assignees or Labels exist on every board
+			if !customFieldTypes[field.DataType] {
+				continue
+			}
+			summary := entity.ProjectFieldSummary{Name: field.Name, DataType: strings.ToLower(field.DataType)}
+			for _, option := range field.Options {
+				summary.Options = append(summary.Options, option.Name)
+			}
+			project.Fields = append(project.Fields, summary)
+		}
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
+
+// customFieldTypes are the ProjectV2 field types a board owner can add
+var customFieldTypes = map[string]bool{
+	"TEXT":          true,
+	"NUMBER":        true,
+	"DATE":          true,
+	"SINGLE_SELECT": true,
+	"ITERATION":     true,
 }
 
 // Helper methods
@@ -113,13 +328,70 @@ func (r *Repository) convertGitHubIssuesToDomain(githubIssues []*github.Issue) [
 			state = *ghIssue.State
 		}
 
+		var assignees []string
+		for _, assignee := range ghIssue.Assignees {
+			if assignee.GetLogin() != "" {
+				assignees = append(assignees, assignee.GetLogin())
+			}
+		}
+
+		var milestone *entity.Milestone
+		if ghIssue.Milestone != nil {
+			milestone = &entity.Milestone{Title: ghIssue.Milestone.GetTitle(), State: ghIssue.Milestone.GetState()}
+			if ghIssue.Milestone.DueOn != nil {
+				dueOn := ghIssue.Milestone.DueOn.Time
+				milestone.DueOn = &dueOn
+			}
+		}
+
+		issue := &entity.Issue{
+			Number:    *ghIssue.Number,
+			Title:     *ghIssue.Title,
+			URL:       *ghIssue.HTMLURL,
+			Body:      body,
+			State:     state,
+			Labels:    labels,
+			Author:    ghIssue.GetUser().GetLogin(),
+			Assignees: assignees,
+			Milestone: milestone,
+			CreatedAt: optionalTime(ghIssue.GetCreatedAt().Time),
+			UpdatedAt: optionalTime(ghIssue.GetUpdatedAt().Time),
+		}
+
+		issues = append(issues, issue)
+	}
+
+	return issues
+}
+
+// convertProjectItemsToDomain converts ProjectV2 items to domain issues
+func (r *Repository) convertProjectItemsToDomain(items []ItemNode) []*entity.Issue {
+	var issues []*entity.Issue
+
+	for _, item := range items {
+		content := item.Content
+		if content.Number == 0 || content.Title == "" || content.URL == "" {
+			continue
+		}
+
+		var labels []string
+		for _, label := range content.Labels.Nodes {
+			labels = append(labels, label.Name)
+		}
+
 		issue := &entity.Issue{
-			Number: *ghIssue.Number,
-			Title:  *ghIssue.Title,
-			URL:    *ghIssue.HTMLURL,
-			Body:   body,
-			State:  state,
-			Labels: labels,
+			Number:    content.Number,
+			Title:     content.Title,
+			URL:       content.URL,
+			Body:      content.Body,
+			State:     strings.ToLower(content.State), // GraphQL reports OPEN/CLOSED
+			Labels:    labels,
+			Author:    actorLogin(content.Author),
+			Assignees: actorLogins(content.Assignees.Nodes),
+			Milestone: r.convertMilestoneToDomain(content.Milestone),
+			CreatedAt: optionalTime(content.CreatedAt),
+			UpdatedAt: optionalTime(content.UpdatedAt),
+			Fields:    r.convertFieldValuesToDomain(item.FieldValues.Nodes),
 		}
 
 		issues = append(issues, issue)
@@ -128,6 +400,187 @@ func (r *Repository) convertGitHubIssuesToDomain(githubIssues []*github.Issue) [
 	return issues
 }
 
+// convertLinkedIssueToDomain converts an issue reached through the sub-issue relationship
+func (r *Repository) convertLinkedIssueToDomain(node *LinkedIssueNode) *entity.Issue {
+	if node == nil || node.Number == 0 {
+		return nil
+	}
+
+	var labels []string
+	for _, label := range node.Labels.Nodes {
+		labels = append(labels, label.Name)
+	}
+
+	return &entity.Issue{
+		Number:    node.Number,
+		Title:     node.Title,
+		URL:       node.URL,
+		Body:      node.Body,
+		State:     strings.ToLower(node.State),
+		Labels:    labels,
+		Author:    actorLogin(node.Author),
+		Assignees: actorLogins(node.Assignees.Nodes),
+		Milestone: r.convertMilestoneToDomain(node.Milestone),
+		CreatedAt: optionalTime(node.CreatedAt),
+		UpdatedAt: optionalTime(node.UpdatedAt),
+	}
+}
+
+// optionalTime returns t, or nil when GitHub did not report it
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// convertMilestoneToDomain converts the milestone of an issue, which is nil when it has none
+func (r *Repository) convertMilestoneToDomain(node *MilestoneNode) *entity.Milestone {
+	if node == nil {
+		return nil
+	}
+	return &entity.Milestone{Title: node.Title, DueOn: node.DueOn, State: strings.ToLower(node.State)}
+}
+
+// actorLogin returns the login of a user, or "" for deleted accounts
+func actorLogin(actor *ActorNode) string {
+	if actor == nil {
+		return ""
+	}
+	return actor.Login
+}
+
+// actorLogins returns the logins of users in order
+func actorLogins(actors []ActorNode) []string {
+	var logins []string
+	for _, actor := range actors {
+		if actor.Login != "" {
+			logins = append(logins, actor.Login)
+		}
+	}
+	return logins
+}
+
+// convertFieldValuesToDomain converts ProjectV2 field values to typed domain values
+func (r *Repository) convertFieldValuesToDomain(nodes []FieldValueNode) []entity.ProjectFieldValue {
+	var fields []entity.ProjectFieldValue
+
+	for _, node := range nodes {
+		// The built-in Title field duplicates the issue title
+		if node.Field.Name == "" || strings.EqualFold(node.Field.Name, "Title") {
+			continue
+		}
+
+		field := entity.ProjectFieldValue{Name: node.Field.Name}
+		switch node.Typename {
+		case "ProjectV2ItemFieldTextValue":
+			field.Type = entity.FieldTypeText
+			field.Text = node.Text
+		case "ProjectV2ItemFieldNumberValue":
+			field.Type = entity.FieldTypeNumber
+			field.Number = node.Number
+		case "ProjectV2ItemFieldDateValue":
+			field.Type = entity.FieldTypeDate
+			field.Date = node.Date
+		case "ProjectV2ItemFieldSingleSelectValue":
+			field.Type = entity.FieldTypeSingleSelect
+			field.Text = node.Name
+		case "ProjectV2ItemFieldIterationValue":
+			field.Type = entity.FieldTypeIteration
+			field.Text = node.Title
+			field.Date = node.StartDate
+			field.DurationDays = node.Duration
+		default:
+			continue // labels, assignees etc. are mapped from the issue itself
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+// timelineEventTypes maps GraphQL timeline item types to domain event types
+var timelineEventTypes = map[string]entity.IssueEventType{
+	"ClosedEvent":                     entity.EventClosed,
+	"ReopenedEvent":                   entity.EventReopened,
+	"LabeledEvent":                    entity.EventLabeled,
+	"UnlabeledEvent":                  entity.EventUnlabeled,
+	"ProjectV2ItemStatusChangedEvent": entity.EventProjectStatusChanged,
+}
+
+// convertTimelineToDomain converts an issue timeline to domain events, oldest first, and
+// collects the pull requests that reference or close the issue, each once
+func (r *Repository) convertTimelineToDomain(timeline *IssueTimelineNode) *entity.IssueTimeline {
+	var events []entity.IssueEvent
+	var pullRequests []entity.PullRequest
+	seen := make(map[string]int)
+	addPullRequest := func(node *PullRequestNode, closes bool) {
+		if node == nil || node.Typename == "Issue" || node.URL == "" {
+			return
+		}
+		if idx, found := seen[node.URL]; found {
+			pullRequests[idx].Closes = pullRequests[idx].Closes || closes
+			return
+		}
+		seen[node.URL] = len(pullRequests)
+		pullRequests = append(pullRequests, r.convertPullRequestToDomain(node, closes))
+	}
+	for i := range timeline.ClosedBy {
+		addPullRequest(&timeline.ClosedBy[i], true)
+	}
+
+	if !timeline.CreatedAt.IsZero() {
+		events = append(events, entity.IssueEvent{Type: entity.EventOpened, At: timeline.CreatedAt, Actor: timeline.Author})
+	}
+
+	for _, item := range timeline.Items {
+		if item.Typename == "CrossReferencedEvent" {
+			addPullRequest(item.Source, item.WillCloseTarget)
+			continue
+		}
+		eventType, ok := timelineEventTypes[item.Typename]
+		if !ok {
+			continue
+		}
+		event := entity.IssueEvent{
+			Type:        eventType,
+			At:          item.CreatedAt,
+			StateReason: strings.ToLower(item.StateReason),
+			FromStatus:  item.PreviousStatus,
+			ToStatus:    item.Status,
+		}
+		if item.Actor != nil {
+			event.Actor = item.Actor.Login
+		}
+		if item.Label != nil {
+			event.Label = item.Label.Name
+		}
+		if item.Project != nil {
+			event.Project = item.Project.Title
+		}
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].At.Before(events[j].At) })
+	sort.SliceStable(pullRequests, func(i, j int) bool { return pullRequests[i].CreatedAt.Before(pullRequests[j].CreatedAt) })
+	return &entity.IssueTimeline{Events: events, PullRequests: pullRequests}
+}
+
+// convertPullRequestToDomain converts a linked pull request to the domain model
+func (r *Repository) convertPullRequestToDomain(node *PullRequestNode, closes bool) entity.PullRequest {
+	return entity.PullRequest{
+		Repository: node.Repository.NameWithOwner,
+		Number:     node.Number,
+		Title:      node.Title,
+		URL:        node.URL,
+		State:      entity.PullRequestState(strings.ToLower(node.State)),
+		CreatedAt:  node.CreatedAt,
+		MergedAt:   node.MergedAt,
+		Closes:     closes,
+	}
+}
+
 // convertGitHubCommentsToWeeklyUpdates converts GitHub comments to weekly updates
 func (r *Repository) convertGitHubCommentsToWeeklyUpdates(comments []*github.IssueComment) []*entity.WeeklyUpdate {
 	var updates []*entity.WeeklyUpdate
@@ -0,0 +1,984 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+	"golang.org/x/time/rate"
+
+	"github-okr-fetcher/internal/adapters/github/githubtest"
+	"github-okr-fetcher/internal/domain/entity"
+)
+
+// newTestRepository creates a repository talking to the fake server
+func newTestRepository(t *testing.T, server *githubtest.Server, configure func(*entity.Config)) *Repository {
+	t.Helper()
+	config := &entity.Config{}
+	server.Configure(config)
+	if configure != nil {
+		configure(config)
+	}
+
+	repo, err := NewRepository("test-token", config)
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+	return repo
+}
+
+// issueNumbers lists the numbers of issues in order
+func issueNumbers(issues []*entity.Issue) []int {
+	numbers := make([]int, 0, len(issues))
+	for _, issue := range issues {
+		numbers = append(numbers, issue.Number)
+	}
+	return numbers
+}
+
+func TestParseProjectURL(t *testing.T) {
+	repo := newTestRepository(t, githubtest.NewServer(t), nil)
+
+	tests := []struct {
+		url     string
+		want    entity.ProjectInfo
+		wantErr string
+	}{
+		{
+			url:  "https://github.com/orgs/acme/projects/7",
+			want: entity.ProjectInfo{Host: "github.com", Owner: "acme", ProjectID: 7, Type: entity.ProjectTypeOrganization},
+		},
+		{
+			url:  "https://github.com/orgs/acme/projects/7/views/3",
+			want: entity.ProjectInfo{Host: "github.com", Owner: "acme", ProjectID: 7, ViewID: 3, Type: entity.ProjectTypeOrganization},
+		},
+		{
+			url:  "https://github.com/acme/okrs/projects/2/views/1",
+			want: entity.ProjectInfo{Host: "github.com", Owner: "acme", Repo: "okrs", ProjectID: 2, ViewID: 1, Type: entity.ProjectTypeRepository},
+		},
+		{url: "https://github.example.com/orgs/acme/projects/7", wantErr: "does not match the configured GitHub host"},
+		{url: "https://github.com/acme", wantErr: "invalid GitHub project URL format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			info, err := repo.ParseProjectURL(tt.url)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.want.URL = tt.url
+			if fmt.Sprintf("%+v", *info) != fmt.Sprintf("%+v", tt.want) {
+				t.Errorf("got %+v, want %+v", *info, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchProjectIssues(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(
+		githubtest.Issue{Ref: "acme/okrs#1", Title: "Objective", Labels: []string{"okr"}},
+		githubtest.Issue{Ref: "acme/okrs#2", Title: "KR one", Parent: "acme/okrs#1", State: "closed"},
+		githubtest.Issue{Ref: "acme/api#3", Title: "KR two", Parent: "acme/okrs#1"},
+		githubtest.Issue{Ref: "acme/okrs#4", Title: "Archived"},
+	)
+	server.AddProject(githubtest.Project{
+		Owner:  "acme",
+		Number: 1,
+		Title:  "OKRs",
+		Items: []githubtest.Item{
+			{Issue: "acme/okrs#1"},
+			{Issue: "acme/okrs#2", Fields: []githubtest.FieldValue{
+				{Name: "Status", Type: entity.FieldTypeSingleSelect, Text: "Done"},
+				{Name: "Confidence", Type: entity.FieldTypeNumber, Number: 0.8},
+			}},
+			{Issue: "acme/api#3", Fields: []githubtest.FieldValue{
+				{Name: "Status", Type: entity.FieldTypeSingleSelect, Text: "In Progress"},
+				{Name: "Target", Type: entity.FieldTypeDate, Date: "2025-03-31"},
+			}},
+			{Issue: "acme/okrs#4", Archived: true},
+		},
+	})
+
+	// A page size of two makes the board span two pages
+	repo := newTestRepository(t, server, func(config *entity.Config) { config.GitHub.PageSize = 2 })
+	info, err := repo.ParseProjectURL(server.ProjectURL("acme", "", 1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := repo.FetchProjectIssues(context.Background(), info)
+	if err != nil {
+		t.Fatalf("FetchProjectIssues: %v", err)
+	}
+
+	if got := issueNumbers(issues); fmt.Sprint(got) != "[1 2 3]" {
+		t.Fatalf("issues = %v, want [1 2 3] without the archived item", got)
+	}
+	if n := server.CountRequests("POST graphql items"); n != 2 {
+		t.Errorf("item pages requested = %d, want 2", n)
+	}
+
+	kr := issues[1]
+	if kr.State != "closed" {
+		t.Errorf("state = %q, want closed", kr.State)
+	}
+	if field := kr.GetField("Title"); field != nil {
+		t.Errorf("the built-in Title field should be dropped, got %+v", field)
+	}
+	if field := kr.GetField("status"); field == nil || field.Type != entity.FieldTypeSingleSelect || field.String() != "Done" {
+		t.Errorf("Status field = %+v, want single select Done", field)
+	}
+	if field := kr.GetField("Confidence"); field == nil || field.String() != "0.8" {
+		t.Errorf("Confidence field = %+v, want 0.8", field)
+	}
+	if field := issues[2].GetField("Target"); field == nil || field.Date != "2025-03-31" {
+		t.Errorf("Target field = %+v, want 2025-03-31", field)
+	}
+
+	// Parents came with the board items, so looking them up costs no request
+	parent, err := repo.FindParentIssue(context.Background(), issues[2].Ref())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent == nil || parent.Ref().String() != "acme/okrs#1" {
+		t.Errorf("parent = %+v, want acme/okrs#1", parent)
+	}
+	if n := server.CountRequests("POST graphql parent"); n != 0 {
+		t.Errorf("parent lookups = %d, want 0", n)
+	}
+}
+
+func TestFetchProjectIssuesStopsAtMaxIssuesLimit(t *testing.T) {
+	tests := []struct {
+		limit       int
+		want        string
+		wantPages   int
+		wantWarning bool
+	}{
+		// The limit falls inside the second page
+		{3, "[1 2 3]", 2, true},
+		// The limit ends a page with more to come
+		{4, "[1 2 3 4]", 2, true},
+		// The board fits
+		{5, "[1 2 3 4 5]", 3, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.limit), func(t *testing.T) {
+			server := githubtest.NewServer(t)
+			project := githubtest.Project{Owner: "acme", Number: 1, Title: "OKRs"}
+			for i := 1; i <= 5; i++ {
+				ref := fmt.Sprintf("acme/okrs#%d", i)
+				server.AddIssue(githubtest.Issue{Ref: ref, Title: fmt.Sprintf("Issue %d", i)})
+				project.Items = append(project.Items, githubtest.Item{Issue: ref})
+			}
+			server.AddProject(project)
+
+			repo := newTestRepository(t, server, func(config *entity.Config) {
+				config.GitHub.PageSize = 2
+				config.GitHub.MaxIssuesLimit = tt.limit
+			})
+			info, err := repo.ParseProjectURL(server.ProjectURL("acme", "", 1, 0))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			issues, err := repo.FetchProjectIssues(context.Background(), info)
+			if err != nil {
+				t.Fatalf("FetchProjectIssues: %v", err)
+			}
+			if got := fmt.Sprint(issueNumbers(issues)); got != tt.want {
+				t.Errorf("issues = %s, want %s", got, tt.want)
+			}
+			if n := server.CountRequests("POST graphql items"); n != tt.wantPages {
+				t.Errorf("item pages requested = %d, want %d", n, tt.wantPages)
+			}
+			if warned := len(info.Warnings) > 0; warned != tt.wantWarning {
+				t.Errorf("warnings = %v, want a warning: %v", info.Warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestFetchProjectIssuesAppliesView(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(
+		githubtest.Issue{Ref: "acme/okrs#1", Title: "Low", Labels: []string{"okr"}},
+		githubtest.Issue{Ref: "acme/okrs#2", Title: "Done", Labels: []string{"okr"}},
+		githubtest.Issue{Ref: "acme/okrs#3", Title: "High", Labels: []string{"okr"}},
+		githubtest.Issue{Ref: "acme/okrs#4", Title: "Unlabeled"},
+	)
+	server.AddProject(githubtest.Project{
+		Owner:  "acme",
+		Number: 1,
+		Items: []githubtest.Item{
+			{Issue: "acme/okrs#1", Fields: []githubtest.FieldValue{{Name: "Status", Type: entity.FieldTypeSingleSelect, Text: "Todo"}, {Name: "Priority", Type: entity.FieldTypeNumber, Number: 1}}},
+			{Issue: "acme/okrs#2", Fields: []githubtest.FieldValue{{Name: "Status", Type: entity.FieldTypeSingleSelect, Text: "Done"}, {Name: "Priority", Type: entity.FieldTypeNumber, Number: 3}}},
+			{Issue: "acme/okrs#3", Fields: []githubtest.FieldValue{{Name: "Status", Type: entity.FieldTypeSingleSelect, Text: "Todo"}, {Name: "Priority", Type: entity.FieldTypeNumber, Number: 2}}},
+			{Issue: "acme/okrs#4", Fields: []githubtest.FieldValue{{Name: "Status", Type: entity.FieldTypeSingleSelect, Text: "Todo"}}},
+		},
+		Views: []githubtest.View{{
+			Number: 5,
+			Name:   "Open OKRs",
+			Layout: "TABLE_LAYOUT",
+			Filter: "label:okr -status:Done",
+			SortBy: []githubtest.SortField{{Field: "Priority", Direction: "DESC"}},
+		}},
+	})
+
+	repo := newTestRepository(t, server, nil)
+	info, err := repo.ParseProjectURL(server.ProjectURL("acme", "", 1, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := repo.FetchProjectIssues(context.Background(), info)
+	if err != nil {
+		t.Fatalf("FetchProjectIssues: %v", err)
+	}
+
+	if got := issueNumbers(issues); fmt.Sprint(got) != "[3 1]" {
+		t.Errorf("issues = %v, want [3 1]: filtered on label and status, sorted by priority", got)
+	}
+	if info.View == nil || info.View.Name != "Open OKRs" || info.View.Layout != "table" {
+		t.Errorf("view = %+v, want the table view Open OKRs", info.View)
+	}
+	if info.View != nil && fmt.Sprint(info.View.SortBy) != "[Priority desc]" {
+		t.Errorf("sort = %v, want [Priority desc]", info.View.SortBy)
+	}
+}
+
+func TestFetchProjectIssuesSortsEmptyValuesLast(t *testing.T) {
+	for _, tt := range []struct {
+		direction string
+		want      string
+	}{
+		{"ASC", "[2 4 1 3]"},
+		{"DESC", "[1 4 2 3]"},
+	} {
+		t.Run(tt.direction, func(t *testing.T) {
+			server := githubtest.NewServer(t)
+			project := githubtest.Project{Owner: "acme", Number: 1, Views: []githubtest.View{{
+				Number: 2,
+				SortBy: []githubtest.SortField{{Field: "Priority", Direction: tt.direction}},
+			}}}
+			for i, priority := range []string{"P2", "P0", "", "P1"} {
+				ref := fmt.Sprintf("acme/okrs#%d", i+1)
+				server.AddIssue(githubtest.Issue{Ref: ref, Title: fmt.Sprintf("Issue %d", i+1)})
+				item := githubtest.Item{Issue: ref}
+				if priority != "" {
+					item.Fields = []githubtest.FieldValue{{Name: "Priority", Type: entity.FieldTypeSingleSelect, Text: priority}}
+				}
+				project.Items = append(project.Items, item)
+			}
+			server.AddProject(project)
+
+			repo := newTestRepository(t, server, nil)
+			info, err := repo.ParseProjectURL(server.ProjectURL("acme", "", 1, 2))
+			if err != nil {
+				t.Fatal(err)
+			}
+			issues, err := repo.FetchProjectIssues(context.Background(), info)
+			if err != nil {
+				t.Fatalf("FetchProjectIssues: %v", err)
+			}
+			if got := fmt.Sprint(issueNumbers(issues)); got != tt.want {
+				t.Errorf("sorted %s = %s, want %s", tt.direction, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchProjectIssuesFiltersBeforeMaxIssuesLimit(t *testing.T) {
+	server := githubtest.NewServer(t)
+	project := githubtest.Project{Owner: "acme", Number: 1, Title: "OKRs", Views: []githubtest.View{{Number: 2, Name: "OKRs", Filter: "label:okr"}}}
+	for i := 1; i <= 5; i++ {
+		ref := fmt.Sprintf("acme/okrs#%d", i)
+		issue := githubtest.Issue{Ref: ref, Title: fmt.Sprintf("Issue %d", i)}
+		// Only the last two items on the board are in the view
+		if i > 3 {
+			issue.Labels = []string{"okr"}
+		}
+		server.AddIssue(issue)
+		project.Items = append(project.Items, githubtest.Item{Issue: ref})
+	}
+	server.AddProject(project)
+
+	repo := newTestRepository(t, server, func(config *entity.Config) {
+		config.GitHub.PageSize = 2
+		config.GitHub.MaxIssuesLimit = 2
+	})
+	info, err := repo.ParseProjectURL(server.ProjectURL("acme", "", 1, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := repo.FetchProjectIssues(context.Background(), info)
+	if err != nil {
+		t.Fatalf("FetchProjectIssues: %v", err)
+	}
+	if got := fmt.Sprint(issueNumbers(issues)); got != "[4 5]" {
+		t.Errorf("issues = %s, want [4 5]: the limit counts only items in the view", got)
+	}
+	if len(info.Warnings) > 0 {
+		t.Errorf("warnings = %v, want none; every item in the view was fetched", info.Warnings)
+	}
+}
+
+func TestFetchProjectIssuesResolvesDateMacrosAsOf(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(
+		githubtest.Issue{Ref: "acme/okrs#1", Title: "Overdue"},
+		githubtest.Issue{Ref: "acme/okrs#2", Title: "Due this week"},
+		githubtest.Issue{Ref: "acme/okrs#3", Title: "Due later"},
+	)
+	server.AddProject(githubtest.Project{
+		Owner:  "acme",
+		Number: 1,
+		Items: []githubtest.Item{
+			{Issue: "acme/okrs#1", Fields: []githubtest.FieldValue{{Name: "Due", Type: entity.FieldTypeDate, Date: "2025-01-10"}}},
+			{Issue: "acme/okrs#2", Fields: []githubtest.FieldValue{{Name: "Due", Type: entity.FieldTypeDate, Date: "2025-01-17"}}},
+			{Issue: "acme/okrs#3", Fields: []githubtest.FieldValue{{Name: "Due", Type: entity.FieldTypeDate, Date: "2025-02-28"}}},
+		},
+		Views: []githubtest.View{{Number: 2, Name: "This week", Filter: "due:@today..@today+7"}},
+	})
+
+	asOf := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	repo := newTestRepository(t, server, func(config *entity.Config) { config.Clock = entity.FixedClock(asOf) })
+	// Search is paced at 30 requests a minute, far slower than the test needs
+	repo.client.rateLimiter.budget(ResourceSearch).limiter.SetLimit(rate.Inf)
+	info, err := repo.ParseProjectURL(server.ProjectURL("acme", "", 1, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := repo.FetchProjectIssues(context.Background(), info)
+	if err != nil {
+		t.Fatalf("FetchProjectIssues: %v", err)
+	}
+	if got := issueNumbers(issues); fmt.Sprint(got) != "[2]" {
+		t.Errorf("issues = %v, want [2]: due between 2025-01-15 and 2025-01-22", got)
+	}
+}
+
+func TestFetchIssuesBySearch(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(
+		githubtest.Issue{Ref: "acme/okrs#1", Title: "Objective", Labels: []string{"okr", "2025-q1"}},
+		githubtest.Issue{Ref: "acme/okrs#2", Title: "KR", Labels: []string{"okr", "2025-q1"}, State: "closed"},
+		githubtest.Issue{Ref: "acme/okrs#3", Title: "Last quarter", Labels: []string{"okr", "2024-q4"}},
+		githubtest.Issue{Ref: "acme/api#4", Title: "Other repository", Labels: []string{"okr", "2025-q1"}},
+	)
+
+	repo := newTestRepository(t, server, func(config *entity.Config) { config.GitHub.PageSize = 1 })
+
+	result, err := repo.FetchIssuesBySearch(context.Background(), "repo:acme/okrs", `label:"okr" label:"2025-q1" is:issue`)
+	if err != nil {
+		t.Fatalf("FetchIssuesBySearch: %v", err)
+	}
+	if got := issueNumbers(result.Issues); fmt.Sprint(got) != "[1 2]" {
+		t.Errorf("issues = %v, want [1 2]", got)
+	}
+	if result.TotalCount != 2 || result.Truncated {
+		t.Errorf("total = %d, truncated = %v; want 2, false", result.TotalCount, result.Truncated)
+	}
+	if n := server.CountRequests("GET /search/issues"); n != 2 {
+		t.Errorf("search pages requested = %d, want 2", n)
+	}
+	if result.Issues[1].State != "closed" || result.Issues[1].URL != "https://github.com/acme/okrs/issues/2" {
+		t.Errorf("issue = %+v, want the closed issue acme/okrs#2", result.Issues[1])
+	}
+
+	result, err = repo.FetchIssuesBySearch(context.Background(), "org:acme", `label:"2025-q1"`)
+	if err != nil {
+		t.Fatalf("FetchIssuesBySearch: %v", err)
+	}
+	if got := issueNumbers(result.Issues); fmt.Sprint(got) != "[1 2 4]" {
+		t.Errorf("organization-wide issues = %v, want [1 2 4]", got)
+	}
+}
+
+func TestFetchIssuesBySearchSplitsLargeResults(t *testing.T) {
+	server := githubtest.NewServer(t)
+	for i := 1; i <= 1500; i++ {
+		server.AddIssue(githubtest.Issue{Ref: fmt.Sprintf("acme/okrs#%d", i), Title: "KR", Labels: []string{"okr"},
+			CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(i) * time.Hour)})
+	}
+
+	var queries []string
+	server.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path == "/api/v3/search/issues" && r.URL.Query().Get("page") == "1" {
+			queries = append(queries, r.URL.Query().Get("q"))
+		}
+		return false
+	}
+	asOf := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo := newTestRepository(t, server, func(config *entity.Config) { config.Clock = entity.FixedClock(asOf) })
+	// Search is paced at 30 requests a minute, far slower than the test needs
+	repo.client.rateLimiter.budget(ResourceSearch).limiter.SetLimit(rate.Inf)
+
+	result, err := repo.FetchIssuesBySearch(context.Background(), "repo:acme/okrs", "label:okr")
+	if err != nil {
+		t.Fatalf("FetchIssuesBySearch: %v", err)
+	}
+	if len(result.Issues) != 1500 || result.TotalCount != 1500 || result.Truncated {
+		t.Errorf("collected %d of %d issues (truncated: %v), want all 1500", len(result.Issues), result.TotalCount, result.Truncated)
+	}
+
+	// The first page showed the query is too big, so the full range is never asked for again
+	fullRange := fmt.Sprintf("created:%s..%s", searchEpoch.Format(time.RFC3339), asOf.Format(time.RFC3339))
+	for _, query := range queries {
+		if strings.Contains(query, fullRange) {
+			t.Errorf("searched the full created range again: %s", query)
+		}
+	}
+	if queries[0] != "repo:acme/okrs label:okr" {
+		t.Errorf("first search = %q, want the query without a created range", queries[0])
+	}
+}
+
+func TestIssuesCarryPeopleMilestonesAndTimestamps(t *testing.T) {
+	dueOn := time.Date(2025, 3, 31, 8, 0, 0, 0, time.UTC)
+	server := githubtest.NewServer(t)
+	server.AddIssue(
+		githubtest.Issue{Ref: "acme/okrs#1", Title: "Objective", Labels: []string{"okr"}, Author: "carol",
+			Assignees: []string{"alice", "bob"}, Milestone: &githubtest.Milestone{Title: "Q1", DueOn: dueOn},
+			CreatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), UpdatedAt: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+		githubtest.Issue{Ref: "acme/okrs#2", Title: "KR", Labels: []string{"okr"}, Parent: "acme/okrs#1"},
+	)
+	server.AddProject(githubtest.Project{Owner: "acme", Number: 1, Items: []githubtest.Item{{Issue: "acme/okrs#1"}}})
+	repo := newTestRepository(t, server, nil)
+	ctx := context.Background()
+
+	// The same issue read from the board, a search and as a sub-issue parent
+	info, err := repo.ParseProjectURL(server.ProjectURL("acme", "", 1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromBoard, err := repo.FetchProjectIssues(ctx, info)
+	if err != nil {
+		t.Fatalf("FetchProjectIssues: %v", err)
+	}
+	fromSearch, err := repo.FetchIssuesBySearch(ctx, "repo:acme/okrs", `label:"okr" is:issue`)
+	if err != nil {
+		t.Fatalf("FetchIssuesBySearch: %v", err)
+	}
+	fromParent, err := repo.FindParentIssue(ctx, entity.IssueRef{Owner: "acme", Repo: "okrs", Number: 2})
+	if err != nil {
+		t.Fatalf("FindParentIssue: %v", err)
+	}
+
+	for source, issue := range map[string]*entity.Issue{"board": fromBoard[0], "search": fromSearch.Issues[0], "parent": fromParent} {
+		got := fmt.Sprintf("%s %v %s %s", issue.Author, issue.Assignees, issue.CreatedAt.Format("01-02"), issue.UpdatedAt.Format("01-02"))
+		if got != "carol [alice bob] 01-02 01-20" || issue.OwnerLogin() != "alice" {
+			t.Errorf("%s: author, assignees and dates = %s, owner %q", source, got, issue.OwnerLogin())
+		}
+		if m := issue.Milestone; m == nil || m.Title != "Q1" || m.DueOn == nil || !m.DueOn.Equal(dueOn) || m.State != "open" {
+			t.Errorf("%s: milestone = %+v, want open Q1 due %s", source, m, dueOn)
+		}
+	}
+}
+
+func TestFetchIssueComments(t *testing.T) {
+	comments := []githubtest.Comment{
+		{Author: "alice", Body: "# Weekly update 2025-01-06\n🟢 On track, migration started"},
+		{Author: "bob", Body: "Looks good to me"},
+		{Author: "alice", Body: "# Weekly update 2025-01-20\n🟡 Caution: vendor is late"},
+		{Author: "alice", Body: "# Weekly update 2025-01-13\nWe are blocked on the security review"},
+	}
+	// Push the weekly updates onto a second page of comments
+	for i := 0; i < 100; i++ {
+		comments = append([]githubtest.Comment{{Author: "ci", Body: "Build passed"}}, comments...)
+	}
+
+	server := githubtest.NewServer(t)
+	server.AddIssue(githubtest.Issue{Ref: "acme/okrs#2", Title: "KR", Comments: comments})
+	repo := newTestRepository(t, server, nil)
+
+	ref := entity.IssueRef{Owner: "acme", Repo: "okrs", Number: 2}
+	updates, err := repo.FetchIssueComments(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("FetchIssueComments: %v", err)
+	}
+
+	if n := server.CountRequests("GET /repos/acme/okrs/issues/2/comments"); n != 2 {
+		t.Errorf("comment pages requested = %d, want 2", n)
+	}
+
+	var got []string
+	for _, update := range updates {
+		got = append(got, fmt.Sprintf("%s %s %s", update.Date, update.Author, update.Status))
+	}
+	want := []string{
+		"2025-01-20 alice caution",
+		"2025-01-13 alice blocked",
+		"2025-01-06 alice on-track",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("updates = %q, want %q", got, want)
+	}
+}
+
+func TestFetchIssueCommentsCachesByRef(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(githubtest.Issue{Ref: "acme/okrs#2", Title: "KR", Comments: []githubtest.Comment{{Author: "alice", Body: "Looks good"}}})
+	repo := newTestRepository(t, server, func(config *entity.Config) {
+		config.Cache.Enabled = true
+		config.Cache.Dir = t.TempDir()
+		config.Cache.FullSync = true
+	})
+
+	// However a reference is spelled, it names one cache entry
+	for _, ref := range []entity.IssueRef{
+		{Host: "github.com", Owner: "acme", Repo: "okrs", Number: 2},
+		{Owner: "Acme", Repo: "OKRs", Number: 2},
+	} {
+		if _, err := repo.FetchIssueComments(context.Background(), ref); err != nil {
+			t.Fatalf("FetchIssueComments(%s): %v", ref, err)
+		}
+	}
+	if n := server.CountRequests("GET /repos/acme/okrs/issues/2/comments"); n != 1 {
+		t.Errorf("comment listings = %d, want 1", n)
+	}
+
+	var comments []*github.IssueComment
+	if !repo.client.cache.GetFromCache("comments:acme/okrs#2", &comments) || len(comments) != 1 {
+		t.Errorf("cached under comments:acme/okrs#2 = %d comments, want 1", len(comments))
+	}
+
+	// Enterprise references name their host once
+	if _, err := repo.FetchIssueComments(context.Background(), entity.IssueRef{Host: "GHE.example.com", Owner: "acme", Repo: "okrs", Number: 2}); err != nil {
+		t.Fatalf("FetchIssueComments: %v", err)
+	}
+	if !repo.client.cache.GetFromCache("comments:ghe.example.com/acme/okrs#2", &comments) {
+		t.Error("no cache entry under comments:ghe.example.com/acme/okrs#2")
+	}
+}
+
+func TestCacheIsSharedSafelyBetweenHosts(t *testing.T) {
+	cacheDir := t.TempDir()
+	hosts := []struct {
+		host  string
+		title string
+	}{
+		{"github.com", "Objective on github.com"},
+		{"ghe.example.com", "Objective on Enterprise Server"},
+	}
+
+	// The same owner, project number and search on both hosts must not share cache entries
+	for _, h := range hosts {
+		server := githubtest.NewServer(t)
+		server.AddIssue(githubtest.Issue{Ref: "acme/okrs#1", Title: h.title, Labels: []string{"okr"}})
+		server.AddProject(githubtest.Project{Owner: "acme", Number: 1, Title: "OKRs", Items: []githubtest.Item{{Issue: "acme/okrs#1"}}})
+		repo := newTestRepository(t, server, func(config *entity.Config) {
+			config.GitHub.Host = h.host
+			config.Cache.Enabled = true
+			config.Cache.Dir = cacheDir
+		})
+
+		info, err := repo.ParseProjectURL(fmt.Sprintf("https://%s/orgs/acme/projects/1", h.host))
+		if err != nil {
+			t.Fatal(err)
+		}
+		issues, err := repo.FetchProjectIssues(context.Background(), info)
+		if err != nil {
+			t.Fatalf("FetchProjectIssues on %s: %v", h.host, err)
+		}
+		if len(issues) != 1 || issues[0].Title != h.title {
+			t.Errorf("project items on %s = %+v, want %q", h.host, issues, h.title)
+		}
+
+		found, err := repo.FetchIssuesBySearch(context.Background(), "repo:acme/okrs", "label:okr")
+		if err != nil {
+			t.Fatalf("FetchIssuesBySearch on %s: %v", h.host, err)
+		}
+		if len(found.Issues) != 1 || found.Issues[0].Title != h.title {
+			t.Errorf("search results on %s = %+v, want %q", h.host, found.Issues, h.title)
+		}
+
+		if n := server.CountRequests("POST graphql items"); n != 1 {
+			t.Errorf("item pages requested from %s = %d, want 1", h.host, n)
+		}
+		if n := server.CountRequests("GET /search/issues"); n != 1 {
+			t.Errorf("searches sent to %s = %d, want 1", h.host, n)
+		}
+	}
+}
+
+func TestFetchIssueCommentsSyncsIncrementally(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	weekly := func(id int64, d int) githubtest.Comment {
+		return githubtest.Comment{ID: id, Author: "alice", CreatedAt: day(d), Body: fmt.Sprintf("# Weekly update %s\n🟢 On track", day(d).Format("2006-01-02"))}
+	}
+	issue := githubtest.Issue{Ref: "acme/okrs#2", Title: "KR", UpdatedAt: day(13), Comments: []githubtest.Comment{weekly(1, 6), weekly(2, 13)}}
+	server := githubtest.NewServer(t)
+	server.AddIssue(issue)
+	server.AddProject(githubtest.Project{Owner: "acme", Number: 1, Items: []githubtest.Item{{Issue: "acme/okrs#2"}}})
+	syncFile := filepath.Join(t.TempDir(), "sync-state.json")
+
+	var mu sync.Mutex
+	var listings []string
+	server.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if strings.HasSuffix(r.URL.Path, "/comments") {
+			mu.Lock()
+			listings = append(listings, fmt.Sprintf("since=%s conditional=%v", r.URL.Query().Get("since"), r.Header.Get("If-None-Match") != ""))
+			mu.Unlock()
+		}
+		return false
+	}
+	// run fetches the board and the issue's weekly updates like a report run, then saves the
+	// sync state; it returns the update dates and the comment listings the run made
+	run := func() (string, string) {
+		t.Helper()
+		mu.Lock()
+		listings = nil
+		mu.Unlock()
+
+		repo := newTestRepository(t, server, func(config *entity.Config) { config.Cache.SyncStateFile = syncFile })
+		info, err := repo.ParseProjectURL(server.ProjectURL("acme", "", 1, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.FetchProjectIssues(context.Background(), info); err != nil {
+			t.Fatalf("FetchProjectIssues: %v", err)
+		}
+		updates, err := repo.FetchIssueComments(context.Background(), entity.IssueRef{Owner: "acme", Repo: "okrs", Number: 2})
+		if err != nil {
+			t.Fatalf("FetchIssueComments: %v", err)
+		}
+		if err := repo.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+
+		var dates []string
+		for _, update := range updates {
+			dates = append(dates, update.Date)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return fmt.Sprint(dates), fmt.Sprint(listings)
+	}
+
+	steps := []struct {
+		name         string
+		change       func()
+		wantDates    string
+		wantListings string
+	}{
+		{"first run downloads the thread", nil,
+			"[2025-01-13 2025-01-06]", "[since= conditional=false]"},
+		{"unchanged issue costs no request", nil,
+			"[2025-01-13 2025-01-06]", "[]"},
+		// The first listing had no "since", so its ETag cannot match the next one
+		{"changed issue lists comments since the last one", func() { issue.UpdatedAt = day(14) },
+			"[2025-01-13 2025-01-06]", "[since=2025-01-13T00:00:00Z conditional=false]"},
+		{"changed issue without new comments is not modified", func() { issue.UpdatedAt = day(15) },
+			"[2025-01-13 2025-01-06]", "[since=2025-01-13T00:00:00Z conditional=true]"},
+		{"new comments are fetched since the last one", func() {
+			issue.UpdatedAt = day(20)
+			issue.Comments = append(issue.Comments, weekly(3, 20))
+		}, "[2025-01-20 2025-01-13 2025-01-06]", "[since=2025-01-13T00:00:00Z conditional=true]"},
+		// Deleting a comment need not touch the issue; the comment count still gives it away
+		{"deleted comments are pruned", func() { issue.Comments = []githubtest.Comment{weekly(1, 6), weekly(3, 20)} },
+			"[2025-01-20 2025-01-06]", "[since=2025-01-20T00:00:00Z conditional=false since= conditional=false]"},
+		{"the pruned thread is reused", nil,
+			"[2025-01-20 2025-01-06]", "[]"},
+	}
+	for _, step := range steps {
+		if step.change != nil {
+			step.change()
+			server.AddIssue(issue)
+		}
+		dates, listings := run()
+		if dates != step.wantDates || listings != step.wantListings {
+			t.Errorf("%s: updates %s after %s, want %s after %s", step.name, dates, listings, step.wantDates, step.wantListings)
+		}
+	}
+	if n := server.NotModified(); n != 1 {
+		t.Errorf("listings answered with 304 Not Modified = %d, want 1", n)
+	}
+}
+
+func TestFetchIssueTimeline(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	server := githubtest.NewServer(t)
+	server.AddIssue(githubtest.Issue{
+		Ref: "acme/okrs#2", Title: "KR", State: "closed", CreatedAt: day(2), Author: "alice",
+		Events: []githubtest.Event{
+			{Type: entity.EventLabeled, At: day(2), Actor: "alice", Label: "okr"},
+			{Type: entity.EventProjectStatusChanged, At: day(3), Actor: "bob", Project: "Q1 OKRs", From: "Todo", To: "In Progress"},
+			{Type: entity.EventClosed, At: day(10), Actor: "alice", StateReason: "COMPLETED"},
+			{Type: entity.EventReopened, At: day(12), Actor: "bob"},
+			{Type: entity.EventClosed, At: day(20), Actor: "alice", StateReason: "COMPLETED"},
+		},
+	})
+	repo := newTestRepository(t, server, func(config *entity.Config) { config.GitHub.PageSize = 2 })
+
+	timeline, err := repo.FetchIssueTimeline(context.Background(), entity.IssueRef{Owner: "acme", Repo: "okrs", Number: 2})
+	if err != nil {
+		t.Fatalf("FetchIssueTimeline: %v", err)
+	}
+	events := timeline.Events
+	if n := server.CountRequests("POST graphql timeline"); n != 3 {
+		t.Errorf("timeline pages requested = %d, want 3", n)
+	}
+
+	var got []string
+	for _, event := range events {
+		got = append(got, fmt.Sprintf("%s %s %s%s%s%s", event.At.Format("01-02"), event.Type, event.Actor, event.Label, event.StateReason, event.ToStatus))
+	}
+	want := []string{
+		"01-02 opened alice",
+		"01-02 labeled aliceokr",
+		"01-03 project_status_changed bobIn Progress",
+		"01-10 closed alicecompleted",
+		"01-12 reopened bob",
+		"01-20 closed alicecompleted",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("events = %q, want %q", got, want)
+	}
+	if events[2].Project != "Q1 OKRs" || events[2].FromStatus != "Todo" {
+		t.Errorf("status change = %+v, want Q1 OKRs from Todo", events[2])
+	}
+
+	if _, err := repo.FetchIssueTimeline(context.Background(), entity.IssueRef{Owner: "acme", Repo: "okrs", Number: 99}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("timeline of a missing issue: err = %v, want not found", err)
+	}
+}
+
+func TestFetchIssueTimelineSkipsUnchangedIssues(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	closed := githubtest.Issue{Ref: "acme/okrs#2", Title: "KR", State: "closed", UpdatedAt: day(10),
+		Events: []githubtest.Event{{Type: entity.EventClosed, At: day(10), StateReason: "COMPLETED"}}}
+	server := githubtest.NewServer(t)
+	server.AddIssue(closed)
+	server.AddProject(githubtest.Project{Owner: "acme", Number: 1, Items: []githubtest.Item{{Issue: "acme/okrs#2"}}})
+	syncFile := filepath.Join(t.TempDir(), "sync-state.json")
+
+	// run fetches the board and the issue's timeline like a report run, then saves the sync state
+	run := func(configure func(*entity.Config)) *entity.IssueTimeline {
+		t.Helper()
+		repo := newTestRepository(t, server, func(config *entity.Config) {
+			config.Cache.SyncStateFile = syncFile
+			if configure != nil {
+				configure(config)
+			}
+		})
+		info, err := repo.ParseProjectURL(server.ProjectURL("acme", "", 1, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repo.FetchProjectIssues(context.Background(), info); err != nil {
+			t.Fatalf("FetchProjectIssues: %v", err)
+		}
+		timeline, err := repo.FetchIssueTimeline(context.Background(), entity.IssueRef{Owner: "acme", Repo: "okrs", Number: 2})
+		if err != nil {
+			t.Fatalf("FetchIssueTimeline: %v", err)
+		}
+		if err := repo.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		return timeline
+	}
+
+	run(nil)
+	timeline := run(nil)
+	if n := server.CountRequests("POST graphql timeline"); n != 1 {
+		t.Errorf("timeline requests after an unchanged second run = %d, want 1", n)
+	}
+	if len(timeline.Events) != 2 || timeline.Events[1].Type != entity.EventClosed {
+		t.Errorf("reused events = %+v, want opened and closed", timeline.Events)
+	}
+
+	closed.UpdatedAt = day(12)
+	closed.Events = append(closed.Events, githubtest.Event{Type: entity.EventReopened, At: day(12)})
+	server.AddIssue(closed)
+	if timeline := run(nil); len(timeline.Events) != 3 {
+		t.Errorf("events after the issue changed = %+v, want the reopen too", timeline.Events)
+	}
+	if n := server.CountRequests("POST graphql timeline"); n != 2 {
+		t.Errorf("timeline requests after the issue changed = %d, want 2", n)
+	}
+
+	// Without sync state the API cache still answers repeated runs
+	cacheDir := t.TempDir()
+	cached := func(config *entity.Config) {
+		config.Cache.FullSync = true
+		config.Cache.Enabled = true
+		config.Cache.Dir = cacheDir
+	}
+	run(cached)
+	run(cached)
+	if n := server.CountRequests("POST graphql timeline"); n != 3 {
+		t.Errorf("timeline requests with a warm cache = %d, want 3", n)
+	}
+}
+
+func TestFetchIssueTimelineLinksPullRequests(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	server := githubtest.NewServer(t)
+	server.AddIssue(githubtest.Issue{
+		Ref: "acme/okrs#2", Title: "KR",
+		PullRequests: []githubtest.PullRequest{
+			{Ref: "acme/api#41", Title: "Cache prices", State: "MERGED", CreatedAt: day(8), MergedAt: day(9), Closes: true},
+			{Ref: "acme/web#7", Title: "Lazy load images", CreatedAt: day(3)},
+		},
+	})
+	repo := newTestRepository(t, server, nil)
+
+	timeline, err := repo.FetchIssueTimeline(context.Background(), entity.IssueRef{Owner: "acme", Repo: "okrs", Number: 2})
+	if err != nil {
+		t.Fatalf("FetchIssueTimeline: %v", err)
+	}
+
+	// The closing pull request is both a closing reference and a cross-reference, and is listed once
+	var got []string
+	for _, pr := range timeline.PullRequests {
+		got = append(got, fmt.Sprintf("%s#%d %s closes=%v merged=%v", pr.Repository, pr.Number, pr.State, pr.Closes, pr.IsMerged()))
+	}
+	want := []string{
+		"acme/web#7 open closes=false merged=false",
+		"acme/api#41 merged closes=true merged=true",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("pull requests = %q, want %q", got, want)
+	}
+	if pr := timeline.PullRequests[1]; pr.URL != "https://github.com/acme/api/pull/41" || pr.Title != "Cache prices" || !pr.MergedAt.Equal(day(9)) {
+		t.Errorf("merged pull request = %+v", pr)
+	}
+	// Cross-references are not part of the issue's own history
+	if len(timeline.Events) != 1 || timeline.Events[0].Type != entity.EventOpened {
+		t.Errorf("events = %+v, want only the opened event", timeline.Events)
+	}
+}
+
+func TestFindParentIssue(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(
+		githubtest.Issue{Ref: "acme/okrs#1", Title: "Objective", Labels: []string{"okr"}},
+		githubtest.Issue{Ref: "acme/api#7", Title: "KR in another repository", Parent: "acme/okrs#1"},
+		githubtest.Issue{Ref: "acme/api#8", Title: "Standalone"},
+	)
+	repo := newTestRepository(t, server, nil)
+
+	parent, err := repo.FindParentIssue(context.Background(), entity.IssueRef{Owner: "acme", Repo: "api", Number: 7})
+	if err != nil {
+		t.Fatalf("FindParentIssue: %v", err)
+	}
+	if parent == nil || parent.Ref().String() != "acme/okrs#1" || !parent.HasLabel("okr") {
+		t.Errorf("parent = %+v, want acme/okrs#1 with its labels", parent)
+	}
+
+	parent, err = repo.FindParentIssue(context.Background(), entity.IssueRef{Owner: "acme", Repo: "api", Number: 8})
+	if err != nil || parent != nil {
+		t.Errorf("parent of a standalone issue = %+v, %v; want nil, nil", parent, err)
+	}
+
+	// Parents are remembered for the rest of the run
+	if _, err := repo.FindParentIssue(context.Background(), entity.IssueRef{Owner: "Acme", Repo: "API", Number: 7}); err != nil {
+		t.Fatal(err)
+	}
+	if n := server.CountRequests("POST graphql parent"); n != 2 {
+		t.Errorf("parent lookups = %d, want 2", n)
+	}
+}
+
+func TestTestBasicAccess(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(githubtest.Issue{Ref: "acme/okrs#1", Title: "Objective"})
+	repo := newTestRepository(t, server, nil)
+
+	if err := repo.TestBasicAccess(context.Background(), "acme"); err != nil {
+		t.Errorf("access to acme: %v", err)
+	}
+	if err := repo.TestBasicAccess(context.Background(), "unknown-org"); err == nil {
+		t.Error("access to an unknown organization should fail")
+	}
+
+	server.Scopes = "repo, project"
+	err := repo.TestBasicAccess(context.Background(), "")
+	var scopeErr *ScopeError
+	if !errors.As(err, &scopeErr) {
+		t.Fatalf("error = %v, want a ScopeError", err)
+	}
+	if fmt.Sprint(scopeErr.Missing) != "[read:org]" {
+		t.Errorf("missing scopes = %v, want [read:org]; project implies read:project", scopeErr.Missing)
+	}
+}
+
+func TestListOrganizationProjects(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(githubtest.Issue{Ref: "acme/okrs#1", Title: "Objective"})
+	server.AddProject(githubtest.Project{Owner: "acme", Number: 2, Title: "Roadmap", Closed: true})
+	server.AddProject(githubtest.Project{
+		Owner:  "acme",
+		Number: 1,
+		Title:  "OKRs",
+		Items:  []githubtest.Item{{Issue: "acme/okrs#1", Fields: []githubtest.FieldValue{{Name: "Status", Type: entity.FieldTypeSingleSelect, Text: "Todo"}}}},
+		Views:  []githubtest.View{{Number: 1, Name: "Board", Layout: "BOARD_LAYOUT"}},
+	})
+	server.AddProject(githubtest.Project{Owner: "other", Number: 3, Title: "Not listed"})
+	repo := newTestRepository(t, server, nil)
+
+	projects, err := repo.ListOrganizationProjects(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("ListOrganizationProjects: %v", err)
+	}
+	if len(projects) != 2 {
+		t.Fatalf("projects = %d, want 2", len(projects))
+	}
+
+	okrs := projects[0]
+	if okrs.Title != "OKRs" || okrs.Closed || okrs.URL != "https://github.com/orgs/acme/projects/1" {
+		t.Errorf("project = %+v, want the open OKRs board", okrs)
+	}
+	if len(okrs.Views) != 1 || okrs.Views[0].Layout != "board" || okrs.Views[0].URL != "https://github.com/orgs/acme/projects/1/views/1" {
+		t.Errorf("views = %+v, want one board view with its URL", okrs.Views)
+	}
+	if len(okrs.Fields) != 1 || okrs.Fields[0].Name != "Status" || fmt.Sprint(okrs.Fields[0].Options) != "[Todo]" {
+		t.Errorf("fields = %+v, want only the custom Status field", okrs.Fields)
+	}
+	if !projects[1].Closed {
+		t.Errorf("project %d should be closed", projects[1].Number)
+	}
+}
+
+func TestListOrganizationProjectsStopsOnRepeatedCursor(t *testing.T) {
+	server := githubtest.NewServer(t)
+	var pages atomic.Int32
+	// Every page claims there is another one after the same cursor
+	server.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if r.URL.Path != "/api/graphql" {
+			return false
+		}
+		pages.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"data": {"organization": {"projectsV2": {"nodes": [{"number": 1, "title": "OKRs"}], "pageInfo": {"hasNextPage": true, "endCursor": "c1"}}}}}`)
+		return true
+	}
+	repo := newTestRepository(t, server, nil)
+
+	_, err := repo.ListOrganizationProjects(context.Background(), "acme")
+	if err == nil || !strings.Contains(err.Error(), "no new cursor") {
+		t.Fatalf("error = %v, want one about the repeated cursor", err)
+	}
+	if n := pages.Load(); n != 2 {
+		t.Errorf("pages requested = %d, want 2", n)
+	}
+}
@@ -0,0 +1,183 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/go-github/v58/github"
+
+	"github-okr-fetcher/internal/domain/entity"
+)
+
+// syncStateVersion is bumped whenever the layout of the state file changes
+const syncStateVersion = 1
+
+// SyncState remembers the comments and timeline fetched for each issue between runs, so
+// later runs only download what changed
+type SyncState struct {
+	Version   int                           `json:"version"`
+	Issues    map[string]*IssueSyncState    `json:"issues"`
+	Timelines map[string]*TimelineSyncState `json:"timelines,omitempty"`
+
+	path  string
+	dirty bool
+	mu    sync.Mutex
+}
+
+// IssueSyncState is what was known about an issue's comments after the last sync
+type IssueSyncState struct {
+	// IssueUpdatedAt is the issue's updated_at when its comments were last synced
+	IssueUpdatedAt time.Time `json:"issue_updated_at"`
+	// LastSeen is the newest comment update time, sent as "since" on the next request
+	LastSeen time.Time `json:"last_seen"`
+	// ETag belongs to the request made with LastSeen as "since"
+	ETag     string                 `json:"etag,omitempty"`
+	Comments []*github.IssueComment `json:"comments"`
+}
+
+// TimelineSyncState is an issue's timeline as fetched during the last sync
+type TimelineSyncState struct {
+	// IssueUpdatedAt is the issue's updated_at when its timeline was fetched
+	IssueUpdatedAt time.Time          `json:"issue_updated_at"`
+	Timeline       *IssueTimelineNode `json:"timeline"`
+}
+
+// SyncStatePath returns the sync state file: the configured one, or one under the user cache directory
+func SyncStatePath(config *entity.Config) (string, error) {
+	if config != nil && config.Cache.SyncStateFile != "" {
+		return config.Cache.SyncStateFile, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("error locating user cache directory: %v", err)
+	}
+	return filepath.Join(dir, "github-okr-fetcher", "sync-state.json"), nil
+}
+
+// LoadSyncState reads the sync state from path; a missing or outdated file yields an empty state
+func LoadSyncState(path string) (*SyncState, error) {
+	state := &SyncState{
+		Version:   syncStateVersion,
+		Issues:    make(map[string]*IssueSyncState),
+		Timelines: make(map[string]*TimelineSyncState),
+		path:      path,
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading sync state: %v", err)
+	}
+
+	var stored SyncState
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("error parsing sync state %s: %v", path, err)
+	}
+	if stored.Version == syncStateVersion && stored.Issues != nil {
+		state.Issues = stored.Issues
+	}
+	if stored.Version == syncStateVersion && stored.Timelines != nil {
+		state.Timelines = stored.Timelines
+	}
+
+	return state, nil
+}
+
+// Lookup returns the stored state of an issue, or nil when it was never synced
+func (s *SyncState) Lookup(ref entity.IssueRef) *IssueSyncState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Issues[ref.Key().String()]
+}
+
+// Store records the state of an issue after its comments were synced
+func (s *SyncState) Store(ref entity.IssueRef, issueState *IssueSyncState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Issues[ref.Key().String()] = issueState
+	s.dirty = true
+}
+
+// LookupTimeline returns the stored timeline of an issue, or nil when it was never fetched
+func (s *SyncState) LookupTimeline(ref entity.IssueRef) *TimelineSyncState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.Timelines[ref.Key().String()]
+}
+
+// StoreTimeline records the timeline of an issue as of the issue's updated_at
+func (s *SyncState) StoreTimeline(ref entity.IssueRef, timelineState *TimelineSyncState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Timelines[ref.Key().String()] = timelineState
+	s.dirty = true
+}
+
+// Save writes the state back to disk if anything changed
+func (s *SyncState) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("error encoding sync state: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("error creating sync state directory: %v", err)
+	}
+
+	// Write to a temporary file first so an interrupted run never leaves a corrupt state
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("error writing sync state: %v", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("error writing sync state: %v", err)
+	}
+
+	s.dirty = false
+	return nil
+}
+
+// mergeComments applies comments fetched with "since" on top of the stored ones,
+// replacing edited comments and keeping creation order
+func mergeComments(stored, changed []*github.IssueComment) []*github.IssueComment {
+	index := make(map[int64]int, len(stored))
+	merged := make([]*github.IssueComment, len(stored))
+	copy(merged, stored)
+	for i, comment := range merged {
+		index[comment.GetID()] = i
+	}
+
+	for _, comment := range changed {
+		if i, found := index[comment.GetID()]; found {
+			merged[i] = comment
+			continue
+		}
+		index[comment.GetID()] = len(merged)
+		merged = append(merged, comment)
+	}
+
+	return merged
+}
+
+// newestCommentUpdate returns the latest update time among comments, or zero when there are none
+func newestCommentUpdate(comments []*github.IssueComment) time.Time {
+	var newest time.Time
+	for _, comment := range comments {
+		if updated := comment.GetUpdatedAt().Time; updated.After(newest) {
+			newest = updated
+		}
+	}
+	return newest
+}
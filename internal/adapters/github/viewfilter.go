@@ -0,0 +1,518 @@
+package github
+
+import (
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github-okr-fetcher/internal/domain/entity"
+)
+
+// unsupportedFilterQualifiers lists view filter qualifiers that cannot be evaluated from project item data
+var unsupportedFilterQualifiers = map[string]bool{
+	"reason":               true,
+	"updated":              true,
+	"last-updated":         true,
+	"created":              true,
+	"closed":               true,
+	"type":                 true,
+	"reviewers":            true,
+	"linked-pull-requests": true,
+	"parent-issue":         true,
+	"sub-issues-progress":  true,
+}
+
+// isFilterValues are the values of the is: qualifier; others are ignored with a warning
+var isFilterValues = map[string]bool{
+	"open":   true,
+	"closed": true,
+	"merged": true,
+	"draft":  true,
+	"issue":  true,
+	"pr":     true,
+}
+
+// viewFilterTerm is a single qualifier or free-text term of a project view filter
+type viewFilterTerm struct {
+	negate    bool
+	qualifier string
+	values    []string
+}
+
+// viewItemFilter returns whether an item passes the view's filter, or nil when the view shows every item
+func viewItemFilter(view *ProjectViewNode, now time.Time) func(ItemNode) bool {
+	if view == nil || strings.TrimSpace(view.Filter) == "" {
+		return nil
+	}
+
+	terms := parseViewFilter(view.Filter)
+	return func(item ItemNode) bool {
+		return matchesViewFilter(item, terms, now)
+	}
+}
+
+// orderProjectItems sorts and groups items the way the view does
+func orderProjectItems(filtered []ItemNode, view *ProjectViewNode) []ItemNode {
+	if view == nil {
+		return filtered
+	}
+
+	// Sort by the view's sort fields first, then order groups; both sorts are stable
+	// so items keep their board position whenever the view does not decide otherwise
+	sortFields := view.SortByFields.Nodes
+	if len(sortFields) > 0 {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			for _, sortField := range sortFields {
+				a, b := itemValues(filtered[i], sortField.Field.Name), itemValues(filtered[j], sortField.Field.Name)
+				// Items without a value go last in either direction, as in the browser
+				if (len(a) == 0) != (len(b) == 0) {
+					return len(b) == 0
+				}
+				cmp := compareFieldValues(a, b)
+				if cmp == 0 {
+					continue
+				}
+				if strings.EqualFold(sortField.Direction, "DESC") {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+			return false
+		})
+	}
+
+	for g := len(view.GroupByFields.Nodes) - 1; g >= 0; g-- {
+		groupField := view.GroupByFields.Nodes[g]
+		ranks := groupRanks(filtered, groupField)
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return ranks[groupKey(filtered[i], groupField.Name)] < ranks[groupKey(filtered[j], groupField.Name)]
+		})
+	}
+
+	return filtered
+}
+
+// convertProjectView converts a view definition to its domain representation
+func convertProjectView(view *ProjectViewNode) *entity.ProjectView {
+	if view == nil {
+		return nil
+	}
+
+	projectView := &entity.ProjectView{
+		Number: view.Number,
+		Name:   view.Name,
+		Layout: strings.ToLower(strings.TrimSuffix(view.Layout, "_LAYOUT")),
+		Filter: view.Filter,
+	}
+	for _, sortField := range view.SortByFields.Nodes {
+		projectView.SortBy = append(projectView.SortBy, sortField.Field.Name+" "+strings.ToLower(sortField.Direction))
+	}
+	for _, groupField := range view.GroupByFields.Nodes {
+		projectView.GroupBy = append(projectView.GroupBy, groupField.Name)
+	}
+
+	return projectView
+}
+
+// parseViewFilter splits a view filter into terms, honoring quoted values
+func parseViewFilter(filter string) []viewFilterTerm {
+	var terms []viewFilterTerm
+
+	for _, token := range splitFilterTokens(filter) {
+		term := viewFilterTerm{}
+		if strings.HasPrefix(token, "-") && len(token) > 1 {
+			term.negate = true
+			token = token[1:]
+		}
+
+		if idx := strings.Index(token, ":"); idx > 0 {
+			term.qualifier = strings.ToLower(strings.Trim(token[:idx], `"`))
+			for _, value := range strings.Split(token[idx+1:], ",") {
+				if value = strings.Trim(value, `"`); value != "" {
+					term.values = append(term.values, value)
+				}
+			}
+		} else {
+			term.values = []string{strings.Trim(token, `"`)}
+		}
+
+		switch term.qualifier {
+		case "is":
+			term.values = supportedIsValues(term.values)
+		case "", "no", "has":
+		default:
+			term.values = supportedFieldValues(term.qualifier, term.values)
+		}
+		if unsupportedFilterQualifiers[term.qualifier] || len(term.values) == 0 {
+			log.Printf("⚠️  View filter term %q is not supported and will be ignored", token)
+			continue
+		}
+
+		terms = append(terms, term)
+	}
+
+	return terms
+}
+
+// supportedIsValues drops the is: values that are not understood, warning about each
+func supportedIsValues(values []string) []string {
+	var supported []string
+	for _, value := range values {
+		if !isFilterValues[strings.ToLower(value)] {
+			log.Printf("⚠️  View filter value is:%s is not supported and will be ignored", value)
+			continue
+		}
+		supported = append(supported, value)
+	}
+	return supported
+}
+
+// todayMacro matches @today with an optional offset in days, e.g. @today-7d
+var todayMacro = regexp.MustCompile(`^@today([+-]\d+d?)?$`)
+
+// supportedFieldValues drops field values with macros that cannot be resolved, such as @me, warning
+// about each; matching them literally would silently filter out every item
+func supportedFieldValues(qualifier string, values []string) []string {
+	var supported []string
+	for _, value := range values {
+		if !isSupportedFieldValue(value) {
+			log.Printf("⚠️  View filter value %s:%s is not supported and will be ignored", qualifier, value)
+			continue
+		}
+		supported = append(supported, value)
+	}
+	return supported
+}
+
+// isSupportedFieldValue reports whether the macros in a value, comparison or range can be resolved.
+// Iteration macros only work as plain values; @today also works in comparisons and ranges.
+func isSupportedFieldValue(value string) bool {
+	if iterationMacros[value] {
+		return true
+	}
+	for _, bound := range strings.SplitN(strings.TrimLeft(value, "<>="), "..", 2) {
+		if strings.HasPrefix(bound, "@") && !todayMacro.MatchString(bound) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitFilterTokens splits a filter on whitespace that is not inside double quotes
+func splitFilterTokens(filter string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, r := range filter {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(r)
+		case (r == ' ' || r == '\t') && !inQuotes:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens
+}
+
+// matchesViewFilter checks whether an item satisfies every term of a view filter
+func matchesViewFilter(item ItemNode, terms []viewFilterTerm, now time.Time) bool {
+	for _, term := range terms {
+		if matchesFilterTerm(item, term, now) == term.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesFilterTerm evaluates a single filter term; multiple values are OR'ed
+func matchesFilterTerm(item ItemNode, term viewFilterTerm, now time.Time) bool {
+	switch term.qualifier {
+	case "":
+		title := strings.ToLower(item.Content.Title)
+		return strings.Contains(title, strings.ToLower(term.values[0]))
+	case "is":
+		for _, value := range term.values {
+			if matchesIsValue(item, strings.ToLower(value)) {
+				return true
+			}
+		}
+		return false
+	case "no", "has":
+		for _, value := range term.values {
+			if (len(itemValues(item, value)) > 0) == (term.qualifier == "has") {
+				return true
+			}
+		}
+		return false
+	}
+
+	iteration := item.fieldValue(term.qualifier)
+	actual := itemValues(item, term.qualifier)
+	for _, expected := range term.values {
+		if iterationMacros[expected] {
+			if iteration != nil && matchesIterationMacro(iteration, expected, now) {
+				return true
+			}
+			continue
+		}
+		if matchesFilterValue(actual, expected, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesIsValue checks an item's state or content type against a value of the is: qualifier
+func matchesIsValue(item ItemNode, value string) bool {
+	switch value {
+	case "open", "closed", "merged":
+		return strings.EqualFold(item.Content.State, value)
+	case "issue":
+		return item.Type == "ISSUE"
+	case "pr":
+		return item.Type == "PULL_REQUEST"
+	case "draft":
+		return item.Type == "DRAFT_ISSUE"
+	}
+	return false
+}
+
+// matchesFilterValue matches an expected filter value, range or comparison against an item's values
+func matchesFilterValue(actual []string, expected string, now time.Time) bool {
+	for _, value := range actual {
+		switch {
+		case strings.HasPrefix(expected, ">="):
+			if compareScalar(value, resolveDateMacro(expected[2:], now)) >= 0 {
+				return true
+			}
+		case strings.HasPrefix(expected, "<="):
+			if compareScalar(value, resolveDateMacro(expected[2:], now)) <= 0 {
+				return true
+			}
+		case strings.HasPrefix(expected, ">"):
+			if compareScalar(value, resolveDateMacro(expected[1:], now)) > 0 {
+				return true
+			}
+		case strings.HasPrefix(expected, "<"):
+			if compareScalar(value, resolveDateMacro(expected[1:], now)) < 0 {
+				return true
+			}
+		case strings.Contains(expected, ".."):
+			bounds := strings.SplitN(expected, "..", 2)
+			lower, upper := resolveDateMacro(bounds[0], now), resolveDateMacro(bounds[1], now)
+			if (lower == "*" || compareScalar(value, lower) >= 0) && (upper == "*" || compareScalar(value, upper) <= 0) {
+				return true
+			}
+		default:
+			if wildcardMatch(value, resolveDateMacro(expected, now)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// itemValues returns the values of a built-in or custom field of a project item
+func itemValues(item ItemNode, field string) []string {
+	var values []string
+
+	switch normalizeFieldName(field) {
+	case "label", "labels":
+		for _, label := range item.Content.Labels.Nodes {
+			values = append(values, label.Name)
+		}
+		return values
+	case "assignee", "assignees":
+		for _, assignee := range item.Content.Assignees.Nodes {
+			values = append(values, assignee.Login)
+		}
+		return values
+	case "repo", "repository":
+		repo := item.Content.Repository
+		if repo.Name != "" {
+			values = append(values, repo.Owner.Login+"/"+repo.Name)
+		}
+		return values
+	case "milestone":
+		if item.Content.Milestone != nil {
+			values = append(values, item.Content.Milestone.Title)
+		}
+		return values
+	case "title":
+		return []string{item.Content.Title}
+	}
+
+	if fieldValue := item.fieldValue(field); fieldValue != nil {
+		if value := fieldValue.value(); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// fieldValue returns the value of the named project field, if the item has one
+func (n ItemNode) fieldValue(field string) *FieldValueNode {
+	normalized := normalizeFieldName(field)
+	for i := range n.FieldValues.Nodes {
+		if normalizeFieldName(n.FieldValues.Nodes[i].Field.Name) == normalized {
+			return &n.FieldValues.Nodes[i]
+		}
+	}
+	return nil
+}
+
+// value returns the field value rendered as a string
+func (v *FieldValueNode) value() string {
+	switch v.Typename {
+	case "ProjectV2ItemFieldSingleSelectValue":
+		return v.Name
+	case "ProjectV2ItemFieldNumberValue":
+		if v.Number == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v.Number, 'f', -1, 64)
+	case "ProjectV2ItemFieldDateValue":
+		return v.Date
+	case "ProjectV2ItemFieldIterationValue":
+		return v.Title
+	default:
+		return v.Text
+	}
+}
+
+// normalizeFieldName makes field names comparable; filters write "Target date" as target-date
+func normalizeFieldName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "-")
+}
+
+// iterationMacros are the relative iteration values supported in view filters
+var iterationMacros = map[string]bool{
+	"@current":  true,
+	"@previous": true,
+	"@next":     true,
+}
+
+// matchesIterationMacro checks whether an iteration value is the current, previous or next iteration
+func matchesIterationMacro(value *FieldValueNode, macro string, now time.Time) bool {
+	if value.Typename != "ProjectV2ItemFieldIterationValue" || value.Duration <= 0 {
+		return false
+	}
+	start, err := time.Parse("2006-01-02", value.StartDate)
+	if err != nil {
+		return false
+	}
+
+	today, _ := time.Parse("2006-01-02", now.Format("2006-01-02"))
+	end := start.AddDate(0, 0, value.Duration)
+	length := end.Sub(start)
+
+	switch macro {
+	case "@current":
+		return !today.Before(start) && today.Before(end)
+	case "@previous":
+		return !today.Before(end) && today.Before(end.Add(length))
+	case "@next":
+		return today.Before(start) && !today.Before(start.Add(-length))
+	}
+	return false
+}
+
+// groupKey returns the value an item is grouped by
+func groupKey(item ItemNode, field string) string {
+	values := itemValues(item, field)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// groupRanks orders groups by single select option order, falling back to first appearance
+func groupRanks(items []ItemNode, field ProjectFieldNode) map[string]int {
+	ranks := map[string]int{"": -1} // items without a value form the leading "No <field>" group
+	for i, option := range field.Options {
+		ranks[option.Name] = i
+	}
+	next := len(field.Options)
+	for _, item := range items {
+		key := groupKey(item, field.Name)
+		if _, ok := ranks[key]; !ok {
+			ranks[key] = next
+			next++
+		}
+	}
+	return ranks
+}
+
+// compareFieldValues compares two multi-valued field values; empty values sort last when ascending
+func compareFieldValues(a, b []string) int {
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+	return compareScalar(a[0], b[0])
+}
+
+// compareScalar compares numerically when both values are numbers, otherwise case-insensitively
+func compareScalar(a, b string) int {
+	if af, err := strconv.ParseFloat(a, 64); err == nil {
+		if bf, err := strconv.ParseFloat(b, 64); err == nil {
+			switch {
+			case af < bf:
+				return -1
+			case af > bf:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// wildcardMatch matches a value against a case-insensitive pattern that may contain * wildcards
+func wildcardMatch(value, pattern string) bool {
+	if !strings.Contains(pattern, "*") {
+		return strings.EqualFold(value, pattern)
+	}
+	parts := strings.Split(pattern, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	re, err := regexp.Compile("(?i)^" + strings.Join(parts, ".*") + "$")
+	if err != nil {
+		return false
+	}
+	return re.MatchString(value)
+}
+
+// resolveDateMacro expands @today, @today-7 and @today+14 (days) to an ISO date
+func resolveDateMacro(value string, now time.Time) string {
+	if !strings.HasPrefix(value, "@today") {
+		return value
+	}
+	date := now
+	if offset := strings.TrimPrefix(value, "@today"); offset != "" {
+		offset = strings.TrimSuffix(offset, "d")
+		if days, err := strconv.Atoi(offset); err == nil {
+			date = date.AddDate(0, 0, days)
+		}
+	}
+	return date.Format("2006-01-02")
+}
@@ -0,0 +1,99 @@
+package github
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseViewFilter(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{"checkout", `[{false  [checkout]}]`},
+		{`"faster checkout"`, `[{false  [faster checkout]}]`},
+		{"label:okr,kr -status:Done", `[{false label [okr kr]} {true status [Done]}]`},
+		{`status:"In Progress" target-date:>=@today`, `[{false status [In Progress]} {false target-date [>=@today]}]`},
+		{`"Target Date":2025-01-01..2025-03-31`, `[{false target date [2025-01-01..2025-03-31]}]`},
+		{"is:open,draft -is:pr", `[{false is [open draft]} {true is [pr]}]`},
+		// Unknown is: values are dropped, and a term left without values with them
+		{"is:open,starred", `[{false is [open]}]`},
+		{"is:starred label:okr", `[{false label [okr]}]`},
+		// Qualifiers the item data cannot answer are ignored
+		{"updated:>@today-7 no:assignee", `[{false no [assignee]}]`},
+		// Macros that cannot be resolved are dropped instead of matched literally
+		{"assignee:@me", `[]`},
+		{"assignee:@me,alice", `[{false assignee [alice]}]`},
+		{"target-date:>=@today-7d iteration:@current", `[{false target-date [>=@today-7d]} {false iteration [@current]}]`},
+		{"target-date:@today..@next", `[]`},
+		{"label:", `[]`},
+		{"  ", `[]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			terms := parseViewFilter(tt.filter)
+			if got := fmt.Sprint(terms); got != tt.want {
+				t.Errorf("parseViewFilter(%q) = %s, want %s", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
+
+// viewItem builds a project item of the given type and state
+func viewItem(number int, itemType, state string) ItemNode {
+	var item ItemNode
+	item.Type = itemType
+	item.Content.Number = number
+	item.Content.Title = fmt.Sprintf("Item %d", number)
+	item.Content.State = state
+	return item
+}
+
+// itemNumbers lists the numbers of items in order
+func itemNumbers(items []ItemNode) []int {
+	numbers := make([]int, 0, len(items))
+	for _, item := range items {
+		numbers = append(numbers, item.Content.Number)
+	}
+	return numbers
+}
+
+func TestMatchesViewFilterIs(t *testing.T) {
+	items := []ItemNode{
+		viewItem(1, "ISSUE", "OPEN"),
+		viewItem(2, "ISSUE", "CLOSED"),
+		viewItem(3, "PULL_REQUEST", "MERGED"),
+		viewItem(4, "DRAFT_ISSUE", ""),
+	}
+	tests := []struct {
+		filter string
+		want   string
+	}{
+		{"is:open", "[1]"},
+		{"is:closed", "[2]"},
+		{"is:merged", "[3]"},
+		{"is:issue", "[1 2]"},
+		{"is:pr", "[3]"},
+		{"is:draft", "[4]"},
+		{"is:issue,pr -is:closed", "[1 3]"},
+		// An unknown value filters nothing instead of everything
+		{"is:starred", "[1 2 3 4]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter, func(t *testing.T) {
+			terms := parseViewFilter(tt.filter)
+			var matched []ItemNode
+			for _, item := range items {
+				if matchesViewFilter(item, terms, time.Now()) {
+					matched = append(matched, item)
+				}
+			}
+			if got := fmt.Sprint(itemNumbers(matched)); got != tt.want {
+				t.Errorf("%q matched %s, want %s", tt.filter, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package litellm
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -21,6 +22,12 @@ type Client struct {
 
 // NewClient creates a new LiteLLM API client
 func NewClient(config entity.LiteLLMConfig, token string) *Client {
+	return NewClientWithTransport(config, token, nil)
+}
+
+// NewClientWithTransport creates a LiteLLM API client that sends requests through transport,
+// e.g. to record or replay them; nil means the default transport
+func NewClientWithTransport(config entity.LiteLLMConfig, token string, transport http.RoundTripper) *Client {
 	timeoutSec := 60
 	if config.TimeoutSec > 0 {
 		timeoutSec = config.TimeoutSec
@@ -31,7 +38,8 @@ func NewClient(config entity.LiteLLMConfig, token string) *Client {
 		token:   token,
 		model:   config.Model,
 		httpClient: &http.Client{
-			Timeout: time.Duration(timeoutSec) * time.Second,
+			Transport: transport,
+			Timeout:   time.Duration(timeoutSec) * time.Second,
 		},
 	}
 }
@@ -127,3 +135,39 @@ Provide a brief analysis focused on achievements and business impact.`, okrData)
 
 	return chatResp.Choices[0].Message.Content, nil
 }
+
+// CheckModel verifies that the endpoint is reachable, accepts the token and serves the configured model
+func (c *Client) CheckModel(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/v1/models", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", c.baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var models struct {
+		Data []struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&models); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	for _, model := range models.Data {
+		if model.ID == c.model {
+			return nil
+		}
+	}
+	return fmt.Errorf("model %q is not served by %s (%d models available)", c.model, c.baseURL, len(models.Data))
+}
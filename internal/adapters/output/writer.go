@@ -17,6 +17,7 @@ import (
 	"strings"
 	"time"
 
+	"github-okr-fetcher/internal/adapters/recorder"
 	"github-okr-fetcher/internal/domain/entity"
 	"github-okr-fetcher/internal/ports"
 
@@ -39,6 +40,11 @@ func NewWriterWithConfig(config *entity.Config) *Writer {
 	return &Writer{config: config}
 }
 
+// now returns the time reports are dated with, which --as-of can fix
+func (w *Writer) now() time.Time {
+	return w.config.Now()
+}
+
 // WriteMarkdown writes objectives as a markdown report
 func (w *Writer) WriteMarkdown(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo, filename string) error {
 	content := w.formatAsMarkdown(objectives, projectInfo)
@@ -51,11 +57,35 @@ func (w *Writer) WriteMarkdownWithAnalysis(objectives []*entity.IssueWithUpdates
 	return os.WriteFile(filename, []byte(content), 0644)
 }
 
-// WriteJSON writes objectives as JSON
-func (w *Writer) WriteJSON(objectives []*entity.IssueWithUpdates, filename string) error {
-	data, err := json.MarshalIndent(objectives, "", "  ")
+// jsonReport is the document written for JSON schema 2
+type jsonReport struct {
+	Project    *entity.ProjectInfo        `json:"project"`
+	Objectives []*entity.IssueWithUpdates `json:"objectives"`
+}
+
+// formatAsJSON marshals objectives as an array, or for schema 2 together with the project they
+// came from, so consumers can tell a partial or truncated report from a complete one
+func (w *Writer) formatAsJSON(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo) ([]byte, error) {
+	var document interface{} = objectives
+	if w.config != nil && w.config.Output.GetJSONSchema() == entity.JSONSchemaWithProject {
+		if objectives == nil {
+			objectives = []*entity.IssueWithUpdates{}
+		}
+		document = jsonReport{Project: projectInfo, Objectives: objectives}
+	}
+
+	data, err := json.MarshalIndent(document, "", "  ")
 	if err != nil {
-		return fmt.Errorf("error marshaling JSON: %v", err)
+		return nil, fmt.Errorf("error marshaling JSON: %v", err)
+	}
+	return data, nil
+}
+
+// WriteJSON writes objectives as JSON in the configured schema
+func (w *Writer) WriteJSON(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo, filename string) error {
+	data, err := w.formatAsJSON(objectives, projectInfo)
+	if err != nil {
+		return err
 	}
 
 	return os.WriteFile(filename, data, 0644)
@@ -123,6 +153,11 @@ func (w *Writer) WriteGoogleDocsWithAnalysis(objectives []*entity.IssueWithUpdat
 
 // formatAsMarkdown formats objectives as markdown content
 func (w *Writer) formatAsMarkdown(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo) string {
+	return w.formatAsMarkdownWithAnalysis(objectives, projectInfo, "")
+}
+
+// formatAsMarkdownWithAnalysis formats objectives as markdown content with LiteLLM analysis
+func (w *Writer) formatAsMarkdownWithAnalysis(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo, analysis string) string {
 	var md strings.Builder
 
 	// Header
@@ -130,6 +165,9 @@ func (w *Writer) formatAsMarkdown(objectives []*entity.IssueWithUpdates, project
 	if w.config != nil && w.config.Output.Title != "" {
 		title = w.config.Output.Title
 	}
+	if projectInfo.Incomplete {
+		title += " (incomplete)"
+	}
 	md.WriteString(fmt.Sprintf("# %s\n\n", title))
 
 	// Project name
@@ -137,9 +175,22 @@ func (w *Writer) formatAsMarkdown(objectives []*entity.IssueWithUpdates, project
 	if w.config != nil && w.config.Output.ProjectName != "" {
 		projectName = w.config.Output.ProjectName
 	}
-	md.WriteString(fmt.Sprintf("📊 **Project**: [%s](https://github.com/orgs/%s/projects/%d/views/%d)\n\n",
-		projectName, projectInfo.Owner, projectInfo.ProjectID, projectInfo.ViewID))
-	md.WriteString(fmt.Sprintf("📅 **Generated**: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	md.WriteString(fmt.Sprintf("📊 **Project**: [%s](%s)\n\n", projectName, projectInfo.WebURL()))
+	if projectInfo.View != nil {
+		md.WriteString(fmt.Sprintf("🔎 **View**: %s\n\n", w.describeProjectView(projectInfo.View)))
+	}
+	md.WriteString(fmt.Sprintf("📅 **Generated**: %s\n\n", w.now().Format("2006-01-02 15:04:05")))
+	for _, warning := range projectInfo.Warnings {
+		md.WriteString(fmt.Sprintf("> ⚠️ **Warning**: %s\n\n", warning))
+	}
+
+	// AI Analysis Section (if available)
+	if analysis != "" {
+		md.WriteString("## 🤖 AI Analysis\n\n")
+		md.WriteString(analysis)
+		md.WriteString("\n\n")
+		md.WriteString("---\n\n")
+	}
 
 	// If no objectives found, provide helpful information
 	if len(objectives) == 0 {
@@ -160,23 +211,22 @@ func (w *Writer) formatAsMarkdown(objectives []*entity.IssueWithUpdates, project
 	atRiskKRs := 0
 	onTrackKRs := 0
 
-	for _, obj := range objectives {
-		totalKRs += len(obj.ChildIssues)
-		for _, kr := range obj.ChildIssues {
-			switch kr.GetKRStatus() {
-			case entity.StatusCompleted:
-				completedKRs++
-			case entity.StatusBlocked:
-				blockedKRs++
-			case entity.StatusDelayed:
-				delayedKRs++
-			case entity.StatusCaution:
-				cautionKRs++
-			case entity.StatusAtRisk:
-				atRiskKRs++
-			case entity.StatusOnTrack:
-				onTrackKRs++
-			}
+	// Key results may sit at any depth of the hierarchy
+	for _, kr := range entity.CollectKeyResults(objectives) {
+		totalKRs++
+		switch kr.GetRolledUpStatus() {
+		case entity.StatusCompleted:
+			completedKRs++
+		case entity.StatusBlocked:
+			blockedKRs++
+		case entity.StatusDelayed:
+			delayedKRs++
+		case entity.StatusCaution:
+			cautionKRs++
+		case entity.StatusAtRisk:
+			atRiskKRs++
+		case entity.StatusOnTrack:
+			onTrackKRs++
 		}
 	}
 
@@ -208,8 +258,12 @@ func (w *Writer) formatAsMarkdown(objectives []*entity.IssueWithUpdates, project
 		md.WriteString(fmt.Sprintf("] %.1f%%\n", completionRate))
 		md.WriteString("```\n\n")
 	}
+	md.WriteString(w.formatCycleTimeSummary(objectives, true))
+	md.WriteString(w.formatPullRequestSummary(objectives, true))
+	md.WriteString(w.formatMilestoneSummary(objectives, true))
 
 	md.WriteString("---\n\n")
+	md.WriteString(w.formatOwnerGroups(objectives, true))
 
 	// Objectives and KRs
 	md.WriteString("## 🎯 Objectives & Key Results\n\n")
@@ -220,30 +274,16 @@ func (w *Writer) formatAsMarkdown(objectives []*entity.IssueWithUpdates, project
 		indicator := w.getStatusIndicator(objStatus)
 
 		md.WriteString(fmt.Sprintf("### %d. %s %s\n", i+1, indicator.Icon, obj.Issue.Title))
-		md.WriteString(fmt.Sprintf("**Issue**: [#%d](%s) | **Status**: %s\n\n",
-			obj.Issue.Number, obj.Issue.URL, indicator.Status))
+		md.WriteString(fmt.Sprintf("**Issue**: [%s](%s) | **Status**: %s%s%s\n\n",
+			obj.Issue.Ref(), obj.Issue.URL, indicator.Status, w.formatProgressInline(obj, true), w.formatFieldColumnsInline(&obj.Issue, true)))
 
 		// Two latest updates for the objective
 		w.formatTwoLatestUpdates(&md, obj)
 
-		// Key Results
+		// Key Results and everything below them, numbered by depth
 		if len(obj.ChildIssues) > 0 {
-			md.WriteString("#### 📋 Key Results:\n\n")
-
-			for j, kr := range obj.ChildIssues {
-				krStatus := kr.GetKRStatus()
-				krIndicator := w.getStatusIndicator(krStatus)
-
-				md.WriteString(fmt.Sprintf("%d.%d. %s **[%s](%s)**\n",
-					i+1, j+1, krIndicator.Icon, kr.Issue.Title, kr.Issue.URL))
-				md.WriteString(fmt.Sprintf("   - **Issue**: [#%d](%s)\n",
-					kr.Issue.Number, kr.Issue.URL))
-				md.WriteString(fmt.Sprintf("   - **Status**: %s\n", krIndicator.Status))
-
-				// Add weekly updates section for KR
-				w.formatWeeklyUpdatesForKR(&md, kr, i+1, j+1)
-				md.WriteString("\n")
-			}
+			md.WriteString(fmt.Sprintf("#### 📋 %s:\n\n", w.childSectionTitle(obj)))
+			w.formatChildIssues(&md, obj.ChildIssues, fmt.Sprintf("%d", i+1))
 		}
 
 		md.WriteString("---\n\n")
@@ -254,158 +294,95 @@ func (w *Writer) formatAsMarkdown(objectives []*entity.IssueWithUpdates, project
 	md.WriteString("- This report is automatically generated from GitHub issues and comments\n")
 	md.WriteString("- Status indicators are detected from weekly update comments\n")
 	md.WriteString("- Click on issue links to view full details and discussions\n")
-	md.WriteString(fmt.Sprintf("- Last updated: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	if analysis != "" {
+		md.WriteString("- AI analysis is provided by LiteLLM for insights and recommendations\n")
+	}
+	md.WriteString(fmt.Sprintf("- Last updated: %s\n\n", w.now().Format("2006-01-02 15:04:05")))
 
 	return md.String()
 }
 
-// formatAsMarkdownWithAnalysis formats objectives as markdown content with LiteLLM analysis
-func (w *Writer) formatAsMarkdownWithAnalysis(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo, analysis string) string {
-	var md strings.Builder
-
-	// Header
-	title := "OKR Report"
-	if w.config != nil && w.config.Output.Title != "" {
-		title = w.config.Output.Title
+// childSectionTitle returns the heading for the children of an objective, e.g. "Key Results"
+func (w *Writer) childSectionTitle(obj *entity.IssueWithUpdates) string {
+	level := "Key Result"
+	if len(obj.ChildIssues) > 0 && obj.ChildIssues[0].Issue.Level != "" {
+		level = obj.ChildIssues[0].Issue.Level
 	}
-	md.WriteString(fmt.Sprintf("# %s\n\n", title))
-
-	// Project name
-	projectName := "Project"
-	if w.config != nil && w.config.Output.ProjectName != "" {
-		projectName = w.config.Output.ProjectName
+	if w.config == nil {
+		return entity.HierarchyConfig{}.PluralName(level)
 	}
-	md.WriteString(fmt.Sprintf("📊 **Project**: [%s](https://github.com/orgs/%s/projects/%d/views/%d)\n\n",
-		projectName, projectInfo.Owner, projectInfo.ProjectID, projectInfo.ViewID))
-	md.WriteString(fmt.Sprintf("📅 **Generated**: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	return w.config.Hierarchy.PluralName(level)
+}
 
-	// AI Analysis Section (if available)
-	if analysis != "" {
-		md.WriteString("## 🤖 AI Analysis\n\n")
-		md.WriteString(analysis)
-		md.WriteString("\n\n")
-		md.WriteString("---\n\n")
+// formatProgressInline renders the rolled-up progress of an issue with children as a " | Progress: N%" suffix
+func (w *Writer) formatProgressInline(issue *entity.IssueWithUpdates, bold bool) string {
+	if len(issue.ChildIssues) == 0 {
+		return ""
 	}
-
-	// If no objectives found, provide helpful information
-	if len(objectives) == 0 {
-		md.WriteString("## ⚠️ No OKR Data Found\n\n")
-		md.WriteString("No issues were found that match the required criteria.\n\n")
-		return md.String()
+	if bold {
+		return fmt.Sprintf(" | **Progress**: %.0f%%", issue.GetProgress()*100)
 	}
+	return fmt.Sprintf(" | Progress: %.0f%%", issue.GetProgress()*100)
+}
 
-	// Summary section
-	md.WriteString("## 📈 Summary\n\n")
+// formatChildIssues renders a subtree, numbering each issue by its path (1.2, 1.2.1, ...)
+func (w *Writer) formatChildIssues(md *strings.Builder, children []entity.IssueWithUpdates, prefix string) {
+	for j, child := range children {
+		number := fmt.Sprintf("%s.%d", prefix, j+1)
+		indicator := w.getStatusIndicator(child.GetRolledUpStatus())
 
-	totalObjectives := len(objectives)
-	totalKRs := 0
-	completedKRs := 0
-	blockedKRs := 0
-	delayedKRs := 0
-	cautionKRs := 0
-	atRiskKRs := 0
-	onTrackKRs := 0
-
-	for _, obj := range objectives {
-		totalKRs += len(obj.ChildIssues)
-		for _, kr := range obj.ChildIssues {
-			switch kr.GetKRStatus() {
-			case entity.StatusCompleted:
-				completedKRs++
-			case entity.StatusBlocked:
-				blockedKRs++
-			case entity.StatusDelayed:
-				delayedKRs++
-			case entity.StatusCaution:
-				cautionKRs++
-			case entity.StatusAtRisk:
-				atRiskKRs++
-			case entity.StatusOnTrack:
-				onTrackKRs++
-			}
+		md.WriteString(fmt.Sprintf("%s. %s **[%s](%s)**\n",
+			number, indicator.Icon, child.Issue.Title, child.Issue.URL))
+		md.WriteString(fmt.Sprintf("   - **Issue**: [%s](%s)\n",
+			child.Issue.Ref(), child.Issue.URL))
+		if child.Issue.Depth > 1 {
+			md.WriteString(fmt.Sprintf("   - **Level**: %s\n", child.Issue.Level))
 		}
-	}
-
-	md.WriteString(fmt.Sprintf("- **Objectives**: %d\n", totalObjectives))
-	md.WriteString(fmt.Sprintf("- **Key Results**: %d\n", totalKRs))
-	md.WriteString(fmt.Sprintf("- ✅ **Completed**: %d\n", completedKRs))
-	md.WriteString(fmt.Sprintf("- 🟢 **On Track**: %d\n", onTrackKRs))
-	md.WriteString(fmt.Sprintf("- 🟡 **Caution**: %d\n", cautionKRs))
-	md.WriteString(fmt.Sprintf("- ⚠️ **At Risk**: %d\n", atRiskKRs))
-	md.WriteString(fmt.Sprintf("- 🔴 **Delayed**: %d\n", delayedKRs))
-	md.WriteString(fmt.Sprintf("- 🚫 **Blocked**: %d\n\n", blockedKRs))
-
-	// Progress bar
-	if totalKRs > 0 {
-		completionRate := float64(completedKRs) / float64(totalKRs) * 100
-		md.WriteString(fmt.Sprintf("**Overall Progress**: %.1f%% (%d/%d completed)\n\n", completionRate, completedKRs, totalKRs))
-
-		// Visual progress bar
-		progressBars := int(completionRate / 10)
-		md.WriteString("```\n")
-		md.WriteString("Progress: [")
-		for i := 0; i < 10; i++ {
-			if i < progressBars {
-				md.WriteString("█")
-			} else {
-				md.WriteString("░")
-			}
+		md.WriteString(fmt.Sprintf("   - **Status**: %s\n", indicator.Status))
+		if len(child.ChildIssues) > 0 {
+			md.WriteString(fmt.Sprintf("   - **Progress**: %.0f%%\n", child.GetProgress()*100))
 		}
-		md.WriteString(fmt.Sprintf("] %.1f%%\n", completionRate))
-		md.WriteString("```\n\n")
-	}
-
-	md.WriteString("---\n\n")
-
-	// Objectives and KRs
-	md.WriteString("## 🎯 Objectives & Key Results\n\n")
-
-	for i, obj := range objectives {
-		// Objective header - use status derived from KRs
-		objStatus := obj.GetObjectiveStatus()
-		indicator := w.getStatusIndicator(objStatus)
-
-		md.WriteString(fmt.Sprintf("### %d. %s %s\n", i+1, indicator.Icon, obj.Issue.Title))
-		md.WriteString(fmt.Sprintf("**Issue**: [#%d](%s) | **Status**: %s\n\n",
-			obj.Issue.Number, obj.Issue.URL, indicator.Status))
-
-		// Two latest updates for the objective
-		w.formatTwoLatestUpdates(&md, obj)
+		md.WriteString(w.formatOwnershipList(&child.Issue, true))
+		md.WriteString(w.formatFieldColumnsList(&child.Issue, true))
+		md.WriteString(w.formatTimelineList(&child.Issue, true))
+		md.WriteString(w.formatPullRequestList(&child, true))
 
-		// Key Results
-		if len(obj.ChildIssues) > 0 {
-			md.WriteString("#### 📋 Key Results:\n\n")
+		// Add weekly updates section for KR
+		w.formatWeeklyUpdatesForKR(md, child)
+		md.WriteString("\n")
 
-			for j, kr := range obj.ChildIssues {
-				krStatus := kr.GetKRStatus()
-				krIndicator := w.getStatusIndicator(krStatus)
+		w.formatChildIssues(md, child.ChildIssues, number)
+	}
+}
 
-				md.WriteString(fmt.Sprintf("%d.%d. %s **[%s](%s)**\n",
-					i+1, j+1, krIndicator.Icon, kr.Issue.Title, kr.Issue.URL))
-				md.WriteString(fmt.Sprintf("   - **Issue**: [#%d](%s)\n",
-					kr.Issue.Number, kr.Issue.URL))
-				md.WriteString(fmt.Sprintf("   - **Status**: %s\n", krIndicator.Status))
+// formatChildIssuesForGoogleDocs renders a subtree in Google Docs format, numbered by path
+func (w *Writer) formatChildIssuesForGoogleDocs(doc *strings.Builder, children []entity.IssueWithUpdates, prefix string) {
+	for j, child := range children {
+		number := fmt.Sprintf("%s.%d", prefix, j+1)
+		indicator := w.getStatusIndicator(child.GetRolledUpStatus())
 
-				// Add weekly updates section for KR
-				w.formatWeeklyUpdatesForKR(&md, kr, i+1, j+1)
-				md.WriteString("\n")
-			}
+		doc.WriteString(fmt.Sprintf("%s. %s %s (%s)\n",
+			number, indicator.Icon, child.Issue.Title, child.Issue.URL))
+		doc.WriteString(fmt.Sprintf("   - Issue: %s (%s)\n",
+			child.Issue.Ref(), child.Issue.URL))
+		if child.Issue.Depth > 1 {
+			doc.WriteString(fmt.Sprintf("   - Level: %s\n", child.Issue.Level))
+		}
+		doc.WriteString(fmt.Sprintf("   - Status: %s\n", indicator.Status))
+		if len(child.ChildIssues) > 0 {
+			doc.WriteString(fmt.Sprintf("   - Progress: %.0f%%\n", child.GetProgress()*100))
 		}
+		doc.WriteString(w.formatOwnershipList(&child.Issue, false))
+		doc.WriteString(w.formatFieldColumnsList(&child.Issue, false))
+		doc.WriteString(w.formatTimelineList(&child.Issue, false))
+		doc.WriteString(w.formatPullRequestList(&child, false))
 
-		md.WriteString("---\n\n")
-	}
+		// Add weekly updates section for KR - use rich formatting
+		w.formatWeeklyUpdatesForKRGoogleDocsRich(doc, child)
+		doc.WriteString("\n")
 
-	// Footer
-	md.WriteString("## 📝 Notes\n\n")
-	md.WriteString("- This report is automatically generated from GitHub issues and comments\n")
-	md.WriteString("- Status indicators are detected from weekly update comments\n")
-	md.WriteString("- Click on issue links to view full details and discussions\n")
-	if analysis != "" {
-		md.WriteString("- AI analysis is provided by LiteLLM for insights and recommendations\n")
+		w.formatChildIssuesForGoogleDocs(doc, child.ChildIssues, number)
 	}
-	md.WriteString(fmt.Sprintf("- Last updated: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
-
-	return md.String()
 }
 
 // formatTwoLatestUpdates formats the two most recent weekly updates in a pretty format
@@ -515,7 +492,7 @@ func (w *Writer) formatWeeklyUpdateContentForGoogleDocsRich(content string) stri
 }
 
 // formatWeeklyUpdatesForKRGoogleDocsRich formats weekly updates for a specific KR in Google Docs with rich formatting
-func (w *Writer) formatWeeklyUpdatesForKRGoogleDocsRich(doc *strings.Builder, kr entity.IssueWithUpdates, objNum, krNum int) {
+func (w *Writer) formatWeeklyUpdatesForKRGoogleDocsRich(doc *strings.Builder, kr entity.IssueWithUpdates) {
 	// Get all weekly updates
 	weeklyUpdates := w.getWeeklyUpdates(kr.AllUpdates)
 
@@ -556,6 +533,7 @@ func (w *Writer) formatWeeklyUpdateContentPrettyGoogleDocsRich(content string) s
 	// Parse content into structured sections (same logic as markdown version but with rich formatting)
 	var currentSection string
 	var statusAssessment map[string]string
+	var statusKeys []string // table rows in document order
 	var goals []string
 	var keyPoints []string
 	var doneItems []string
@@ -578,6 +556,7 @@ func (w *Writer) formatWeeklyUpdateContentPrettyGoogleDocsRich(content string) s
 		if strings.Contains(lowerLine, "<table>") {
 			inTable = true
 			statusAssessment = make(map[string]string)
+			statusKeys = nil
 			continue
 		}
 		if strings.Contains(lowerLine, "</table>") {
@@ -593,6 +572,9 @@ func (w *Writer) formatWeeklyUpdateContentPrettyGoogleDocsRich(content string) s
 				// Extract table value
 				value := w.extractTextFromHTML(trimmedLine)
 				if value != "" && !strings.Contains(value, "Choose one") {
+					if _, seen := statusAssessment[currentKey]; !seen {
+						statusKeys = append(statusKeys, currentKey)
+					}
 					statusAssessment[currentKey] = value
 				}
 			}
@@ -630,7 +612,8 @@ func (w *Writer) formatWeeklyUpdateContentPrettyGoogleDocsRich(content string) s
 	// Status Assessment (if available)
 	if len(statusAssessment) > 0 {
 		result.WriteString("       📊 Status:\n")
-		for key, value := range statusAssessment {
+		for _, key := range statusKeys {
+			value := statusAssessment[key]
 			result.WriteString(fmt.Sprintf("       - %s: %s\n", key, value))
 		}
 		result.WriteString("\n")
@@ -746,7 +729,7 @@ func (w *Writer) formatWeeklyUpdateContentForGoogleDocs(content string) string {
 }
 
 // formatWeeklyUpdatesForKR formats weekly updates for a specific KR
-func (w *Writer) formatWeeklyUpdatesForKR(md *strings.Builder, kr entity.IssueWithUpdates, objNum, krNum int) {
+func (w *Writer) formatWeeklyUpdatesForKR(md *strings.Builder, kr entity.IssueWithUpdates) {
 	// Get all weekly updates
 	weeklyUpdates := w.getWeeklyUpdates(kr.AllUpdates)
 
@@ -810,6 +793,7 @@ func (w *Writer) formatWeeklyUpdateContentPretty(content string) string {
 	// Parse content into structured sections
 	var currentSection string
 	var statusAssessment map[string]string
+	var statusKeys []string // table rows in document order
 	var goals []string
 	var keyPoints []string
 	var doneItems []string
@@ -832,6 +816,7 @@ func (w *Writer) formatWeeklyUpdateContentPretty(content string) string {
 		if strings.Contains(lowerLine, "<table>") {
 			inTable = true
 			statusAssessment = make(map[string]string)
+			statusKeys = nil
 			continue
 		}
 		if strings.Contains(lowerLine, "</table>") {
@@ -847,6 +832,9 @@ func (w *Writer) formatWeeklyUpdateContentPretty(content string) string {
 				// Extract table value
 				value := w.extractTextFromHTML(trimmedLine)
 				if value != "" && !strings.Contains(value, "Choose one") {
+					if _, seen := statusAssessment[currentKey]; !seen {
+						statusKeys = append(statusKeys, currentKey)
+					}
 					statusAssessment[currentKey] = value
 				}
 			}
@@ -884,7 +872,8 @@ func (w *Writer) formatWeeklyUpdateContentPretty(content string) string {
 	// Status Assessment (if available)
 	if len(statusAssessment) > 0 {
 		result.WriteString("       **📊 Status:**\n")
-		for key, value := range statusAssessment {
+		for _, key := range statusKeys {
+			value := statusAssessment[key]
 			result.WriteString(fmt.Sprintf("       - %s: %s\n", key, value))
 		}
 		result.WriteString("\n")
@@ -1026,6 +1015,7 @@ func (w *Writer) formatWeeklyUpdateContentPrettyGoogleDocs(content string) strin
 	// Parse content into structured sections (same logic as markdown version)
 	var currentSection string
 	var statusAssessment map[string]string
+	var statusKeys []string // table rows in document order
 	var goals []string
 	var keyPoints []string
 	var doneItems []string
@@ -1048,6 +1038,7 @@ func (w *Writer) formatWeeklyUpdateContentPrettyGoogleDocs(content string) strin
 		if strings.Contains(lowerLine, "<table>") {
 			inTable = true
 			statusAssessment = make(map[string]string)
+			statusKeys = nil
 			continue
 		}
 		if strings.Contains(lowerLine, "</table>") {
@@ -1063,6 +1054,9 @@ func (w *Writer) formatWeeklyUpdateContentPrettyGoogleDocs(content string) strin
 				// Extract table value
 				value := w.extractTextFromHTML(trimmedLine)
 				if value != "" && !strings.Contains(value, "Choose one") {
+					if _, seen := statusAssessment[currentKey]; !seen {
+						statusKeys = append(statusKeys, currentKey)
+					}
 					statusAssessment[currentKey] = value
 				}
 			}
@@ -1100,7 +1094,8 @@ func (w *Writer) formatWeeklyUpdateContentPrettyGoogleDocs(content string) strin
 	// Status Assessment (if available)
 	if len(statusAssessment) > 0 {
 		result.WriteString("         Status:\n")
-		for key, value := range statusAssessment {
+		for _, key := range statusKeys {
+			value := statusAssessment[key]
 			result.WriteString(fmt.Sprintf("         - %s: %s\n", key, value))
 		}
 		result.WriteString("\n")
@@ -1173,6 +1168,9 @@ func (w *Writer) formatAsGoogleDocs(objectives []*entity.IssueWithUpdates, proje
 	if w.config != nil && w.config.Output.Title != "" {
 		title = w.config.Output.Title
 	}
+	if projectInfo.Incomplete {
+		title += " (incomplete)"
+	}
 	doc.WriteString(fmt.Sprintf("# %s\n\n", title))
 
 	// Project name
@@ -1181,8 +1179,14 @@ func (w *Writer) formatAsGoogleDocs(objectives []*entity.IssueWithUpdates, proje
 		projectName = w.config.Output.ProjectName
 	}
 	doc.WriteString(fmt.Sprintf("📊 Project: %s (%s)\n\n",
-		projectName, fmt.Sprintf("https://github.com/orgs/%s/projects/%d/views/%d", projectInfo.Owner, projectInfo.ProjectID, projectInfo.ViewID)))
-	doc.WriteString(fmt.Sprintf("📅 Generated: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+		projectName, projectInfo.WebURL()))
+	if projectInfo.View != nil {
+		doc.WriteString(fmt.Sprintf("🔎 View: %s\n\n", w.describeProjectView(projectInfo.View)))
+	}
+	doc.WriteString(fmt.Sprintf("📅 Generated: %s\n\n", w.now().Format("2006-01-02 15:04:05")))
+	for _, warning := range projectInfo.Warnings {
+		doc.WriteString(fmt.Sprintf("⚠️ Warning: %s\n\n", warning))
+	}
 
 	// If no objectives found
 	if len(objectives) == 0 {
@@ -1203,23 +1207,22 @@ func (w *Writer) formatAsGoogleDocs(objectives []*entity.IssueWithUpdates, proje
 	atRiskKRs := 0
 	onTrackKRs := 0
 
-	for _, obj := range objectives {
-		totalKRs += len(obj.ChildIssues)
-		for _, kr := range obj.ChildIssues {
-			switch kr.GetKRStatus() {
-			case entity.StatusCompleted:
-				completedKRs++
-			case entity.StatusBlocked:
-				blockedKRs++
-			case entity.StatusDelayed:
-				delayedKRs++
-			case entity.StatusCaution:
-				cautionKRs++
-			case entity.StatusAtRisk:
-				atRiskKRs++
-			case entity.StatusOnTrack:
-				onTrackKRs++
-			}
+	// Key results may sit at any depth of the hierarchy
+	for _, kr := range entity.CollectKeyResults(objectives) {
+		totalKRs++
+		switch kr.GetRolledUpStatus() {
+		case entity.StatusCompleted:
+			completedKRs++
+		case entity.StatusBlocked:
+			blockedKRs++
+		case entity.StatusDelayed:
+			delayedKRs++
+		case entity.StatusCaution:
+			cautionKRs++
+		case entity.StatusAtRisk:
+			atRiskKRs++
+		case entity.StatusOnTrack:
+			onTrackKRs++
 		}
 	}
 
@@ -1249,8 +1252,12 @@ func (w *Writer) formatAsGoogleDocs(objectives []*entity.IssueWithUpdates, proje
 		}
 		doc.WriteString(fmt.Sprintf("] %.1f%%\n\n", completionRate))
 	}
+	doc.WriteString(w.formatCycleTimeSummary(objectives, false))
+	doc.WriteString(w.formatPullRequestSummary(objectives, false))
+	doc.WriteString(w.formatMilestoneSummary(objectives, false))
 
 	doc.WriteString("---\n\n")
+	doc.WriteString(w.formatOwnerGroups(objectives, false))
 
 	// Objectives and KRs - match markdown format exactly
 	doc.WriteString("## 🎯 Objectives & Key Results\n\n")
@@ -1261,30 +1268,16 @@ func (w *Writer) formatAsGoogleDocs(objectives []*entity.IssueWithUpdates, proje
 		indicator := w.getStatusIndicator(objStatus)
 
 		doc.WriteString(fmt.Sprintf("### %d. %s %s\n", i+1, indicator.Icon, obj.Issue.Title))
-		doc.WriteString(fmt.Sprintf("Issue: #%d (%s) | Status: %s\n\n",
-			obj.Issue.Number, obj.Issue.URL, indicator.Status))
+		doc.WriteString(fmt.Sprintf("Issue: %s (%s) | Status: %s%s%s\n\n",
+			obj.Issue.Ref(), obj.Issue.URL, indicator.Status, w.formatProgressInline(obj, false), w.formatFieldColumnsInline(&obj.Issue, false)))
 
 		// Two latest updates for the objective - use markdown-style formatting
 		w.formatTwoLatestUpdatesForGoogleDocsRich(&doc, obj)
 
-		// Key Results
+		// Key Results and everything below them, numbered by depth
 		if len(obj.ChildIssues) > 0 {
-			doc.WriteString("#### 📋 Key Results:\n\n")
-
-			for j, kr := range obj.ChildIssues {
-				krStatus := kr.GetKRStatus()
-				krIndicator := w.getStatusIndicator(krStatus)
-
-				doc.WriteString(fmt.Sprintf("%d.%d. %s %s (%s)\n",
-					i+1, j+1, krIndicator.Icon, kr.Issue.Title, kr.Issue.URL))
-				doc.WriteString(fmt.Sprintf("   - Issue: #%d (%s)\n",
-					kr.Issue.Number, kr.Issue.URL))
-				doc.WriteString(fmt.Sprintf("   - Status: %s\n", krIndicator.Status))
-
-				// Add weekly updates section for KR - use rich formatting
-				w.formatWeeklyUpdatesForKRGoogleDocsRich(&doc, kr, i+1, j+1)
-				doc.WriteString("\n")
-			}
+			doc.WriteString(fmt.Sprintf("#### 📋 %s:\n\n", w.childSectionTitle(obj)))
+			w.formatChildIssuesForGoogleDocs(&doc, obj.ChildIssues, fmt.Sprintf("%d", i+1))
 		}
 
 		doc.WriteString("---\n\n")
@@ -1295,11 +1288,298 @@ func (w *Writer) formatAsGoogleDocs(objectives []*entity.IssueWithUpdates, proje
 	doc.WriteString("- This report is automatically generated from GitHub issues and comments\n")
 	doc.WriteString("- Status indicators are detected from weekly update comments\n")
 	doc.WriteString("- Click on issue links to view full details and discussions\n")
-	doc.WriteString(fmt.Sprintf("- Last updated: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	doc.WriteString(fmt.Sprintf("- Last updated: %s\n\n", w.now().Format("2006-01-02 15:04:05")))
 
 	return doc.String()
 }
 
+// describeProjectView summarizes the project view a report mirrors
+func (w *Writer) describeProjectView(view *entity.ProjectView) string {
+	parts := []string{view.Name}
+	if view.Filter != "" {
+		parts = append(parts, fmt.Sprintf("filter: %s", view.Filter))
+	}
+	if len(view.SortBy) > 0 {
+		parts = append(parts, fmt.Sprintf("sorted by %s", strings.Join(view.SortBy, ", ")))
+	}
+	if len(view.GroupBy) > 0 {
+		parts = append(parts, fmt.Sprintf("grouped by %s", strings.Join(view.GroupBy, ", ")))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// getFieldColumns returns the configured project field columns that have a value on the issue
+func (w *Writer) getFieldColumns(issue *entity.Issue) []entity.ProjectFieldValue {
+	if w.config == nil {
+		return nil
+	}
+
+	var columns []entity.ProjectFieldValue
+	for _, name := range w.config.ProjectFields.Columns {
+		if field := issue.GetField(name); field != nil && field.String() != "" {
+			columns = append(columns, *field)
+		}
+	}
+	return columns
+}
+
+// formatFieldColumnsInline renders the configured field columns as " | Name: value" suffixes
+func (w *Writer) formatFieldColumnsInline(issue *entity.Issue, bold bool) string {
+	var sb strings.Builder
+	for _, field := range w.getFieldColumns(issue) {
+		if bold {
+			sb.WriteString(fmt.Sprintf(" | **%s**: %s", field.Name, field.String()))
+		} else {
+			sb.WriteString(fmt.Sprintf(" | %s: %s", field.Name, field.String()))
+		}
+	}
+	return sb.String()
+}
+
+// formatFieldColumnsList renders the configured field columns as KR bullet lines
+func (w *Writer) formatFieldColumnsList(issue *entity.Issue, bold bool) string {
+	var sb strings.Builder
+	for _, field := range w.getFieldColumns(issue) {
+		if bold {
+			sb.WriteString(fmt.Sprintf("   - **%s**: %s\n", field.Name, field.String()))
+		} else {
+			sb.WriteString(fmt.Sprintf("   - %s: %s\n", field.Name, field.String()))
+		}
+	}
+	return sb.String()
+}
+
+// formatTimelineList renders when a completed issue was closed and how often it was reopened as KR bullet lines
+func (w *Writer) formatTimelineList(issue *entity.Issue, bold bool) string {
+	var sb strings.Builder
+	writeLine := func(name, value string) {
+		if bold {
+			sb.WriteString(fmt.Sprintf("   - **%s**: %s\n", name, value))
+		} else {
+			sb.WriteString(fmt.Sprintf("   - %s: %s\n", name, value))
+		}
+	}
+
+	if completed, ok := issue.CompletedAt(); ok {
+		value := completed.Format("2006-01-02")
+		if cycleTime, ok := issue.CycleTime(); ok {
+			value += fmt.Sprintf(" (open %s)", formatDays(cycleTime))
+		}
+		writeLine("Completed on", value)
+	}
+	if reopened := issue.ReopenCount(); reopened == 1 {
+		writeLine("Reopened", "1 time")
+	} else if reopened > 1 {
+		writeLine("Reopened", fmt.Sprintf("%d times", reopened))
+	}
+	return sb.String()
+}
+
+// formatCycleTimeSummary renders the average cycle time of completed key results, or "" if none has one
+func (w *Writer) formatCycleTimeSummary(objectives []*entity.IssueWithUpdates, bold bool) string {
+	average, count := entity.AverageCycleTime(entity.CollectKeyResults(objectives))
+	if count == 0 {
+		return ""
+	}
+	label := "Average KR Cycle Time"
+	if bold {
+		label = "**" + label + "**"
+	}
+	keyResults := "key results"
+	if count == 1 {
+		keyResults = "key result"
+	}
+	return fmt.Sprintf("%s: %s across %d completed %s\n\n", label, formatDays(average), count, keyResults)
+}
+
+// formatPullRequestList renders the pull request evidence of an issue as KR bullet lines,
+// flagging key results reported on track without recent merges
+func (w *Writer) formatPullRequestList(issue *entity.IssueWithUpdates, bold bool) string {
+	evidence := issue.PullRequestEvidence
+	if evidence == nil {
+		return ""
+	}
+
+	var sb strings.Builder
+	if evidence.Linked > 0 {
+		merged := fmt.Sprintf("%d merged", evidence.MergedSinceUpdate)
+		if issue.LatestUpdate != nil {
+			merged += " since the last update"
+		}
+		value := fmt.Sprintf("%s, %d linked (%d open)", merged, evidence.Linked, evidence.Open)
+		if bold {
+			sb.WriteString(fmt.Sprintf("   - **Pull Requests**: %s\n", value))
+		} else {
+			sb.WriteString(fmt.Sprintf("   - Pull Requests: %s\n", value))
+		}
+	}
+	if evidence.NoRecentMerges {
+		if bold {
+			sb.WriteString(fmt.Sprintf("   - 🚩 **No merged PRs in %s** while reported on track\n", evidenceWindowText()))
+		} else {
+			sb.WriteString(fmt.Sprintf("   - 🚩 No merged PRs in %s while reported on track\n", evidenceWindowText()))
+		}
+	}
+	return sb.String()
+}
+
+// formatPullRequestSummary renders the pull requests merged since the last update across all key
+// results and the key results on track without recent merges, or "" if no pull requests were fetched
+func (w *Writer) formatPullRequestSummary(objectives []*entity.IssueWithUpdates, bold bool) string {
+	var withEvidence, mergedSinceUpdate int
+	var flagged []string
+	for _, kr := range entity.CollectKeyResults(objectives) {
+		if kr.PullRequestEvidence == nil {
+			continue
+		}
+		withEvidence++
+		mergedSinceUpdate += kr.PullRequestEvidence.MergedSinceUpdate
+		if kr.PullRequestEvidence.NoRecentMerges {
+			flagged = append(flagged, kr.Issue.Ref().String())
+		}
+	}
+	if withEvidence == 0 {
+		return ""
+	}
+
+	keyResults := "key results"
+	if withEvidence == 1 {
+		keyResults = "key result"
+	}
+	mergedLabel := "Merged PRs Since Last Update"
+	flaggedLabel := fmt.Sprintf("On Track Without Merged PRs in %s", evidenceWindowText())
+	if bold {
+		mergedLabel = "**" + mergedLabel + "**"
+		flaggedLabel = "**" + flaggedLabel + "**"
+	}
+
+	summary := fmt.Sprintf("%s: %d across %d %s\n\n", mergedLabel, mergedSinceUpdate, withEvidence, keyResults)
+	if len(flagged) > 0 {
+		summary += fmt.Sprintf("🚩 %s: %s\n\n", flaggedLabel, strings.Join(flagged, ", "))
+	}
+	return summary
+}
+
+// evidenceWindowText renders entity.EvidenceWindow, e.g. "4 weeks"
+func evidenceWindowText() string {
+	return fmt.Sprintf("%d weeks", int(entity.EvidenceWindow.Hours()/24/7))
+}
+
+// formatOwnershipList renders the owner, other assignees and milestone of an issue as KR bullet lines, marking
+// open issues whose milestone is overdue or due soon
+func (w *Writer) formatOwnershipList(issue *entity.Issue, bold bool) string {
+	var sb strings.Builder
+	writeLine := func(name, value string) {
+		if bold {
+			sb.WriteString(fmt.Sprintf("   - **%s**: %s\n", name, value))
+		} else {
+			sb.WriteString(fmt.Sprintf("   - %s: %s\n", name, value))
+		}
+	}
+
+	if owner := issue.OwnerLogin(); owner != "" {
+		writeLine("Owner", "@"+owner)
+		// Only the first assignee owns the issue; the others are listed apart
+		if others := issue.Assignees[1:]; len(others) > 0 {
+			assignees := make([]string, len(others))
+			for i, login := range others {
+				assignees[i] = "@" + login
+			}
+			writeLine("Assignees", strings.Join(assignees, ", "))
+		}
+	} else if issue.IsKeyResult() {
+		writeLine("Owner", "unassigned")
+	}
+
+	if issue.Milestone != nil {
+		value := issue.Milestone.Title
+		if issue.Milestone.DueOn != nil {
+			value += fmt.Sprintf(" (due %s)", issue.Milestone.DueOn.Format("2006-01-02"))
+		}
+		if marker := w.dueMarker(issue); marker != "" {
+			value += " " + marker
+		}
+		writeLine("Milestone", value)
+	}
+	return sb.String()
+}
+
+// dueMarker returns "🚨 overdue" or "⏰ due soon" for an open issue with a dated milestone, or ""
+func (w *Writer) dueMarker(issue *entity.Issue) string {
+	switch issue.DueState(w.now()) {
+	case entity.DueOverdue:
+		return "🚨 overdue"
+	case entity.DueSoon:
+		return "⏰ due soon"
+	}
+	return ""
+}
+
+// formatMilestoneSummary renders how many key results are overdue or due soon, or "" if none is
+func (w *Writer) formatMilestoneSummary(objectives []*entity.IssueWithUpdates, bold bool) string {
+	var overdue, dueSoon int
+	for _, kr := range entity.CollectKeyResults(objectives) {
+		switch kr.Issue.DueState(w.now()) {
+		case entity.DueOverdue:
+			overdue++
+		case entity.DueSoon:
+			dueSoon++
+		}
+	}
+	if overdue == 0 && dueSoon == 0 {
+		return ""
+	}
+
+	label := "Milestones"
+	if bold {
+		label = "**" + label + "**"
+	}
+	return fmt.Sprintf("%s: 🚨 %d overdue, ⏰ %d due soon\n\n", label, overdue, dueSoon)
+}
+
+// formatOwnerGroups renders the key results grouped by owner when output.group_by is "owner",
+// with links in Markdown or URLs in parentheses in plain text
+func (w *Writer) formatOwnerGroups(objectives []*entity.IssueWithUpdates, markdown bool) string {
+	if w.config == nil || w.config.Output.GroupBy != entity.GroupByOwner {
+		return ""
+	}
+	groups := entity.GroupKeyResultsByOwner(objectives)
+	if len(groups) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString("## 👤 Key Results by Owner\n\n")
+	for _, group := range groups {
+		owner := "Unassigned"
+		if group.Owner != "" {
+			owner = "@" + group.Owner
+		}
+		sb.WriteString(fmt.Sprintf("### %s (%d)\n\n", owner, len(group.KeyResults)))
+
+		for _, kr := range group.KeyResults {
+			indicator := w.getStatusIndicator(kr.GetRolledUpStatus())
+			if markdown {
+				sb.WriteString(fmt.Sprintf("- %s [%s](%s) | **Status**: %s", indicator.Icon, kr.Issue.Title, kr.Issue.URL, indicator.Status))
+			} else {
+				sb.WriteString(fmt.Sprintf("- %s %s (%s) | Status: %s", indicator.Icon, kr.Issue.Title, kr.Issue.URL, indicator.Status))
+			}
+			if marker := w.dueMarker(&kr.Issue); marker != "" {
+				sb.WriteString(" | " + marker)
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("---\n\n")
+	return sb.String()
+}
+
+// formatDays renders a duration in days, e.g. "18.5 days"
+func formatDays(d time.Duration) string {
+	return fmt.Sprintf("%.1f days", d.Hours()/24)
+}
+
 // StatusIndicator represents the visual status of an issue
 type StatusIndicator struct {
 	Status string
@@ -1337,6 +1617,24 @@ type googleDocsClient struct {
 func (w *Writer) newGoogleDocsClientOAuth(clientID, clientSecret string) (*googleDocsClient, error) {
 	ctx := context.Background()
 
+	var recording entity.RecordingConfig
+	if w.config != nil {
+		recording = w.config.Recording
+	}
+	apiTransport, err := recorder.NewTransport(recording, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Replayed runs answer every Docs API call from fixtures, so no consent is needed
+	if recording.IsReplay() {
+		return &googleDocsClient{
+			httpClient: &http.Client{Transport: apiTransport},
+			ctx:        ctx,
+			writer:     w,
+		}, nil
+	}
+
 	// Find an available port for the callback server
 	availablePort, err := w.findAvailablePort()
 	if err != nil {
@@ -1358,8 +1656,10 @@ func (w *Writer) newGoogleDocsClientOAuth(clientID, clientSecret string) (*googl
 		return nil, fmt.Errorf("failed to get OAuth2 token: %v", err)
 	}
 
-	// Create HTTP client with token
-	client := config.Client(ctx, token)
+	// Create HTTP client with token; only Docs API calls go through the recorder, not token refreshes
+	client := &http.Client{
+		Transport: &oauth2.Transport{Source: config.TokenSource(ctx, token), Base: apiTransport},
+	}
 
 	return &googleDocsClient{
 		httpClient: client,
@@ -1494,7 +1794,7 @@ func (w *Writer) createMarkdownFile(content string, projectInfo *entity.ProjectI
 	}
 
 	// Add timestamp to make it unique
-	timestamp := time.Now().Format("20060102-150405")
+	timestamp := w.now().Format("20060102-150405")
 	filename = fmt.Sprintf("%s_%s.md", filename, timestamp)
 
 	// Write to current directory
@@ -1522,7 +1822,7 @@ func (gdc *googleDocsClient) convertMarkdownToGoogleDocs(documentURL, markdownCo
 	fmt.Printf("🔗 Document ID: %s\n", documentID)
 
 	// Create a new section with timestamp
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
+	timestamp := gdc.writer.now().Format("2006-01-02 15:04:05")
 	sectionTitle := fmt.Sprintf("OKR Report - %s", timestamp)
 	
 	fmt.Printf("📑 Creating new section: %s\n", sectionTitle)
@@ -1733,6 +2033,9 @@ func (gdc *googleDocsClient) buildPlainTextContent(objectives []*entity.IssueWit
 	if gdc.writer != nil && gdc.writer.config != nil && gdc.writer.config.Output.Title != "" {
 		title = gdc.writer.config.Output.Title
 	}
+	if projectInfo.Incomplete {
+		title += " (incomplete)"
+	}
 	content.WriteString(title + "\n\n")
 
 	// Project info
@@ -1740,12 +2043,20 @@ func (gdc *googleDocsClient) buildPlainTextContent(objectives []*entity.IssueWit
 	if gdc.writer != nil && gdc.writer.config != nil && gdc.writer.config.Output.ProjectName != "" {
 		projectName = gdc.writer.config.Output.ProjectName
 	}
-	projectUrl := fmt.Sprintf("https://github.com/orgs/%s/projects/%d/views/%d",
-		projectInfo.Owner, projectInfo.ProjectID, projectInfo.ViewID)
+	projectUrl := projectInfo.WebURL()
 	content.WriteString(fmt.Sprintf("📊 Project: %s (%s)\n\n", projectName, projectUrl))
 
+	if projectInfo.View != nil {
+		content.WriteString(fmt.Sprintf("🔎 View: %s\n\n", gdc.writer.describeProjectView(projectInfo.View)))
+	}
+
 	// Generated timestamp
-	content.WriteString(fmt.Sprintf("📅 Generated: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	content.WriteString(fmt.Sprintf("📅 Generated: %s\n\n", gdc.writer.now().Format("2006-01-02 15:04:05")))
+
+	// Warnings about incomplete data
+	for _, warning := range projectInfo.Warnings {
+		content.WriteString(fmt.Sprintf("⚠️ Warning: %s\n\n", warning))
+	}
 
 	// AI Analysis Section (if available)
 	if analysis != "" {
@@ -1768,23 +2079,22 @@ func (gdc *googleDocsClient) buildPlainTextContent(objectives []*entity.IssueWit
 	atRiskKRs := 0
 	onTrackKRs := 0
 
-	for _, obj := range objectives {
-		totalKRs += len(obj.ChildIssues)
-		for _, kr := range obj.ChildIssues {
-			switch kr.GetKRStatus() {
-			case entity.StatusCompleted:
-				completedKRs++
-			case entity.StatusBlocked:
-				blockedKRs++
-			case entity.StatusDelayed:
-				delayedKRs++
-			case entity.StatusCaution:
-				cautionKRs++
-			case entity.StatusAtRisk:
-				atRiskKRs++
-			case entity.StatusOnTrack:
-				onTrackKRs++
-			}
+	// Key results may sit at any depth of the hierarchy
+	for _, kr := range entity.CollectKeyResults(objectives) {
+		totalKRs++
+		switch kr.GetRolledUpStatus() {
+		case entity.StatusCompleted:
+			completedKRs++
+		case entity.StatusBlocked:
+			blockedKRs++
+		case entity.StatusDelayed:
+			delayedKRs++
+		case entity.StatusCaution:
+			cautionKRs++
+		case entity.StatusAtRisk:
+			atRiskKRs++
+		case entity.StatusOnTrack:
+			onTrackKRs++
 		}
 	}
 
@@ -1817,8 +2127,12 @@ func (gdc *googleDocsClient) buildPlainTextContent(objectives []*entity.IssueWit
 		content.WriteString(fmt.Sprintf("] %.1f%%\n", completionRate))
 		content.WriteString("```\n\n")
 	}
+	content.WriteString(gdc.writer.formatCycleTimeSummary(objectives, true))
+	content.WriteString(gdc.writer.formatPullRequestSummary(objectives, true))
+	content.WriteString(gdc.writer.formatMilestoneSummary(objectives, true))
 
 	content.WriteString("---\n\n")
+	content.WriteString(gdc.writer.formatOwnerGroups(objectives, true))
 
 	// Objectives and KRs section (match Markdown ## style)
 	content.WriteString("## 🎯 Objectives & Key Results\n\n")
@@ -1830,47 +2144,12 @@ func (gdc *googleDocsClient) buildPlainTextContent(objectives []*entity.IssueWit
 
 		// Objective heading (match Markdown ### style)
 		content.WriteString(fmt.Sprintf("### %d. %s %s\n", i+1, indicator.Icon, obj.Issue.Title))
-		content.WriteString(fmt.Sprintf("**Issue**: [#%d](%s) | **Status**: %s\n\n", obj.Issue.Number, obj.Issue.URL, indicator.Status))
+		content.WriteString(fmt.Sprintf("**Issue**: [%s](%s) | **Status**: %s%s%s\n\n", obj.Issue.Ref(), obj.Issue.URL, indicator.Status, gdc.writer.formatProgressInline(obj, true), gdc.writer.formatFieldColumnsInline(&obj.Issue, true)))
 
-		// Key Results (match Markdown #### style)
+		// Key Results and everything below them (match Markdown numbering)
 		if len(obj.ChildIssues) > 0 {
-			content.WriteString("#### 📋 Key Results:\n\n")
-
-			for j, kr := range obj.ChildIssues {
-				krStatus := kr.GetKRStatus()
-				krIndicator := gdc.writer.getStatusIndicator(krStatus)
-
-				// KR title with status (match Markdown format)
-				content.WriteString(fmt.Sprintf("%d.%d. %s **[%s](%s)**\n", i+1, j+1, krIndicator.Icon, kr.Issue.Title, kr.Issue.URL))
-				content.WriteString(fmt.Sprintf("   - **Issue**: [#%d](%s)\n", kr.Issue.Number, kr.Issue.URL))
-				content.WriteString(fmt.Sprintf("   - **Status**: %s\n", krIndicator.Status))
-
-				// Weekly updates for KRs (match Markdown format)
-				weeklyUpdates := gdc.writer.getWeeklyUpdates(kr.AllUpdates)
-				if len(weeklyUpdates) > 0 {
-					content.WriteString("   - **Weekly Updates**:\n")
-
-					maxUpdates := 2
-					if len(weeklyUpdates) < maxUpdates {
-						maxUpdates = len(weeklyUpdates)
-					}
-
-					for k := 0; k < maxUpdates; k++ {
-						update := weeklyUpdates[k]
-						updateLabel := "Latest"
-						if k == 1 {
-							updateLabel = "Previous"
-						}
-
-						content.WriteString(fmt.Sprintf("     - **%s** (%s by @%s):\n", updateLabel, update.Date, update.Author))
-
-						// Format the update content nicely (preserve Markdown structure)
-						formattedContent := gdc.formatUpdateContentForGoogleDocs(update.Content)
-						content.WriteString(formattedContent + "\n")
-					}
-				}
-				content.WriteString("\n")
-			}
+			content.WriteString(fmt.Sprintf("#### 📋 %s:\n\n", gdc.writer.childSectionTitle(obj)))
+			gdc.writeChildIssues(&content, obj.ChildIssues, fmt.Sprintf("%d", i+1))
 		}
 		content.WriteString("---\n\n")
 	}
@@ -1880,11 +2159,62 @@ func (gdc *googleDocsClient) buildPlainTextContent(objectives []*entity.IssueWit
 	content.WriteString("- This report is automatically generated from GitHub issues and comments\n")
 	content.WriteString("- Status indicators are detected from weekly update comments\n")
 	content.WriteString("- Click on issue links to view full details and discussions\n")
-	content.WriteString(fmt.Sprintf("- Last updated: %s\n\n", time.Now().Format("2006-01-02 15:04:05")))
+	content.WriteString(fmt.Sprintf("- Last updated: %s\n\n", gdc.writer.now().Format("2006-01-02 15:04:05")))
 
 	return content.String()
 }
 
+// writeChildIssues renders a subtree for the Google Docs API, numbered by path (match Markdown format)
+func (gdc *googleDocsClient) writeChildIssues(content *strings.Builder, children []entity.IssueWithUpdates, prefix string) {
+	for j, child := range children {
+		number := fmt.Sprintf("%s.%d", prefix, j+1)
+		indicator := gdc.writer.getStatusIndicator(child.GetRolledUpStatus())
+
+		// Title with status (match Markdown format)
+		content.WriteString(fmt.Sprintf("%s. %s **[%s](%s)**\n", number, indicator.Icon, child.Issue.Title, child.Issue.URL))
+		content.WriteString(fmt.Sprintf("   - **Issue**: [%s](%s)\n", child.Issue.Ref(), child.Issue.URL))
+		if child.Issue.Depth > 1 {
+			content.WriteString(fmt.Sprintf("   - **Level**: %s\n", child.Issue.Level))
+		}
+		content.WriteString(fmt.Sprintf("   - **Status**: %s\n", indicator.Status))
+		if len(child.ChildIssues) > 0 {
+			content.WriteString(fmt.Sprintf("   - **Progress**: %.0f%%\n", child.GetProgress()*100))
+		}
+		content.WriteString(gdc.writer.formatOwnershipList(&child.Issue, true))
+		content.WriteString(gdc.writer.formatFieldColumnsList(&child.Issue, true))
+		content.WriteString(gdc.writer.formatTimelineList(&child.Issue, true))
+		content.WriteString(gdc.writer.formatPullRequestList(&child, true))
+
+		// Weekly updates (match Markdown format)
+		weeklyUpdates := gdc.writer.getWeeklyUpdates(child.AllUpdates)
+		if len(weeklyUpdates) > 0 {
+			content.WriteString("   - **Weekly Updates**:\n")
+
+			maxUpdates := 2
+			if len(weeklyUpdates) < maxUpdates {
+				maxUpdates = len(weeklyUpdates)
+			}
+
+			for k := 0; k < maxUpdates; k++ {
+				update := weeklyUpdates[k]
+				updateLabel := "Latest"
+				if k == 1 {
+					updateLabel = "Previous"
+				}
+
+				content.WriteString(fmt.Sprintf("     - **%s** (%s by @%s):\n", updateLabel, update.Date, update.Author))
+
+				// Format the update content nicely (preserve Markdown structure)
+				formattedContent := gdc.formatUpdateContentForGoogleDocs(update.Content)
+				content.WriteString(formattedContent + "\n")
+			}
+		}
+		content.WriteString("\n")
+
+		gdc.writeChildIssues(content, child.ChildIssues, number)
+	}
+}
+
 // buildFormattingRequests builds the formatting requests to apply basic styling safely
 func (gdc *googleDocsClient) buildFormattingRequests(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo, analysis string) []map[string]interface{} {
 	// Use a simplified approach to avoid complex index tracking that can cause errors
@@ -1900,6 +2230,9 @@ func (gdc *googleDocsClient) buildFormattingRequests(objectives []*entity.IssueW
 	if gdc.writer != nil && gdc.writer.config != nil && gdc.writer.config.Output.Title != "" {
 		title = gdc.writer.config.Output.Title
 	}
+	if projectInfo.Incomplete {
+		title += " (incomplete)"
+	}
 
 	titleEnd := 1 + len(title)
 	requests = append(requests, map[string]interface{}{
@@ -1922,6 +2255,7 @@ func (gdc *googleDocsClient) buildFormattingRequests(objectives []*entity.IssueW
 	}{
 		{"## 🤖 AI Analysis", "HEADING_1"},
 		{"## 📈 Summary", "HEADING_1"},
+		{"## 👤 Key Results by Owner", "HEADING_1"},
 		{"## 🎯 Objectives & Key Results", "HEADING_1"},
 		{"## 📝 Notes", "HEADING_1"},
 	}
@@ -2197,7 +2531,7 @@ func (r *ReportGenerator) GenerateReport(objectives []*entity.IssueWithUpdates,
 	case ports.OutputFormatMarkdown:
 		return r.writer.WriteMarkdown(objectives, projectInfo, filename)
 	case ports.OutputFormatJSON:
-		return r.writer.WriteJSON(objectives, filename)
+		return r.writer.WriteJSON(objectives, projectInfo, filename)
 	case ports.OutputFormatGoogleDocs:
 		// For Google Docs, just create a plain text file as fallback
 		content := r.writer.formatAsGoogleDocs(objectives, projectInfo)
@@ -2231,10 +2565,10 @@ func (r *ReportGenerator) FormatAsMarkdown(objectives []*entity.IssueWithUpdates
 }
 
 // FormatAsJSON returns JSON formatted content
-func (r *ReportGenerator) FormatAsJSON(objectives []*entity.IssueWithUpdates) (string, error) {
-	data, err := json.MarshalIndent(objectives, "", "  ")
+func (r *ReportGenerator) FormatAsJSON(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo) (string, error) {
+	data, err := r.writer.formatAsJSON(objectives, projectInfo)
 	if err != nil {
-		return "", fmt.Errorf("error marshaling JSON: %v", err)
+		return "", err
 	}
 	return string(data), nil
 }
@@ -0,0 +1,513 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github-okr-fetcher/internal/domain/entity"
+	"github-okr-fetcher/internal/ports"
+)
+
+// sampleReport returns a small OKR tree: one objective with a completed key result and a key
+// result at risk that has an initiative below it
+func sampleReport() ([]*entity.IssueWithUpdates, *entity.ProjectInfo) {
+	update := func(date string, status entity.WeeklyUpdateStatus, content string) entity.WeeklyUpdate {
+		return entity.WeeklyUpdate{Date: date, Author: "alice", Status: status, Content: content}
+	}
+	atRisk := []entity.WeeklyUpdate{
+		update("2025-01-13", entity.StatusAtRisk, "# Weekly update 2025-01-13\n"+
+			"<table>\n<tr><th>Status</th>\n<td><span>At risk</span></td></tr>\n<tr><th>Confidence</th>\n<td><span>Low</span></td></tr>\n</table>\n"+
+			"## 🎉 Done\n- Load tests written\n## 🗒 Notes\n- Vendor contract is late"),
+		update("2025-01-06", entity.StatusOnTrack, "# Weekly update 2025-01-06\n🟢 On track"),
+	}
+
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+	reopened := []entity.IssueEvent{
+		{Type: entity.EventOpened, At: day(2), Actor: "alice"},
+		{Type: entity.EventClosed, At: day(10), Actor: "alice", StateReason: "completed"},
+		{Type: entity.EventReopened, At: day(12), Actor: "bob"},
+		{Type: entity.EventClosed, At: day(20), Actor: "alice", StateReason: "completed"},
+	}
+	pullRequest := func(repo string, number int, state entity.PullRequestState, merged int) entity.PullRequest {
+		pr := entity.PullRequest{Repository: repo, Number: number, State: state, CreatedAt: day(1),
+			URL: fmt.Sprintf("https://github.com/%s/pull/%d", repo, number)}
+		if merged > 0 {
+			mergedAt := day(merged)
+			pr.MergedAt = &mergedAt
+		}
+		return pr
+	}
+	milestone := func(title string, month time.Month, d int) *entity.Milestone {
+		dueOn := time.Date(2025, month, d, 8, 0, 0, 0, time.UTC)
+		return &entity.Milestone{Title: title, DueOn: &dueOn, State: "open"}
+	}
+
+	objective := &entity.IssueWithUpdates{
+		Issue: entity.Issue{Number: 1, Title: "Faster checkout", URL: "https://github.com/acme/okrs/issues/1",
+			Type: entity.IssueTypeObjective, State: "open", Level: "Objective"},
+		ChildIssues: []entity.IssueWithUpdates{
+			{Issue: entity.Issue{Number: 2, Title: "p95 latency below 300ms", URL: "https://github.com/acme/okrs/issues/2",
+				Type: entity.IssueTypeKeyResult, State: "closed", Depth: 1, Level: "Key Result", Timeline: reopened,
+				Assignees: []string{"alice"}, Milestone: milestone("Q1", time.January, 15),
+				PullRequests: []entity.PullRequest{pullRequest("acme/okrs", 30, entity.PullRequestMerged, 19)}}},
+			{
+				Issue: entity.Issue{Number: 5, Title: "API error budget", URL: "https://github.com/acme/api/issues/5",
+					Type: entity.IssueTypeKeyResult, State: "open", Depth: 1, Level: "Key Result",
+					Assignees: []string{"bob", "carol"}, Milestone: milestone("Q1 launch", time.February, 7),
+					PullRequests: []entity.PullRequest{
+						pullRequest("acme/api", 38, entity.PullRequestMerged, 8),
+						pullRequest("acme/api", 41, entity.PullRequestMerged, 14),
+						pullRequest("acme/api", 44, entity.PullRequestOpen, 0),
+					}},
+				LatestUpdate: &atRisk[0],
+				AllUpdates:   atRisk,
+				ChildIssues: []entity.IssueWithUpdates{
+					{Issue: entity.Issue{Number: 12, Title: "Retry queue", URL: "https://github.com/acme/api/issues/12",
+						Type: entity.IssueTypeInitiative, State: "open", Depth: 2, Level: "Initiative",
+						Milestone: milestone("January", time.January, 24)}},
+				},
+			},
+		},
+	}
+
+	// Every issue was opened by alice on January 1st and last updated on the 20th
+	var stamp func(node *entity.IssueWithUpdates)
+	stamp = func(node *entity.IssueWithUpdates) {
+		created, updated := day(1), day(20)
+		node.Issue.Author, node.Issue.CreatedAt, node.Issue.UpdatedAt = "alice", &created, &updated
+		for i := range node.ChildIssues {
+			stamp(&node.ChildIssues[i])
+		}
+	}
+	stamp(objective)
+
+	// Summarize the pull requests the way the OKR service does, as of the golden report date
+	asOf := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+	for i := range objective.ChildIssues {
+		kr := &objective.ChildIssues[i]
+		kr.PullRequestEvidence = kr.SummarizePullRequests(asOf)
+	}
+
+	projectInfo := &entity.ProjectInfo{Owner: "acme", ProjectID: 7, Type: entity.ProjectTypeOrganization}
+	return []*entity.IssueWithUpdates{objective}, projectInfo
+}
+
+// updateGolden rewrites the golden files from the current output: go test ./internal/adapters/output -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// assertGolden compares a rendered report with testdata/name byte for byte
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("writing golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s differs from the golden file; run go test ./internal/adapters/output -update and review the diff\ngot:\n%s", name, got)
+	}
+}
+
+// goldenConfig renders reports as of a fixed moment
+func goldenConfig() *entity.Config {
+	config := &entity.Config{Clock: entity.FixedClock(time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC))}
+	config.Output.Title = "Q1 OKRs"
+	config.Output.ProjectName = "Checkout"
+	config.Output.GroupBy = entity.GroupByOwner
+	return config
+}
+
+func TestGoldenReports(t *testing.T) {
+	for _, tt := range []struct {
+		golden string
+		format ports.OutputFormat
+	}{
+		{"report.md", ports.OutputFormatMarkdown},
+		{"report.json", ports.OutputFormatJSON},
+		{"report.txt", ports.OutputFormatGoogleDocs},
+	} {
+		t.Run(tt.golden, func(t *testing.T) {
+			assertGolden(t, tt.golden, generate(t, goldenConfig(), tt.format))
+		})
+	}
+
+	objectives, projectInfo := sampleReport()
+	writer := NewWriterWithConfig(goldenConfig())
+	t.Run("report-analysis.md", func(t *testing.T) {
+		assertGolden(t, "report-analysis.md", writer.formatAsMarkdownWithAnalysis(objectives, projectInfo, "Checkout is slipping."))
+	})
+	t.Run("google-docs-api.txt", func(t *testing.T) {
+		client := &googleDocsClient{ctx: context.Background(), writer: writer}
+		assertGolden(t, "google-docs-api.txt", client.buildPlainTextContent(objectives, projectInfo, "Checkout is slipping."))
+	})
+}
+
+func TestReportsAreReproducible(t *testing.T) {
+	for _, format := range []ports.OutputFormat{ports.OutputFormatMarkdown, ports.OutputFormatGoogleDocs} {
+		first, second := generate(t, goldenConfig(), format), generate(t, goldenConfig(), format)
+		if first != second {
+			t.Errorf("two %s renders of the same data differ", format)
+		}
+		assertContains(t, first, "2025-01-31 09:00:00")
+	}
+}
+
+// generate renders the sample report in a format and returns the written file
+func generate(t *testing.T, config *entity.Config, format ports.OutputFormat) string {
+	t.Helper()
+	objectives, projectInfo := sampleReport()
+	filename := filepath.Join(t.TempDir(), "report")
+
+	if err := NewReportGeneratorWithConfig(config).GenerateReport(objectives, projectInfo, format, filename); err != nil {
+		t.Fatalf("GenerateReport(%s): %v", format, err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("reading report: %v", err)
+	}
+	return string(data)
+}
+
+// assertContains fails for every expected snippet missing from the report
+func assertContains(t *testing.T, report string, expected ...string) {
+	t.Helper()
+	for _, snippet := range expected {
+		if !strings.Contains(report, snippet) {
+			t.Errorf("report is missing %q:\n%s", snippet, report)
+		}
+	}
+}
+
+func TestGenerateMarkdownReport(t *testing.T) {
+	config := &entity.Config{}
+	config.Output.Title = "Q1 OKRs"
+	config.Output.ProjectName = "Checkout"
+
+	report := generate(t, config, ports.OutputFormatMarkdown)
+	assertContains(t, report,
+		"# Q1 OKRs\n",
+		"📊 **Project**: [Checkout](https://github.com/orgs/acme/projects/7)",
+		"- **Objectives**: 1\n- **Key Results**: 2\n- ✅ **Completed**: 1\n",
+		"- ⚠️ **At Risk**: 1\n",
+		"**Overall Progress**: 50.0% (1/2 completed)",
+		"**Average KR Cycle Time**: 18.0 days across 1 completed key result\n",
+		"   - **Completed on**: 2025-01-20 (open 18.0 days)\n   - **Reopened**: 1 time\n",
+		"**Merged PRs Since Last Update**: 2 across 2 key results\n",
+		"   - **Pull Requests**: 1 merged since the last update, 3 linked (1 open)\n",
+		"### 1. ⚠️ Faster checkout\n",
+		"[acme/okrs#1](https://github.com/acme/okrs/issues/1)",
+		"[acme/api#5](https://github.com/acme/api/issues/5)",
+		"**🗒 Notes:**\n       - Vendor contract is late\n",
+		"**✅ Completed:**\n       - Load tests written\n",
+		"1.2.1. ❓ **[Retry queue](https://github.com/acme/api/issues/12)**",
+		"**Level**: Initiative",
+	)
+	if strings.Contains(report, "AI Analysis") {
+		t.Error("report without analysis has an AI Analysis section")
+	}
+}
+
+func TestMarkdownReportWithAnalysis(t *testing.T) {
+	objectives, projectInfo := sampleReport()
+	report := NewWriter().formatAsMarkdownWithAnalysis(objectives, projectInfo, "Checkout is slipping.")
+
+	assertContains(t, report,
+		"# OKR Report\n",
+		"## 🤖 AI Analysis\n\nCheckout is slipping.\n\n---\n\n",
+		"- AI analysis is provided by LiteLLM",
+	)
+}
+
+func TestMarkdownReportWithoutObjectives(t *testing.T) {
+	_, projectInfo := sampleReport()
+	projectInfo.Incomplete = true
+	projectInfo.Warnings = []string{"search results were truncated"}
+	report := NewWriter().formatAsMarkdown(nil, projectInfo)
+
+	assertContains(t, report,
+		"# OKR Report (incomplete)\n",
+		"> ⚠️ **Warning**: search results were truncated",
+		"## ⚠️ No OKR Data Found",
+	)
+}
+
+func TestGenerateJSONReport(t *testing.T) {
+	report := generate(t, nil, ports.OutputFormatJSON)
+
+	var decoded []entity.IssueWithUpdates
+	if err := json.Unmarshal([]byte(report), &decoded); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if len(decoded) != 1 || len(decoded[0].ChildIssues) != 2 {
+		t.Fatalf("decoded %d objectives, want 1 with 2 key results", len(decoded))
+	}
+	kr := decoded[0].ChildIssues[1]
+	if kr.Issue.Ref().String() != "acme/api#5" || kr.LatestUpdate.Status != entity.StatusAtRisk || kr.ChildIssues[0].Issue.Level != "Initiative" {
+		t.Errorf("key result round-tripped as %+v", kr)
+	}
+	if kr.Issue.OwnerLogin() != "bob" || kr.Issue.Milestone == nil || kr.Issue.Milestone.DueOn.Day() != 7 || kr.Issue.CreatedAt == nil || kr.Issue.CreatedAt.Day() != 1 {
+		t.Errorf("key result round-tripped with owner %q, milestone %+v, created %s", kr.Issue.OwnerLogin(), kr.Issue.Milestone, kr.Issue.CreatedAt)
+	}
+	if completed, ok := decoded[0].ChildIssues[0].Issue.CompletedAt(); !ok || completed.Day() != 20 {
+		t.Errorf("completed key result round-tripped with completion date %v, %v", completed, ok)
+	}
+}
+
+func TestJSONSchemaWithProjectFlagsIncompleteRuns(t *testing.T) {
+	objectives, projectInfo := sampleReport()
+	projectInfo.MarkIncomplete("the run was interrupted")
+	projectInfo.AddWarning("search results were truncated")
+
+	config := &entity.Config{}
+	config.Output.JSONSchema = entity.JSONSchemaWithProject
+	report, err := NewReportGeneratorWithConfig(config).FormatAsJSON(objectives[:0], projectInfo)
+	if err != nil {
+		t.Fatalf("FormatAsJSON: %v", err)
+	}
+	assertContains(t, report, `"incomplete": true`, `"objectives": []`)
+
+	var document struct {
+		Project    entity.ProjectInfo        `json:"project"`
+		Objectives []entity.IssueWithUpdates `json:"objectives"`
+	}
+	if err := json.Unmarshal([]byte(report), &document); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if document.Project.Owner != "acme" || !document.Project.Incomplete || len(document.Project.Warnings) != 2 || document.Project.Warnings[1] != "search results were truncated" {
+		t.Errorf("project round-tripped as %+v", document.Project)
+	}
+
+	// The default schema keeps the bare array
+	report, err = NewReportGenerator().FormatAsJSON(objectives, projectInfo)
+	if err != nil {
+		t.Fatalf("FormatAsJSON: %v", err)
+	}
+	if !strings.HasPrefix(report, "[") {
+		t.Errorf("default JSON report starts with %.20q, want an array", report)
+	}
+}
+
+func TestChildSectionTitleUsesLevelPlurals(t *testing.T) {
+	config := &entity.Config{}
+	config.Hierarchy.Plurals = map[string]string{"capability": "Capabilities"}
+	children := func(level string) *entity.IssueWithUpdates {
+		return &entity.IssueWithUpdates{ChildIssues: []entity.IssueWithUpdates{{Issue: entity.Issue{Level: level}}}}
+	}
+
+	for _, tt := range []struct {
+		level string
+		want  string
+	}{
+		{"", "Key Results"},
+		{"Key Result", "Key Results"},
+		{"Capability", "Capabilities"},
+		{"Epics", "Epics"},
+		{"Team Goal", "Team Goal"},
+	} {
+		if got := NewWriterWithConfig(config).childSectionTitle(children(tt.level)); got != tt.want {
+			t.Errorf("childSectionTitle(%q) = %q, want %q", tt.level, got, tt.want)
+		}
+	}
+	if got := NewWriter().childSectionTitle(children("Initiative")); got != "Initiatives" {
+		t.Errorf("childSectionTitle without config = %q, want Initiatives", got)
+	}
+}
+
+func TestGenerateGoogleDocsTextReport(t *testing.T) {
+	report := generate(t, nil, ports.OutputFormatGoogleDocs)
+
+	assertContains(t, report,
+		"Faster checkout",
+		"API error budget",
+		"Retry queue",
+		"Vendor contract is late",
+		"Average KR Cycle Time: 18.0 days across 1 completed key result",
+		"   - Completed on: 2025-01-20 (open 18.0 days)\n",
+		"Merged PRs Since Last Update: 2 across 2 key results",
+		"   - Pull Requests: 1 merged, 1 linked (0 open)\n",
+	)
+	if strings.Contains(report, "**") {
+		t.Errorf("plain text report contains markdown emphasis:\n%s", report)
+	}
+}
+
+func TestReportsFlagOnTrackKeyResultsWithoutMerges(t *testing.T) {
+	objectives, projectInfo := sampleReport()
+	objectives[0].ChildIssues[1].PullRequestEvidence = &entity.PullRequestEvidence{NoRecentMerges: true}
+	writer := NewWriterWithConfig(goldenConfig())
+
+	assertContains(t, writer.formatAsMarkdown(objectives, projectInfo),
+		"🚩 **On Track Without Merged PRs in 4 weeks**: acme/api#5\n",
+		"   - 🚩 **No merged PRs in 4 weeks** while reported on track\n",
+	)
+	assertContains(t, writer.formatAsGoogleDocs(objectives, projectInfo),
+		"🚩 On Track Without Merged PRs in 4 weeks: acme/api#5\n",
+		"   - 🚩 No merged PRs in 4 weeks while reported on track\n",
+	)
+}
+
+func TestReportsShowOwnersAndMilestones(t *testing.T) {
+	objectives, projectInfo := sampleReport()
+	writer := NewWriterWithConfig(goldenConfig())
+
+	assertContains(t, writer.formatAsMarkdown(objectives, projectInfo),
+		"**Milestones**: 🚨 0 overdue, ⏰ 1 due soon\n",
+		"## 👤 Key Results by Owner\n\n### @alice (1)\n\n- ✅ [p95 latency below 300ms](https://github.com/acme/okrs/issues/2) | **Status**: completed\n",
+		"### @bob (1)\n\n- ⚠️ [API error budget](https://github.com/acme/api/issues/5) | **Status**: at-risk | ⏰ due soon\n",
+		"   - **Owner**: @alice\n   - **Milestone**: Q1 (due 2025-01-15)\n",
+		"   - **Owner**: @bob\n   - **Assignees**: @carol\n   - **Milestone**: Q1 launch (due 2025-02-07) ⏰ due soon\n",
+		"   - **Milestone**: January (due 2025-01-24) 🚨 overdue\n",
+	)
+	assertContains(t, writer.formatAsGoogleDocs(objectives, projectInfo),
+		"- ⚠️ API error budget (https://github.com/acme/api/issues/5) | Status: at-risk | ⏰ due soon\n",
+		"   - Owner: @bob\n   - Assignees: @carol\n",
+	)
+
+	// Without output.group_by the report keeps only the objective tree
+	if report := NewWriter().formatAsMarkdown(objectives, projectInfo); strings.Contains(report, "Key Results by Owner") {
+		t.Error("report without group_by has a Key Results by Owner section")
+	}
+}
+
+func TestGenerateReportRejectsUnknownFormat(t *testing.T) {
+	objectives, projectInfo := sampleReport()
+	err := NewReportGenerator().GenerateReport(objectives, projectInfo, "pdf", filepath.Join(t.TempDir(), "report"))
+	if err == nil || !strings.Contains(err.Error(), "unsupported output format") {
+		t.Errorf("GenerateReport(pdf) error = %v", err)
+	}
+}
+
+// fakeDocs serves the two Google Docs API endpoints the writer uses for one document
+type fakeDocs struct {
+	endIndex int
+
+	mu       sync.Mutex
+	requests [][]map[string]interface{}
+}
+
+func (f *fakeDocs) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/documents/doc-123":
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"body": map[string]interface{}{"content": []interface{}{map[string]interface{}{"endIndex": f.endIndex}}},
+		})
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/documents/doc-123:batchUpdate":
+		var payload struct {
+			Requests []map[string]interface{} `json:"requests"`
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &payload); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		f.mu.Lock()
+		f.requests = append(f.requests, payload.Requests)
+		f.mu.Unlock()
+		w.Write([]byte(`{}`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// redirectTransport sends every request to the test server instead of its original host
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newFakeDocsClient returns a Google Docs client talking to a fake document
+func newFakeDocsClient(t *testing.T, docs *fakeDocs) *googleDocsClient {
+	t.Helper()
+	server := httptest.NewServer(docs)
+	t.Cleanup(server.Close)
+	target, _ := url.Parse(server.URL)
+
+	return &googleDocsClient{
+		httpClient: &http.Client{Transport: redirectTransport{target: target}},
+		ctx:        context.Background(),
+		writer:     NewWriter(),
+	}
+}
+
+const testDocumentURL = "https://docs.google.com/document/d/doc-123/edit"
+
+func TestConvertMarkdownToGoogleDocsAppends(t *testing.T) {
+	docs := &fakeDocs{endIndex: 40}
+	client := newFakeDocsClient(t, docs)
+	objectives, projectInfo := sampleReport()
+
+	if err := client.convertMarkdownToGoogleDocs(testDocumentURL, NewWriter().formatAsMarkdown(objectives, projectInfo)); err != nil {
+		t.Fatalf("convertMarkdownToGoogleDocs: %v", err)
+	}
+
+	if len(docs.requests) != 1 || len(docs.requests[0]) != 2 {
+		t.Fatalf("batch updates = %v, want one page break and one insert", docs.requests)
+	}
+	if _, ok := docs.requests[0][0]["insertPageBreak"]; !ok {
+		t.Errorf("first request = %v, want a page break", docs.requests[0][0])
+	}
+	insert := docs.requests[0][1]["insertText"].(map[string]interface{})
+	if index := insert["location"].(map[string]interface{})["index"]; index != float64(40) {
+		t.Errorf("insert index = %v, want 40", index)
+	}
+	text := insert["text"].(string)
+	assertContains(t, text, "=== OKR Report - ", "Faster checkout", "API error budget")
+	if strings.Contains(text, "**") || strings.Contains(text, "](") {
+		t.Errorf("inserted text still contains markdown:\n%s", text)
+	}
+}
+
+func TestWriteToGoogleDocsReplacesContent(t *testing.T) {
+	docs := &fakeDocs{endIndex: 40}
+	client := newFakeDocsClient(t, docs)
+	objectives, projectInfo := sampleReport()
+
+	if err := client.writeToGoogleDocs(testDocumentURL, objectives, projectInfo, "Checkout is slipping."); err != nil {
+		t.Fatalf("writeToGoogleDocs: %v", err)
+	}
+
+	if len(docs.requests) < 2 {
+		t.Fatalf("batch updates = %d, want a delete followed by an insert", len(docs.requests))
+	}
+	deleteRange := docs.requests[0][0]["deleteContentRange"].(map[string]interface{})["range"].(map[string]interface{})
+	if deleteRange["startIndex"] != float64(1) || deleteRange["endIndex"] != float64(39) {
+		t.Errorf("deleted range = %v, want 1-39", deleteRange)
+	}
+	insert := docs.requests[1][0]["insertText"].(map[string]interface{})
+	assertContains(t, insert["text"].(string), "Faster checkout", "Checkout is slipping.", "Retry queue")
+}
+
+func TestWriteToGoogleDocsRejectsInvalidURL(t *testing.T) {
+	client := newFakeDocsClient(t, &fakeDocs{})
+	objectives, projectInfo := sampleReport()
+
+	err := client.writeToGoogleDocs("https://example.com/not-a-doc", objectives, projectInfo, "")
+	if err == nil || !strings.Contains(err.Error(), "could not extract document ID") {
+		t.Errorf("writeToGoogleDocs error = %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package recorder_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github-okr-fetcher/internal/adapters/github"
+	"github-okr-fetcher/internal/adapters/output"
+	"github-okr-fetcher/internal/domain/entity"
+	"github-okr-fetcher/internal/domain/service"
+	"github-okr-fetcher/internal/ports"
+)
+
+// TestReplayedRunRendersReport runs the whole pipeline, from fetching the board to the written
+// report, against a recording of a two-objective board made with --record
+func TestReplayedRunRendersReport(t *testing.T) {
+	config := &entity.Config{}
+	config.GitHub.ProjectURL = "https://github.com/orgs/acme/projects/1"
+	config.Labels.Required = []string{"okr"}
+	config.Recording = entity.RecordingConfig{Mode: entity.RecordingReplay, Dir: filepath.Join("testdata", "okr-board")}
+	config.Clock = entity.FixedClock(time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC))
+	// Fixtures answer at once, so there is no quota to pace
+	config.GitHub.RateLimit = 3600000
+
+	// Replays need no token
+	repo, err := github.NewRepository("", config)
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+	objectives, projectInfo, err := service.NewOKRServiceWithConfig(repo, config).FetchOKRData(context.Background(), config)
+	if err != nil {
+		t.Fatalf("FetchOKRData: %v", err)
+	}
+	if len(objectives) != 2 || projectInfo.Incomplete {
+		t.Fatalf("got %d objectives (incomplete: %v), want 2 complete ones", len(objectives), projectInfo.Incomplete)
+	}
+
+	filename := filepath.Join(t.TempDir(), "report.md")
+	if err := output.NewReportGeneratorWithConfig(config).GenerateReport(objectives, projectInfo, ports.OutputFormatMarkdown, filename); err != nil {
+		t.Fatalf("GenerateReport: %v", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("reading report: %v", err)
+	}
+
+	report := string(data)
+	for _, snippet := range []string{
+		"2025-01-31 09:00:00",
+		"Faster checkout",
+		"Zero downtime deploys",
+		"**Latest** (2025-01-13 by @alice)",
+		"Reliable payments",
+		"**Latest** (2025-01-13 by @carol)",
+		"**Completed on**: 2025-01-20 (open 18.0 days)",
+	} {
+		if !strings.Contains(report, snippet) {
+			t.Errorf("report is missing %q:\n%s", snippet, report)
+		}
+	}
+	if strings.Contains(report, "Unrelated bug") {
+		t.Errorf("report lists the issue without the OKR label:\n%s", report)
+	}
+}
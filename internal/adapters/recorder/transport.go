@@ -0,0 +1,413 @@
+// Package recorder records HTTP exchanges to fixture files and replays them later, so a run
+// can be reproduced offline, without tokens, and tests can exercise the whole pipeline.
+package recorder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github-okr-fetcher/internal/domain/entity"
+)
+
+// Exchange is one recorded request and its response, stored as a fixture file
+type Exchange struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies a request. Headers are not kept so credentials never reach a fixture.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	// BodyHash tells apart requests to the same URL, such as GraphQL queries
+	BodyHash string `json:"body_hash,omitempty"`
+	Body     Body   `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response replayed for a request
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body is stored as JSON when it is JSON, which keeps fixtures readable and editable,
+// and as a string otherwise
+type Body []byte
+
+// MarshalJSON implements json.Marshaler
+func (b Body) MarshalJSON() ([]byte, error) {
+	if len(b) == 0 {
+		return []byte(`""`), nil
+	}
+	if json.Valid(b) {
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, b); err == nil {
+			return compact.Bytes(), nil
+		}
+	}
+	return json.Marshal(string(b))
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+	// Fixtures are indented; replay the compact form GitHub sends
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return err
+	}
+	*b = compact.Bytes()
+	return nil
+}
+
+// skippedHeaders are response headers left out of fixtures
+var skippedHeaders = []string{"Set-Cookie", "Date", "Content-Length"}
+
+// NewTransport wraps base so exchanges are recorded to or replayed from the fixture directory
+// named in config. Without recording it returns base unchanged; a nil base means the default transport.
+func NewTransport(config entity.RecordingConfig, base http.RoundTripper) (http.RoundTripper, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	switch config.Mode {
+	case entity.RecordingOff:
+		return base, nil
+	case entity.RecordingRecord:
+		seq, err := recordingSequence(config.Dir)
+		if err != nil {
+			return nil, err
+		}
+		return &recordingTransport{dir: config.Dir, base: base, seq: seq}, nil
+	case entity.RecordingReplay:
+		exchanges, err := LoadExchanges(config.Dir)
+		if err != nil {
+			return nil, err
+		}
+		return newReplayTransport(exchanges), nil
+	}
+	return nil, fmt.Errorf("unknown recording mode %q", config.Mode)
+}
+
+// LoadExchanges reads every fixture in dir, in file name order
+func LoadExchanges(dir string) ([]Exchange, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing fixtures: %v", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no fixtures found in %s", dir)
+	}
+	sort.Strings(paths)
+
+	exchanges := make([]Exchange, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading fixture: %v", err)
+		}
+		var exchange Exchange
+		if err := json.Unmarshal(data, &exchange); err != nil {
+			return nil, fmt.Errorf("error parsing fixture %s: %v", path, err)
+		}
+		exchanges = append(exchanges, exchange)
+	}
+	return exchanges, nil
+}
+
+// fixtureSequence numbers the fixtures of one directory across all transports recording into it
+type fixtureSequence struct {
+	mu   sync.Mutex
+	next int
+}
+
+var (
+	sequencesMu sync.Mutex
+	sequences   = make(map[string]*fixtureSequence)
+)
+
+// recordingSequence returns the shared sequence for a fixture directory. The first transport
+// creates the directory, which must not hold fixtures already: replaying a mix of two
+// recordings would answer requests with stale responses.
+func recordingSequence(dir string) (*fixtureSequence, error) {
+	sequencesMu.Lock()
+	defer sequencesMu.Unlock()
+
+	key := filepath.Clean(dir)
+	if seq, ok := sequences[key]; ok {
+		return seq, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating fixture directory: %v", err)
+	}
+	existing, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error listing fixtures: %v", err)
+	}
+	if len(existing) > 0 {
+		return nil, fmt.Errorf("fixture directory %s already holds a recording; record into an empty directory", dir)
+	}
+
+	seq := &fixtureSequence{}
+	sequences[key] = seq
+	return seq, nil
+}
+
+// recordingTransport forwards requests and saves each exchange as a numbered fixture file
+type recordingTransport struct {
+	dir  string
+	base http.RoundTripper
+	seq  *fixtureSequence
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response to record: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := resp.Header.Clone()
+	for _, name := range skippedHeaders {
+		header.Del(name)
+	}
+	exchange := Exchange{
+		Request:  recorded,
+		Response: RecordedResponse{StatusCode: resp.StatusCode, Header: header, Body: body},
+	}
+
+	if err := t.save(exchange); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// save writes an exchange to the next numbered fixture file
+func (t *recordingTransport) save(exchange Exchange) error {
+	data, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding fixture: %v", err)
+	}
+
+	t.seq.mu.Lock()
+	t.seq.next++
+	name := fmt.Sprintf("%04d-%s-%s.json", t.seq.next, strings.ToLower(exchange.Request.Method), fixtureSlug(exchange.Request.URL))
+	t.seq.mu.Unlock()
+
+	if err := os.WriteFile(filepath.Join(t.dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("error writing fixture: %v", err)
+	}
+	return nil
+}
+
+// replayTransport answers requests from recorded exchanges. A request is matched to an unused
+// exchange with the same method, URL and body, then to one with the same method and URL (bodies
+// may carry timestamps), and finally repeats the last exchange that matched exactly. GraphQL
+// requests all share one URL, so they only ever match an exchange with the same query and variables.
+type replayTransport struct {
+	exchanges []Exchange
+	// keys holds the body key of each exchange, see bodyKey
+	keys []string
+	used []bool
+	mu   sync.Mutex
+}
+
+func newReplayTransport(exchanges []Exchange) *replayTransport {
+	keys := make([]string, len(exchanges))
+	for i, exchange := range exchanges {
+		keys[i] = bodyKey(exchange.Request)
+	}
+	return &replayTransport{exchanges: exchanges, keys: keys, used: make([]bool, len(exchanges))}
+}
+
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := recordRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	exchange := t.match(recorded)
+	if exchange == nil {
+		return nil, fmt.Errorf("no recorded response for %s", describeRequest(recorded))
+	}
+
+	header := exchange.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.Response.StatusCode, http.StatusText(exchange.Response.StatusCode)),
+		StatusCode:    exchange.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(exchange.Response.Body)),
+		ContentLength: int64(len(exchange.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+// match finds the exchange to replay for a request
+func (t *replayTransport) match(request RecordedRequest) *Exchange {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	sameRequest := func(i int) bool {
+		candidate := t.exchanges[i].Request
+		return candidate.Method == request.Method && candidate.URL == request.URL
+	}
+
+	// A GraphQL response to another query would be decoded as if it answered this one
+	passes := []bool{true, false}
+	if isGraphQL(request) {
+		passes = passes[:1]
+	}
+
+	key := bodyKey(request)
+	lastExact := -1
+	for _, exact := range passes {
+		for i := range t.exchanges {
+			if !sameRequest(i) || (exact && t.keys[i] != key) {
+				continue
+			}
+			if t.used[i] {
+				if exact {
+					lastExact = i
+				}
+				continue
+			}
+			t.used[i] = true
+			return &t.exchanges[i]
+		}
+	}
+
+	if lastExact >= 0 {
+		return &t.exchanges[lastExact]
+	}
+	return nil
+}
+
+// graphQLBody is the part of a GraphQL request body that decides its response
+type graphQLBody struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// isGraphQL reports whether a request goes to a GraphQL endpoint
+func isGraphQL(request RecordedRequest) bool {
+	if request.Method != http.MethodPost {
+		return false
+	}
+	path := request.URL
+	if idx := strings.Index(path, "?"); idx >= 0 {
+		path = path[:idx]
+	}
+	return strings.HasSuffix(path, "/graphql")
+}
+
+// parseGraphQL decodes a GraphQL request body, collapsing the whitespace of its query
+func parseGraphQL(body []byte) (graphQLBody, bool) {
+	var parsed graphQLBody
+	if err := json.Unmarshal(body, &parsed); err != nil || parsed.Query == "" {
+		return parsed, false
+	}
+	parsed.Query = strings.Join(strings.Fields(parsed.Query), " ")
+	return parsed, true
+}
+
+// bodyKey identifies the body of a request. GraphQL bodies are keyed by their normalized query
+// and variables, so formatting and key order do not matter; other bodies by their hash.
+func bodyKey(request RecordedRequest) string {
+	if !isGraphQL(request) {
+		return request.BodyHash
+	}
+	parsed, ok := parseGraphQL(request.Body)
+	if !ok {
+		return request.BodyHash
+	}
+	// encoding/json writes map keys sorted, which makes the variables canonical
+	variables, err := json.Marshal(parsed.Variables)
+	if err != nil {
+		return request.BodyHash
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(parsed.Query+"\n"+string(variables))))[:16]
+}
+
+// describeRequest names a request in errors; GraphQL requests by their query and variables
+func describeRequest(request RecordedRequest) string {
+	parsed, ok := parseGraphQL(request.Body)
+	if !isGraphQL(request) || !ok {
+		return request.Method + " " + request.URL
+	}
+	query := parsed.Query
+	if len(query) > 120 {
+		query = query[:120] + "..."
+	}
+	variables, _ := json.Marshal(parsed.Variables)
+	return fmt.Sprintf("GraphQL query %q with variables %s", query, variables)
+}
+
+// recordRequest captures the identifying parts of a request, restoring its body for sending
+func recordRequest(req *http.Request) (RecordedRequest, error) {
+	recorded := RecordedRequest{Method: req.Method, URL: req.URL.String()}
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return recorded, fmt.Errorf("error reading request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(body) > 0 {
+		recorded.Body = body
+		recorded.BodyHash = fmt.Sprintf("%x", sha256.Sum256(body))[:16]
+	}
+	return recorded, nil
+}
+
+// slugPattern matches runs of characters that do not belong in a file name
+var slugPattern = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// fixtureSlug turns a request URL into a short readable file name part
+func fixtureSlug(rawURL string) string {
+	rawURL = strings.TrimPrefix(strings.TrimPrefix(rawURL, "https://"), "http://")
+	if idx := strings.Index(rawURL, "?"); idx >= 0 {
+		rawURL = rawURL[:idx]
+	}
+	slug := strings.Trim(slugPattern.ReplaceAllString(rawURL, "-"), "-")
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+	return strings.ToLower(slug)
+}
@@ -0,0 +1,234 @@
+package recorder
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github-okr-fetcher/internal/domain/entity"
+)
+
+// echoServer answers every request with its method, path and body
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Echo", "yes")
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, r.Method+" "+r.URL.Path+" "+string(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// send makes a request through transport and returns the response body
+func send(t *testing.T, transport http.RoundTripper, method, url, body string) (*http.Response, string) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading response: %v", err)
+	}
+	return resp, string(data)
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	srv := echoServer(t)
+	dir := filepath.Join(t.TempDir(), "fixtures")
+
+	recording, err := NewTransport(entity.RecordingConfig{Mode: entity.RecordingRecord, Dir: dir}, nil)
+	if err != nil {
+		t.Fatalf("NewTransport(record): %v", err)
+	}
+	requests := []struct{ method, path, body string }{
+		{http.MethodGet, "/repos/acme/okrs/issues/1/comments?per_page=100", ""},
+		{http.MethodPost, "/graphql", `{"query": "{ viewer { login } }"}`},
+		{http.MethodPost, "/graphql", `{"query": "{ rateLimit { remaining } }"}`},
+	}
+	var recorded []string
+	for _, r := range requests {
+		_, body := send(t, recording, r.method, srv.URL+r.path, r.body)
+		recorded = append(recorded, body)
+	}
+
+	exchanges, err := LoadExchanges(dir)
+	if err != nil {
+		t.Fatalf("LoadExchanges: %v", err)
+	}
+	if len(exchanges) != len(requests) {
+		t.Fatalf("recorded %d exchanges, want %d", len(exchanges), len(requests))
+	}
+	if header := exchanges[0].Response.Header; header.Get("X-Echo") != "yes" || header.Get("Date") != "" {
+		t.Errorf("recorded headers = %v, want X-Echo without Date", header)
+	}
+
+	// Nothing is left to answer but the fixtures
+	srv.Close()
+	replay, err := NewTransport(entity.RecordingConfig{Mode: entity.RecordingReplay, Dir: dir}, nil)
+	if err != nil {
+		t.Fatalf("NewTransport(replay): %v", err)
+	}
+	// Replayed out of order: POSTs are told apart by their bodies
+	for _, i := range []int{2, 0, 1} {
+		r := requests[i]
+		resp, body := send(t, replay, r.method, srv.URL+r.path, r.body)
+		if resp.StatusCode != http.StatusCreated || body != recorded[i] {
+			t.Errorf("replayed %s %s = %d %q, want 201 %q", r.method, r.path, resp.StatusCode, body, recorded[i])
+		}
+	}
+}
+
+func TestReplayFallsBackToMethodAndURL(t *testing.T) {
+	replay := newReplayTransport([]Exchange{
+		{Request: RecordedRequest{Method: "POST", URL: "https://api.github.com/markdown", BodyHash: "aaa"}, Response: RecordedResponse{StatusCode: 200, Body: Body(`"first"`)}},
+		{Request: RecordedRequest{Method: "POST", URL: "https://api.github.com/markdown", BodyHash: "bbb"}, Response: RecordedResponse{StatusCode: 200, Body: Body(`"second"`)}},
+		{Request: RecordedRequest{Method: "GET", URL: "https://api.github.com/rate_limit"}, Response: RecordedResponse{StatusCode: 200, Body: Body(`"limits"`)}},
+	})
+
+	tests := []struct {
+		name    string
+		request RecordedRequest
+		want    string
+	}{
+		{"exact body", RecordedRequest{Method: "POST", URL: "https://api.github.com/markdown", BodyHash: "bbb"}, `"second"`},
+		{"unused exchange with another body", RecordedRequest{Method: "POST", URL: "https://api.github.com/markdown", BodyHash: "ccc"}, `"first"`},
+		{"repeat of the last exact match", RecordedRequest{Method: "POST", URL: "https://api.github.com/markdown", BodyHash: "bbb"}, `"second"`},
+		{"no body", RecordedRequest{Method: "GET", URL: "https://api.github.com/rate_limit"}, `"limits"`},
+		{"repeat without body", RecordedRequest{Method: "GET", URL: "https://api.github.com/rate_limit"}, `"limits"`},
+		{"other method", RecordedRequest{Method: "DELETE", URL: "https://api.github.com/rate_limit"}, ""},
+		{"used up without an exact match", RecordedRequest{Method: "POST", URL: "https://api.github.com/markdown", BodyHash: "ddd"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertMatch(t, replay.match(tt.request), tt.want)
+		})
+	}
+}
+
+func TestReplayMatchesGraphQLByQueryAndVariables(t *testing.T) {
+	graphQL := func(body string) RecordedRequest {
+		return RecordedRequest{Method: "POST", URL: "https://api.github.com/graphql", BodyHash: "ignored", Body: Body(body)}
+	}
+	replay := newReplayTransport([]Exchange{
+		{Request: graphQL(`{"query": "query($n: Int!) {\n  issue(number: $n) { title }\n}", "variables": {"n": 1, "owner": "acme"}}`), Response: RecordedResponse{StatusCode: 200, Body: Body(`"issue 1"`)}},
+		{Request: graphQL(`{"query": "query($n: Int!) { issue(number: $n) { title } }", "variables": {"n": 2, "owner": "acme"}}`), Response: RecordedResponse{StatusCode: 200, Body: Body(`"issue 2"`)}},
+	})
+
+	tests := []struct {
+		name    string
+		request RecordedRequest
+		want    string
+	}{
+		{"other whitespace and key order", graphQL(`{"variables": {"owner": "acme", "n": 2}, "query": "query($n: Int!) { issue(number: $n) { title } }"}`), `"issue 2"`},
+		{"repeat of the last exact match", graphQL(`{"query": "query($n: Int!) { issue(number: $n) { title } }", "variables": {"n": 2, "owner": "acme"}}`), `"issue 2"`},
+		{"other variables", graphQL(`{"query": "query($n: Int!) { issue(number: $n) { title } }", "variables": {"n": 3, "owner": "acme"}}`), ""},
+		{"other query", graphQL(`{"query": "query($n: Int!) { issue(number: $n) { body } }", "variables": {"n": 1, "owner": "acme"}}`), ""},
+		{"normalized query", graphQL(`{"query": "query($n: Int!) { issue(number: $n) { title } }", "variables": {"n": 1, "owner": "acme"}}`), `"issue 1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertMatch(t, replay.match(tt.request), tt.want)
+		})
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "https://api.github.com/graphql", strings.NewReader(`{"query": "{ viewer { login } }", "variables": {}}`))
+	if _, err := replay.RoundTrip(req); err == nil || !strings.Contains(err.Error(), `no recorded response for GraphQL query "{ viewer { login } }"`) {
+		t.Errorf("error = %v, want no recorded response for the query", err)
+	}
+}
+
+// assertMatch checks the response body of a matched exchange; want is empty when nothing should match
+func assertMatch(t *testing.T, exchange *Exchange, want string) {
+	t.Helper()
+	switch {
+	case want == "" && exchange != nil:
+		t.Errorf("matched %+v, want no match", exchange.Request)
+	case want != "" && exchange == nil:
+		t.Errorf("no match, want %s", want)
+	case want != "" && string(exchange.Response.Body) != want:
+		t.Errorf("matched %s, want %s", exchange.Response.Body, want)
+	}
+}
+
+func TestReplayRejectsUnknownRequest(t *testing.T) {
+	dir := t.TempDir()
+	fixture := `{"request": {"method": "GET", "url": "https://api.github.com/rate_limit"}, "response": {"status_code": 200, "body": {}}}`
+	if err := os.WriteFile(filepath.Join(dir, "0001-get.json"), []byte(fixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	replay, err := NewTransport(entity.RecordingConfig{Mode: entity.RecordingReplay, Dir: dir}, nil)
+	if err != nil {
+		t.Fatalf("NewTransport(replay): %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, "https://api.github.com/orgs/acme", nil)
+	if _, err := replay.RoundTrip(req); err == nil || !strings.Contains(err.Error(), "no recorded response for GET https://api.github.com/orgs/acme") {
+		t.Errorf("error = %v, want no recorded response", err)
+	}
+}
+
+func TestReplayNeedsFixtures(t *testing.T) {
+	_, err := NewTransport(entity.RecordingConfig{Mode: entity.RecordingReplay, Dir: t.TempDir()}, nil)
+	if err == nil || !strings.Contains(err.Error(), "no fixtures found") {
+		t.Errorf("error = %v, want no fixtures found", err)
+	}
+}
+
+func TestRecordRefusesNonEmptyDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "0001-get-old.json"), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewTransport(entity.RecordingConfig{Mode: entity.RecordingRecord, Dir: dir}, nil)
+	if err == nil || !strings.Contains(err.Error(), "already holds a recording") {
+		t.Errorf("error = %v, want a refusal to record into %s", err, dir)
+	}
+}
+
+func TestRecordingNeverStoresCredentials(t *testing.T) {
+	srv := echoServer(t)
+	dir := filepath.Join(t.TempDir(), "fixtures")
+	recording, err := NewTransport(entity.RecordingConfig{Mode: entity.RecordingRecord, Dir: dir}, nil)
+	if err != nil {
+		t.Fatalf("NewTransport(record): %v", err)
+	}
+
+	send(t, recording, http.MethodGet, srv.URL+"/user", "")
+	send(t, recording, http.MethodPost, srv.URL+"/graphql", `{"query": "{ viewer { login } }"}`)
+
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(paths) != 2 {
+		t.Fatalf("recorded %d fixtures, want 2", len(paths))
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(data), "secret-token") || strings.Contains(string(data), "Authorization") {
+			t.Errorf("%s contains the credentials:\n%s", filepath.Base(path), data)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock tells the current time. Reports read it instead of time.Now so a run can be
+// rendered as of a fixed moment and reproduced byte for byte.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the wall clock
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// FixedClock always returns the same moment, as set with --as-of
+type FixedClock time.Time
+
+// Now returns the fixed moment
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// asOfLayouts are the formats accepted by ParseAsOf, most specific first
+var asOfLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// ParseAsOf parses an --as-of value: an RFC 3339 timestamp, a local date and time, or a date.
+// Values without a zone are taken as UTC so they render the same on every machine.
+func ParseAsOf(value string) (time.Time, error) {
+	for _, layout := range asOfLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use YYYY-MM-DD, YYYY-MM-DD HH:MM:SS or RFC 3339", value)
+}
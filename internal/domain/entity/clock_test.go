@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAsOf(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2025-01-31", time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"2025-01-31 09:30:00", time.Date(2025, 1, 31, 9, 30, 0, 0, time.UTC)},
+		{"2025-01-31T09:30:00", time.Date(2025, 1, 31, 9, 30, 0, 0, time.UTC)},
+		{"2025-01-31T09:30:00+02:00", time.Date(2025, 1, 31, 7, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseAsOf(tt.value)
+		if err != nil {
+			t.Errorf("ParseAsOf(%q): %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseAsOf(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+
+	if _, err := ParseAsOf("last friday"); err == nil {
+		t.Error("ParseAsOf accepted an invalid value")
+	}
+}
+
+func TestConfigNowUsesClock(t *testing.T) {
+	asOf := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+	config := &Config{Clock: FixedClock(asOf)}
+	config.Output.Format = "json"
+
+	if got := config.GetOutputFile("acme", 7, 2); got != "okr-report_acme_7_2_20250131_090000.json" {
+		t.Errorf("GetOutputFile() = %s", got)
+	}
+	if got := (*Config)(nil).Now(); got.IsZero() {
+		t.Error("a nil config should fall back to the wall clock")
+	}
+}
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -18,6 +19,49 @@ type Config struct {
 	Cache           CacheConfig            `json:"cache"`
 	Patterns        PatternsConfig         `json:"patterns"`
 	StatusDetection StatusDetectionConfig  `json:"status_detection"`
+	ProjectFields   ProjectFieldsConfig    `json:"project_fields"`
+	Hierarchy       HierarchyConfig        `json:"hierarchy"`
+
+	// Recording is set from --record/--replay and never read from the config file
+	Recording RecordingConfig `json:"-"`
+	// Clock dates reports and output files; --as-of sets a fixed one (default: the wall clock)
+	Clock Clock `json:"-"`
+}
+
+// Now returns the current time according to the configured clock
+func (c *Config) Now() time.Time {
+	if c == nil || c.Clock == nil {
+		return SystemClock.Now()
+	}
+	return c.Clock.Now()
+}
+
+// RecordingMode selects whether HTTP exchanges go to the network, to fixtures or both
+type RecordingMode string
+
+const (
+	// RecordingOff sends requests to the network as usual
+	RecordingOff RecordingMode = ""
+	// RecordingRecord sends requests to the network and saves every exchange as a fixture
+	RecordingRecord RecordingMode = "record"
+	// RecordingReplay answers requests from fixtures without any network access
+	RecordingReplay RecordingMode = "replay"
+)
+
+// RecordingConfig names the fixture directory HTTP exchanges are recorded to or replayed from
+type RecordingConfig struct {
+	Mode RecordingMode
+	Dir  string
+}
+
+// IsActive returns true when exchanges are recorded or replayed
+func (c RecordingConfig) IsActive() bool {
+	return c.Mode != RecordingOff
+}
+
+// IsReplay returns true when requests are answered from fixtures
+func (c RecordingConfig) IsReplay() bool {
+	return c.Mode == RecordingReplay
 }
 
 // GitHubConfig contains GitHub-related configuration
@@ -32,6 +76,85 @@ type GitHubConfig struct {
 	PageSize      int    `json:"page_size,omitempty"`
 	MaxIssuesLimit int   `json:"max_issues_limit,omitempty"`
 	UserAgent     string `json:"user_agent,omitempty"`
+
+	// RetryMaxElapsedSec stops retrying transient failures after this many seconds
+	RetryMaxElapsedSec int `json:"retry_max_elapsed_seconds,omitempty"`
+
+	// SkipTimelines saves the timeline request per issue, leaving out completion dates,
+	// cycle times and pull request evidence
+	SkipTimelines bool `json:"skip_timelines,omitempty"`
+
+	// Host is the GitHub Enterprise Server host, e.g. "github.example.com"
+	// (default: the host of project_url, or github.com)
+	Host string `json:"host,omitempty"`
+	// APIURL and GraphQLURL override the API endpoints derived from the host
+	APIURL     string `json:"api_url,omitempty"`
+	GraphQLURL string `json:"graphql_url,omitempty"`
+	// CABundle is a PEM file of certificate authorities to trust in addition to the system ones
+	CABundle string `json:"ca_bundle,omitempty"`
+	// Proxy is the proxy URL for API requests (default: HTTPS_PROXY/HTTP_PROXY)
+	Proxy string `json:"proxy,omitempty"`
+
+	// TokenFile and TokenCommand supply the token when GITHUB_TOKEN is not set;
+	// the command's output is used, e.g. "gh auth token"
+	TokenFile    string `json:"token_file,omitempty"`
+	TokenCommand string `json:"token_command,omitempty"`
+
+	// App authenticates as a GitHub App installation instead of with GITHUB_TOKEN
+	App GitHubAppConfig `json:"app,omitempty"`
+}
+
+// GitHubAppConfig identifies a GitHub App installation to authenticate as
+// Note: the private key may also be provided via the GITHUB_APP_PRIVATE_KEY environment variable
+type GitHubAppConfig struct {
+	AppID          int64  `json:"app_id,omitempty"`
+	InstallationID int64  `json:"installation_id,omitempty"`
+	PrivateKeyFile string `json:"private_key_file,omitempty"`
+	// PrivateKey holds the PEM-encoded key itself and is never read from the config file
+	PrivateKey string `json:"-"`
+}
+
+// IsConfigured returns true when App authentication was requested
+func (c GitHubAppConfig) IsConfigured() bool {
+	return c.AppID != 0 || c.InstallationID != 0
+}
+
+// GetHost returns the GitHub host: the configured one, the one in the project URL, or github.com
+func (c GitHubConfig) GetHost() string {
+	if c.Host != "" {
+		return strings.ToLower(c.Host)
+	}
+	if parsed, err := url.Parse(c.ProjectURL); err == nil && parsed.Host != "" {
+		return strings.ToLower(parsed.Host)
+	}
+	return DefaultHost
+}
+
+// IsEnterprise returns true when the host is a GitHub Enterprise Server
+func (c GitHubConfig) IsEnterprise() bool {
+	return c.GetHost() != DefaultHost
+}
+
+// GetAPIURL returns the REST API base URL; GitHub Enterprise Server serves it under /api/v3/
+func (c GitHubConfig) GetAPIURL() string {
+	if c.APIURL != "" {
+		return c.APIURL
+	}
+	if c.IsEnterprise() {
+		return fmt.Sprintf("https://%s/api/v3/", c.GetHost())
+	}
+	return "https://api.github.com/"
+}
+
+// GetGraphQLURL returns the GraphQL endpoint; GitHub Enterprise Server serves it at /api/graphql
+func (c GitHubConfig) GetGraphQLURL() string {
+	if c.GraphQLURL != "" {
+		return c.GraphQLURL
+	}
+	if c.IsEnterprise() {
+		return fmt.Sprintf("https://%s/api/graphql", c.GetHost())
+	}
+	return "https://api.github.com/graphql"
 }
 
 // LabelsConfig contains label filtering configuration
@@ -43,6 +166,11 @@ type LabelsConfig struct {
 type FilterConfig struct {
 	Query     string `json:"query,omitempty"`
 	UseSearch bool   `json:"use_search,omitempty"`
+
+	// Search scopes; when none are set the search is limited to github.owner/github.repo
+	Org          string   `json:"org,omitempty"`          // every repository of an organization
+	Repositories []string `json:"repositories,omitempty"` // explicit "owner/repo" list
+	User         string   `json:"user,omitempty"`         // repositories owned by a user
 }
 
 // OutputConfig contains output formatting configuration
@@ -54,9 +182,29 @@ type OutputConfig struct {
 	FilenamePattern   string           `json:"filename_pattern,omitempty"`
 	TimestampFormat   string           `json:"timestamp_format,omitempty"`
 	ProgressBarSegs   int              `json:"progress_bar_segments,omitempty"`
+	GroupBy           string           `json:"group_by,omitempty"` // "owner" adds a section of key results by owner
+	JSONSchema        int              `json:"json_schema,omitempty"` // 1 (default) or 2, see JSONSchemaWithProject
 	GoogleDocs        GoogleDocsConfig `json:"google_docs"`
 }
 
+// GroupByOwner groups key results by their owner in a section of their own
+const GroupByOwner = "owner"
+
+// JSON report schemas: version 1 is the array of objectives, version 2 an object holding the
+// project, with its incomplete flag and warnings, next to the objectives
+const (
+	JSONSchemaObjectives  = 1
+	JSONSchemaWithProject = 2
+)
+
+// GetJSONSchema returns the JSON report schema version, the objectives array unless set
+func (c OutputConfig) GetJSONSchema() int {
+	if c.JSONSchema == 0 {
+		return JSONSchemaObjectives
+	}
+	return c.JSONSchema
+}
+
 // GoogleDocsConfig contains Google Docs integration configuration
 // Note: OAuth credentials must be provided via GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET environment variables
 type GoogleDocsConfig struct {
@@ -120,6 +268,28 @@ func (c *Config) GetSearchQuery() string {
 	return "is:issue"
 }
 
+// GetSearchScopes returns the search qualifiers to run the query in, e.g. "org:acme" or "repo:acme/api".
+// Without configured scopes the search is limited to the given default repository.
+func (c *Config) GetSearchScopes(defaultOwner, defaultRepo string) []string {
+	var scopes []string
+	if c.Filter.Org != "" {
+		scopes = append(scopes, "org:"+c.Filter.Org)
+	}
+	for _, repo := range c.Filter.Repositories {
+		if trimmed := strings.TrimSpace(repo); trimmed != "" {
+			scopes = append(scopes, "repo:"+trimmed)
+		}
+	}
+	if c.Filter.User != "" {
+		scopes = append(scopes, "user:"+c.Filter.User)
+	}
+
+	if len(scopes) == 0 {
+		scopes = append(scopes, fmt.Sprintf("repo:%s/%s", defaultOwner, defaultRepo))
+	}
+	return scopes
+}
+
 // GetOutputFile generates an output filename
 func (c *Config) GetOutputFile(owner string, projectID, viewID int) string {
 	if c.Output.File != "" {
@@ -137,7 +307,7 @@ func (c *Config) GetOutputFile(owner string, projectID, viewID int) string {
 	if c.Output.TimestampFormat != "" {
 		timestampFormat = c.Output.TimestampFormat
 	}
-	timestamp := time.Now().Format(timestampFormat)
+	timestamp := c.Now().Format(timestampFormat)
 	
 	filenamePattern := "okr-report_%s_%d_%d_%s%s"
 	if c.Output.FilenamePattern != "" {
@@ -158,6 +328,37 @@ type CacheConfig struct {
 	IssuesTTLMin   int  `json:"issues_ttl_minutes,omitempty"`
 	CommentsTTLMin int  `json:"comments_ttl_minutes,omitempty"`
 	GraphQLTTLMin  int  `json:"graphql_ttl_minutes,omitempty"`
+
+	// Dir holds cached API responses between runs (default: user cache directory)
+	Dir string `json:"dir,omitempty"`
+
+	// SyncStateFile stores comment sync state between runs (default: user cache directory)
+	SyncStateFile string `json:"sync_state_file,omitempty"`
+	// FullSync re-downloads every comment instead of only those changed since the last run
+	FullSync bool `json:"full_sync,omitempty"`
+}
+
+// GetIssuesTTL returns how long search results stay cached
+func (c CacheConfig) GetIssuesTTL() time.Duration {
+	return ttlMinutes(c.IssuesTTLMin, 10)
+}
+
+// GetCommentsTTL returns how long issue comments stay cached
+func (c CacheConfig) GetCommentsTTL() time.Duration {
+	return ttlMinutes(c.CommentsTTLMin, 5)
+}
+
+// GetGraphQLTTL returns how long GraphQL responses stay cached
+func (c CacheConfig) GetGraphQLTTL() time.Duration {
+	return ttlMinutes(c.GraphQLTTLMin, 5)
+}
+
+// ttlMinutes converts a configured TTL in minutes, falling back to a default when unset
+func ttlMinutes(configured, fallback int) time.Duration {
+	if configured <= 0 {
+		configured = fallback
+	}
+	return time.Duration(configured) * time.Minute
 }
 
 // PatternsConfig contains regex patterns for detection
@@ -172,4 +373,130 @@ type StatusDetectionConfig struct {
 	BlockedKeywords   []string `json:"blocked_keywords,omitempty"`
 	AtRiskKeywords    []string `json:"at_risk_keywords,omitempty"`
 	OnTrackKeywords   []string `json:"on_track_keywords,omitempty"`
-}
\ No newline at end of file
+}
+
+// ProjectFieldsConfig controls how custom ProjectV2 fields are used
+type ProjectFieldsConfig struct {
+	// StatusField names the single-select field that is authoritative for KR status
+	StatusField string `json:"status_field,omitempty"`
+	// StatusMapping maps field option names to statuses (on-track, caution, at-risk, delayed, blocked, completed)
+	StatusMapping map[string]string `json:"status_mapping,omitempty"`
+	// Columns lists the fields shown alongside each issue in reports
+	Columns []string `json:"columns,omitempty"`
+}
+
+// ResolveProjectStatus returns the status an issue carries in the configured status field
+func (c *Config) ResolveProjectStatus(issue *Issue) WeeklyUpdateStatus {
+	if c.ProjectFields.StatusField == "" {
+		return StatusUnknown
+	}
+
+	field := issue.GetField(c.ProjectFields.StatusField)
+	if field == nil || field.Text == "" {
+		return StatusUnknown
+	}
+
+	for option, status := range c.ProjectFields.StatusMapping {
+		if strings.EqualFold(option, field.Text) {
+			return ParseStatus(status)
+		}
+	}
+	return ParseStatus(field.Text)
+}
+
+// HierarchyConfig names the levels of the OKR tree from the root down
+type HierarchyConfig struct {
+	// Levels lists level names by depth, e.g. ["Company Objective", "Team Objective", "Key Result", "Initiative"]
+	Levels []string `json:"levels,omitempty"`
+	// KeyResultLevel names the level whose issues are key results; required unless a level is named "Key Result"
+	KeyResultLevel string `json:"key_result_level,omitempty"`
+	// Plurals names levels in report headings, e.g. {"Capability": "Capabilities"}
+	Plurals map[string]string `json:"plurals,omitempty"`
+}
+
+// defaultLevelPlurals are the plurals of the built-in level names
+var defaultLevelPlurals = map[string]string{
+	"objective":  "Objectives",
+	"key result": "Key Results",
+	"initiative": "Initiatives",
+}
+
+// PluralName returns the plural of a level name for headings: the configured plural, the
+// built-in one for the default names, or else the name unchanged
+func (h HierarchyConfig) PluralName(level string) string {
+	if plural := h.Plurals[level]; plural != "" {
+		return plural
+	}
+	for name, plural := range h.Plurals {
+		if strings.EqualFold(name, level) && plural != "" {
+			return plural
+		}
+	}
+	if plural, ok := defaultLevelPlurals[strings.ToLower(level)]; ok {
+		return plural
+	}
+	return level
+}
+
+// GetLevels returns the configured level names, defaulting to Objective and Key Result
+func (h HierarchyConfig) GetLevels() []string {
+	if len(h.Levels) == 0 {
+		return []string{"Objective", "Key Result"}
+	}
+	return h.Levels
+}
+
+// LevelName returns the name of the level at the given depth; deeper issues reuse the last name
+func (h HierarchyConfig) LevelName(depth int) string {
+	levels := h.GetLevels()
+	if depth >= len(levels) {
+		return levels[len(levels)-1]
+	}
+	return levels[depth]
+}
+
+// defaultKeyResultLevel is the level holding key results when key_result_level is not set
+const defaultKeyResultLevel = "Key Result"
+
+// KeyResultDepth returns the depth at which issues are key results, or -1 when no level is the
+// key result level, which Validate reports
+func (h HierarchyConfig) KeyResultDepth() int {
+	name := h.KeyResultLevel
+	if name == "" {
+		name = defaultKeyResultLevel
+	}
+
+	for depth, level := range h.GetLevels() {
+		if strings.EqualFold(level, name) {
+			return depth
+		}
+	}
+	return -1
+}
+
+// Validate checks that key_result_level, or "Key Result" when it is not set, is one of the levels:
+// which depth holds key results is never guessed
+func (h HierarchyConfig) Validate() error {
+	if h.KeyResultDepth() >= 0 {
+		return nil
+	}
+
+	levels := strings.Join(h.GetLevels(), ", ")
+	if h.KeyResultLevel == "" {
+		return fmt.Errorf("hierarchy.key_result_level is required when hierarchy.levels (%s) do not include %q", levels, defaultKeyResultLevel)
+	}
+	return fmt.Errorf("hierarchy.key_result_level %q is not one of hierarchy.levels (%s)", h.KeyResultLevel, levels)
+}
+
+// TypeForDepth classifies an issue by its depth relative to the key result level
+func (h HierarchyConfig) TypeForDepth(depth int) IssueType {
+	krDepth := h.KeyResultDepth()
+	switch {
+	case depth < krDepth:
+		return IssueTypeObjective
+	case depth == krDepth:
+		return IssueTypeKeyResult
+	default:
+		return IssueTypeInitiative
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHierarchyKeyResultLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		hierarchy HierarchyConfig
+		wantDepth int
+		wantErr   string
+	}{
+		{"defaults", HierarchyConfig{}, 1, ""},
+		{"named Key Result", HierarchyConfig{Levels: []string{"Company Objective", "Team Objective", "key result", "Initiative"}}, 2, ""},
+		{"configured level", HierarchyConfig{Levels: []string{"Goal", "Outcome", "Task"}, KeyResultLevel: "Outcome"}, 1, ""},
+		{"single configured level", HierarchyConfig{Levels: []string{"Goal"}, KeyResultLevel: "Goal"}, 0, ""},
+		{"no Key Result level", HierarchyConfig{Levels: []string{"Goal", "Outcome", "Task"}}, -1, "hierarchy.key_result_level is required"},
+		{"unknown configured level", HierarchyConfig{Levels: []string{"Goal", "Outcome"}, KeyResultLevel: "Result"}, -1, `"Result" is not one of hierarchy.levels (Goal, Outcome)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hierarchy.KeyResultDepth(); got != tt.wantDepth {
+				t.Errorf("KeyResultDepth() = %d, want %d", got, tt.wantDepth)
+			}
+			err := tt.hierarchy.Validate()
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Errorf("Validate() = %v, want no error", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("Validate() = %v, want an error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
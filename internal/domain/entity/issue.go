@@ -1,11 +1,20 @@
 package entity
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // IssueType represents the type of an issue in the OKR system
 type IssueType string
 
 const (
 	IssueTypeObjective IssueType = "objective"
 	IssueTypeKeyResult IssueType = "kr"
+	// IssueTypeInitiative marks work items below the key result level
+	IssueTypeInitiative IssueType = "initiative"
 )
 
 // Issue represents a GitHub issue in our OKR system
@@ -17,6 +26,80 @@ type Issue struct {
 	Body   string    `json:"body,omitempty"`
 	State  string    `json:"state,omitempty"`
 	Labels []string  `json:"labels,omitempty"`
+
+	// Author opened the issue; the first of its Assignees owns it
+	Author    string     `json:"author,omitempty"`
+	Assignees []string   `json:"assignees,omitempty"`
+	Milestone *Milestone `json:"milestone,omitempty"`
+	// CreatedAt and UpdatedAt are nil when unknown, e.g. for draft items
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+
+	// Parent is the sub-issue parent reported by GitHub, when there is one
+	Parent *Issue `json:"-"`
+	// Depth is the distance from the root of the OKR tree; Level is the configured name of that depth
+	Depth int    `json:"depth,omitempty"`
+	Level string `json:"level,omitempty"`
+
+	// Fields holds the custom ProjectV2 field values set on the board item
+	Fields []ProjectFieldValue `json:"fields,omitempty"`
+	// ProjectStatus is the status taken from the configured project status field
+	ProjectStatus WeeklyUpdateStatus `json:"project_status,omitempty"`
+	// Timeline is the issue's history, oldest first: opened, closed, reopened, labeled and project status changes
+	Timeline []IssueEvent `json:"timeline,omitempty"`
+	// PullRequests are the pull requests that reference or close the issue
+	PullRequests []PullRequest `json:"pull_requests,omitempty"`
+}
+
+// SearchResult holds the issues found by a search together with how many matched
+type SearchResult struct {
+	Issues     []*Issue `json:"issues"`
+	TotalCount int      `json:"total_count"`
+	// Truncated is set when not every matching issue could be collected
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ProjectFieldType represents the data type of a ProjectV2 field
+type ProjectFieldType string
+
+const (
+	FieldTypeText         ProjectFieldType = "text"
+	FieldTypeNumber       ProjectFieldType = "number"
+	FieldTypeDate         ProjectFieldType = "date"
+	FieldTypeSingleSelect ProjectFieldType = "single_select"
+	FieldTypeIteration    ProjectFieldType = "iteration"
+)
+
+// ProjectFieldValue represents the value of a custom ProjectV2 field on an issue
+type ProjectFieldValue struct {
+	Name string           `json:"name"`
+	Type ProjectFieldType `json:"type"`
+	// Text holds text values, the selected option name or the iteration title
+	Text   string   `json:"text,omitempty"`
+	Number *float64 `json:"number,omitempty"`
+	// Date holds date values and the iteration start date (YYYY-MM-DD)
+	Date         string `json:"date,omitempty"`
+	DurationDays int    `json:"duration_days,omitempty"`
+}
+
+// String returns the display value of the field
+func (v ProjectFieldValue) String() string {
+	switch v.Type {
+	case FieldTypeNumber:
+		if v.Number == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*v.Number, 'f', -1, 64)
+	case FieldTypeDate:
+		return v.Date
+	case FieldTypeIteration:
+		if v.Date != "" {
+			return fmt.Sprintf("%s (%s)", v.Text, v.Date)
+		}
+		return v.Text
+	default:
+		return v.Text
+	}
 }
 
 // WeeklyUpdateStatus represents the status of a weekly update
@@ -32,6 +115,29 @@ const (
 	StatusUnknown   WeeklyUpdateStatus = "unknown"
 )
 
+// ParseStatus maps a free-form status name such as "On Track", "At risk" or "Done"
+// onto a WeeklyUpdateStatus, returning StatusUnknown when it is not recognised
+func ParseStatus(value string) WeeklyUpdateStatus {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	normalized = strings.NewReplacer(" ", "-", "_", "-").Replace(normalized)
+
+	switch normalized {
+	case "on-track", "ontrack", "green":
+		return StatusOnTrack
+	case "caution", "yellow":
+		return StatusCaution
+	case "delayed", "off-track", "behind", "red":
+		return StatusDelayed
+	case "at-risk", "atrisk", "risk":
+		return StatusAtRisk
+	case "blocked":
+		return StatusBlocked
+	case "completed", "complete", "done", "closed":
+		return StatusCompleted
+	}
+	return StatusUnknown
+}
+
 // WeeklyUpdate represents a weekly status update from issue comments
 type WeeklyUpdate struct {
 	Date    string             `json:"date"`
@@ -46,6 +152,8 @@ type IssueWithUpdates struct {
 	LatestUpdate *WeeklyUpdate      `json:"latest_update,omitempty"`
 	AllUpdates   []WeeklyUpdate     `json:"all_updates,omitempty"`
 	ChildIssues  []IssueWithUpdates `json:"child_issues,omitempty"`
+	// PullRequestEvidence is set when the issue's timeline, and with it its pull requests, was fetched
+	PullRequestEvidence *PullRequestEvidence `json:"pull_request_evidence,omitempty"`
 }
 
 // IsObjective returns true if the issue is an objective
@@ -58,6 +166,30 @@ func (i *Issue) IsKeyResult() bool {
 	return i.Type == IssueTypeKeyResult
 }
 
+// IsInitiative returns true if the issue sits below the key result level
+func (i *Issue) IsInitiative() bool {
+	return i.Type == IssueTypeInitiative
+}
+
+// Ref returns the fully qualified reference of the issue, derived from its URL
+func (i *Issue) Ref() IssueRef {
+	ref, err := ParseIssueRef(i.URL, IssueRef{})
+	if err != nil {
+		return IssueRef{Number: i.Number}
+	}
+	return ref
+}
+
+// GetField returns the project field with the given name (case-insensitive), or nil
+func (i *Issue) GetField(name string) *ProjectFieldValue {
+	for idx := range i.Fields {
+		if strings.EqualFold(i.Fields[idx].Name, name) {
+			return &i.Fields[idx]
+		}
+	}
+	return nil
+}
+
 // HasLabel checks if the issue has a specific label
 func (i *Issue) HasLabel(label string) bool {
 	for _, l := range i.Labels {
@@ -109,6 +241,11 @@ func (i *IssueWithUpdates) GetActualStatus() WeeklyUpdateStatus {
 	if i.Issue.State == "closed" {
 		return StatusCompleted
 	}
+
+	// The configured project status field is authoritative over comment heuristics
+	if i.Issue.ProjectStatus != "" && i.Issue.ProjectStatus != StatusUnknown {
+		return i.Issue.ProjectStatus
+	}
 	
 	// If the update says "completed" but the GitHub issue is still open, 
 	// it can't be truly completed - downgrade based on the actual update content
@@ -123,8 +260,8 @@ func (i *IssueWithUpdates) GetActualStatus() WeeklyUpdateStatus {
 // GetKRStatus returns the KR status based on the latest weekly update symbol
 // This prioritizes the status symbol from the most recent weekly update
 func (i *IssueWithUpdates) GetKRStatus() WeeklyUpdateStatus {
-	// If this is not a KR, use the original status
-	if !i.Issue.IsKeyResult() {
+	// If this is not a KR (or a work item below one), use the original status
+	if !i.Issue.IsKeyResult() && !i.Issue.IsInitiative() {
 		return i.GetActualStatus()
 	}
 	
@@ -132,6 +269,11 @@ func (i *IssueWithUpdates) GetKRStatus() WeeklyUpdateStatus {
 	if i.Issue.State == "closed" {
 		return StatusCompleted
 	}
+
+	// The configured project status field is authoritative over comment heuristics
+	if i.Issue.ProjectStatus != "" && i.Issue.ProjectStatus != StatusUnknown {
+		return i.Issue.ProjectStatus
+	}
 	
 	// Look for the most recent weekly update with a valid status
 	// Search through all updates to find the latest one with meaningful status
@@ -168,12 +310,79 @@ func (i *IssueWithUpdates) GetObjectiveStatus() WeeklyUpdateStatus {
 	if !i.Issue.IsObjective() || len(i.ChildIssues) == 0 {
 		return i.GetActualStatus()
 	}
-	
+
+	return i.GetRolledUpStatus()
+}
+
+// GetRolledUpStatus returns the status of the issue rolled up through its whole subtree.
+// Objectives aggregate their children; key results and the levels below them report
+// their own status and only fall back to their children when they have none.
+func (i *IssueWithUpdates) GetRolledUpStatus() WeeklyUpdateStatus {
+	if len(i.ChildIssues) == 0 {
+		return i.GetKRStatus()
+	}
+
+	if !i.Issue.IsObjective() {
+		if status := i.GetKRStatus(); status != StatusUnknown {
+			return status
+		}
+	}
+
+	statuses := make([]WeeklyUpdateStatus, 0, len(i.ChildIssues))
+	for idx := range i.ChildIssues {
+		statuses = append(statuses, i.ChildIssues[idx].GetRolledUpStatus())
+	}
+	return aggregateStatuses(statuses)
+}
+
+// GetProgress returns the completed share of the issue between 0 and 1.
+// Closed or completed issues count as done; otherwise progress is the mean of the children.
+func (i *IssueWithUpdates) GetProgress() float64 {
+	if i.Issue.State == "closed" {
+		return 1
+	}
+
+	if len(i.ChildIssues) == 0 {
+		if i.GetKRStatus() == StatusCompleted {
+			return 1
+		}
+		return 0
+	}
+
+	var total float64
+	for idx := range i.ChildIssues {
+		total += i.ChildIssues[idx].GetProgress()
+	}
+	return total / float64(len(i.ChildIssues))
+}
+
+// CollectKeyResults returns every key result in the given trees, in depth-first order
+func CollectKeyResults(roots []*IssueWithUpdates) []*IssueWithUpdates {
+	var keyResults []*IssueWithUpdates
+
+	var walk func(node *IssueWithUpdates)
+	walk = func(node *IssueWithUpdates) {
+		if node.Issue.IsKeyResult() {
+			keyResults = append(keyResults, node)
+		}
+		for idx := range node.ChildIssues {
+			walk(&node.ChildIssues[idx])
+		}
+	}
+
+	for _, root := range roots {
+		walk(root)
+	}
+	return keyResults
+}
+
+// aggregateStatuses derives a single status from the statuses of child issues
+func aggregateStatuses(statuses []WeeklyUpdateStatus) WeeklyUpdateStatus {
 	// Count KR statuses
 	var completed, blocked, delayed, atRisk, caution, onTrack, unknown int
 	
-	for _, kr := range i.ChildIssues {
-		switch kr.GetKRStatus() {
+	for _, status := range statuses {
+		switch status {
 		case StatusCompleted:
 			completed++
 		case StatusBlocked:
@@ -191,7 +400,7 @@ func (i *IssueWithUpdates) GetObjectiveStatus() WeeklyUpdateStatus {
 		}
 	}
 	
-	totalKRs := len(i.ChildIssues)
+	totalKRs := len(statuses)
 	
 	// Determine objective status based on KR aggregation
 	// Priority order: Blocked > Delayed > AtRisk > Caution > Completed > OnTrack > Unknown
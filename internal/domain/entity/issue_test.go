@@ -0,0 +1,203 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// node builds an issue of the given type and state with weekly updates, newest first
+func node(issueType IssueType, state string, updates ...WeeklyUpdateStatus) IssueWithUpdates {
+	n := IssueWithUpdates{Issue: Issue{Type: issueType, State: state}}
+	for _, status := range updates {
+		n.AllUpdates = append(n.AllUpdates, WeeklyUpdate{Status: status})
+	}
+	if len(n.AllUpdates) > 0 {
+		n.LatestUpdate = &n.AllUpdates[0]
+	}
+	return n
+}
+
+func TestIssueOmitsUnknownTimestamps(t *testing.T) {
+	data, err := json.Marshal(Issue{Number: 1, Title: "Draft"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "created_at") || strings.Contains(string(data), "updated_at") {
+		t.Errorf("issue without timestamps marshaled as %s", data)
+	}
+
+	created := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	data, err = json.Marshal(Issue{Number: 1, CreatedAt: &created})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"created_at":"2025-01-02T00:00:00Z"`) || strings.Contains(string(data), "updated_at") {
+		t.Errorf("issue with a creation time marshaled as %s", data)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := map[string]WeeklyUpdateStatus{
+		"On Track":    StatusOnTrack,
+		"on_track":    StatusOnTrack,
+		" Green ":     StatusOnTrack,
+		"Yellow":      StatusCaution,
+		"Off track":   StatusDelayed,
+		"At risk":     StatusAtRisk,
+		"BLOCKED":     StatusBlocked,
+		"Done":        StatusCompleted,
+		"In progress": StatusUnknown,
+		"":            StatusUnknown,
+	}
+
+	for value, want := range tests {
+		if got := ParseStatus(value); got != want {
+			t.Errorf("ParseStatus(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
+
+func TestGetKRStatus(t *testing.T) {
+	withProjectStatus := node(IssueTypeKeyResult, "open", StatusOnTrack)
+	withProjectStatus.Issue.ProjectStatus = StatusAtRisk
+
+	tests := []struct {
+		name string
+		kr   IssueWithUpdates
+		want WeeklyUpdateStatus
+	}{
+		{"closed issue is completed", node(IssueTypeKeyResult, "closed", StatusBlocked), StatusCompleted},
+		{"project status wins over updates", withProjectStatus, StatusAtRisk},
+		{"latest known update", node(IssueTypeKeyResult, "open", StatusUnknown, StatusCaution, StatusOnTrack), StatusCaution},
+		{"completed update on open issue", node(IssueTypeKeyResult, "open", StatusCompleted), StatusOnTrack},
+		{"no updates", node(IssueTypeKeyResult, "open"), StatusUnknown},
+		{"initiative uses its updates", node(IssueTypeInitiative, "open", StatusDelayed), StatusDelayed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.kr.GetKRStatus(); got != tt.want {
+				t.Errorf("GetKRStatus() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetObjectiveStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		children []WeeklyUpdateStatus
+		want     WeeklyUpdateStatus
+	}{
+		{"blocked beats everything", []WeeklyUpdateStatus{StatusCompleted, StatusCaution, StatusBlocked}, StatusBlocked},
+		{"delayed beats at risk", []WeeklyUpdateStatus{StatusAtRisk, StatusDelayed}, StatusDelayed},
+		{"at risk beats caution", []WeeklyUpdateStatus{StatusCaution, StatusAtRisk}, StatusAtRisk},
+		{"caution beats on track", []WeeklyUpdateStatus{StatusOnTrack, StatusCaution}, StatusCaution},
+		{"all completed", []WeeklyUpdateStatus{StatusCompleted, StatusCompleted}, StatusCompleted},
+		{"half completed", []WeeklyUpdateStatus{StatusCompleted, StatusUnknown}, StatusOnTrack},
+		{"some on track", []WeeklyUpdateStatus{StatusOnTrack, StatusUnknown, StatusUnknown}, StatusOnTrack},
+		{"nothing known", []WeeklyUpdateStatus{StatusUnknown, StatusUnknown, StatusUnknown}, StatusUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objective := node(IssueTypeObjective, "open")
+			for _, status := range tt.children {
+				// Completed key results are closed; open ones would be downgraded to on track
+				state := "open"
+				if status == StatusCompleted {
+					state = "closed"
+				}
+				objective.ChildIssues = append(objective.ChildIssues, node(IssueTypeKeyResult, state, status))
+			}
+			if got := objective.GetObjectiveStatus(); got != tt.want {
+				t.Errorf("GetObjectiveStatus() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("objective without key results uses its own updates", func(t *testing.T) {
+		objective := node(IssueTypeObjective, "open", StatusCompleted)
+		if got := objective.GetObjectiveStatus(); got != StatusOnTrack {
+			t.Errorf("GetObjectiveStatus() = %s, want %s", got, StatusOnTrack)
+		}
+	})
+}
+
+func TestGetRolledUpStatus(t *testing.T) {
+	// A key result without updates of its own reports the status of its initiatives
+	silent := node(IssueTypeKeyResult, "open")
+	silent.ChildIssues = []IssueWithUpdates{
+		node(IssueTypeInitiative, "open", StatusOnTrack),
+		node(IssueTypeInitiative, "open", StatusAtRisk),
+	}
+	// A key result with updates of its own ignores its initiatives
+	reporting := node(IssueTypeKeyResult, "open", StatusOnTrack)
+	reporting.ChildIssues = []IssueWithUpdates{node(IssueTypeInitiative, "open", StatusBlocked)}
+
+	if got := silent.GetRolledUpStatus(); got != StatusAtRisk {
+		t.Errorf("silent key result = %s, want %s", got, StatusAtRisk)
+	}
+	if got := reporting.GetRolledUpStatus(); got != StatusOnTrack {
+		t.Errorf("reporting key result = %s, want %s", got, StatusOnTrack)
+	}
+
+	objective := node(IssueTypeObjective, "open")
+	objective.ChildIssues = []IssueWithUpdates{silent, reporting}
+	if got := objective.GetRolledUpStatus(); got != StatusAtRisk {
+		t.Errorf("objective = %s, want %s", got, StatusAtRisk)
+	}
+}
+
+func TestGetProgress(t *testing.T) {
+	kr := node(IssueTypeKeyResult, "open")
+	kr.ChildIssues = []IssueWithUpdates{
+		node(IssueTypeInitiative, "closed"),
+		node(IssueTypeInitiative, "open", StatusOnTrack),
+	}
+	objective := node(IssueTypeObjective, "open")
+	objective.ChildIssues = []IssueWithUpdates{
+		kr,
+		node(IssueTypeKeyResult, "closed"),
+		node(IssueTypeKeyResult, "open", StatusCompleted), // Downgraded while the issue is open
+		node(IssueTypeKeyResult, "open"),
+	}
+
+	tests := []struct {
+		name string
+		node IssueWithUpdates
+		want string
+	}{
+		{"key result with initiatives", kr, "0.50"},
+		{"objective", objective, "0.38"},
+		{"closed objective", node(IssueTypeObjective, "closed"), "1.00"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprintf("%.2f", tt.node.GetProgress()); got != tt.want {
+			t.Errorf("%s: GetProgress() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCollectKeyResults(t *testing.T) {
+	first := node(IssueTypeObjective, "open")
+	first.ChildIssues = []IssueWithUpdates{node(IssueTypeKeyResult, "open"), node(IssueTypeKeyResult, "open")}
+	first.ChildIssues[0].Issue.Number = 2
+	first.ChildIssues[1].Issue.Number = 3
+	first.ChildIssues[0].ChildIssues = []IssueWithUpdates{node(IssueTypeInitiative, "open")}
+	second := node(IssueTypeObjective, "open")
+	second.ChildIssues = []IssueWithUpdates{node(IssueTypeKeyResult, "open")}
+	second.ChildIssues[0].Issue.Number = 5
+
+	var numbers []int
+	for _, kr := range CollectKeyResults([]*IssueWithUpdates{&first, &second}) {
+		numbers = append(numbers, kr.Issue.Number)
+	}
+	if fmt.Sprint(numbers) != "[2 3 5]" {
+		t.Errorf("key results = %v, want [2 3 5]", numbers)
+	}
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Milestone is the milestone an issue is planned for
+type Milestone struct {
+	Title string     `json:"title"`
+	DueOn *time.Time `json:"due_on,omitempty"`
+	State string     `json:"state,omitempty"` // "open" or "closed"
+}
+
+// DueState tells how an open issue stands against the due date of its milestone
+type DueState string
+
+const (
+	DueNone    DueState = ""
+	DueSoon    DueState = "due-soon"
+	DueOverdue DueState = "overdue"
+)
+
+// DueSoonWindow is how close a milestone's due date must be for its open issues to be due soon
+const DueSoonWindow = 14 * 24 * time.Hour
+
+// DueState returns whether the issue is overdue or due soon as of now. Closed issues and
+// issues without a dated milestone are neither.
+func (i *Issue) DueState(now time.Time) DueState {
+	if i.State == "closed" || i.Milestone == nil || i.Milestone.DueOn == nil {
+		return DueNone
+	}
+	due := *i.Milestone.DueOn
+	switch {
+	case now.After(due):
+		return DueOverdue
+	case due.Sub(now) <= DueSoonWindow:
+		return DueSoon
+	}
+	return DueNone
+}
+
+// OwnerLogin returns the login of the person accountable for the issue: its first assignee,
+// or "" when nobody is assigned
+func (i *Issue) OwnerLogin() string {
+	if len(i.Assignees) == 0 {
+		return ""
+	}
+	return i.Assignees[0]
+}
+
+// OwnerGroup is the key results owned by one person; Owner is "" for unassigned key results
+type OwnerGroup struct {
+	Owner      string
+	KeyResults []*IssueWithUpdates
+}
+
+// GroupKeyResultsByOwner groups the key results in the given trees by owner, ordered by login
+// with unassigned key results last. Key results keep their report order within a group.
+func GroupKeyResultsByOwner(roots []*IssueWithUpdates) []OwnerGroup {
+	var groups []OwnerGroup
+	index := make(map[string]int)
+	for _, kr := range CollectKeyResults(roots) {
+		owner := kr.Issue.OwnerLogin()
+		key := strings.ToLower(owner)
+		idx, found := index[key]
+		if !found {
+			idx = len(groups)
+			index[key] = idx
+			groups = append(groups, OwnerGroup{Owner: owner})
+		}
+		groups[idx].KeyResults = append(groups[idx].KeyResults, kr)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Owner == "" || groups[j].Owner == "" {
+			return groups[j].Owner == "" && groups[i].Owner != ""
+		}
+		return strings.ToLower(groups[i].Owner) < strings.ToLower(groups[j].Owner)
+	})
+	return groups
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDueState(t *testing.T) {
+	now := time.Date(2025, 1, 31, 9, 0, 0, 0, time.UTC)
+	due := func(days int) *Milestone {
+		dueOn := now.Add(time.Duration(days) * 24 * time.Hour)
+		return &Milestone{Title: "Q1", DueOn: &dueOn}
+	}
+
+	tests := []struct {
+		name  string
+		issue Issue
+		want  DueState
+	}{
+		{"overdue", Issue{State: "open", Milestone: due(-1)}, DueOverdue},
+		{"due within two weeks", Issue{State: "open", Milestone: due(14)}, DueSoon},
+		{"due later", Issue{State: "open", Milestone: due(15)}, DueNone},
+		{"closed issues are never late", Issue{State: "closed", Milestone: due(-1)}, DueNone},
+		{"milestone without a due date", Issue{State: "open", Milestone: &Milestone{Title: "Someday"}}, DueNone},
+		{"no milestone", Issue{State: "open"}, DueNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.issue.DueState(now); got != tt.want {
+				t.Errorf("DueState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGroupKeyResultsByOwner(t *testing.T) {
+	kr := func(number int, assignees ...string) IssueWithUpdates {
+		n := node(IssueTypeKeyResult, "open")
+		n.Issue.Number = number
+		n.Issue.Assignees = assignees
+		return n
+	}
+	objective := node(IssueTypeObjective, "open")
+	objective.ChildIssues = []IssueWithUpdates{kr(2, "carol"), kr(3), kr(4, "Alice", "carol"), kr(5, "carol")}
+	objective.ChildIssues[0].ChildIssues = []IssueWithUpdates{node(IssueTypeInitiative, "open")}
+
+	var got []string
+	for _, group := range GroupKeyResultsByOwner([]*IssueWithUpdates{&objective}) {
+		var numbers []string
+		for _, kr := range group.KeyResults {
+			numbers = append(numbers, fmt.Sprint(kr.Issue.Number))
+		}
+		got = append(got, fmt.Sprintf("%q:%s", group.Owner, strings.Join(numbers, ",")))
+	}
+	want := `"Alice":4 "carol":2,5 "":3`
+	if strings.Join(got, " ") != want {
+		t.Errorf("groups = %s, want %s", strings.Join(got, " "), want)
+	}
+}
@@ -1,5 +1,7 @@
 package entity
 
+import "fmt"
+
 // ProjectType represents the type of GitHub project
 type ProjectType string
 
@@ -10,12 +12,29 @@ const (
 
 // ProjectInfo contains information about a GitHub project
 type ProjectInfo struct {
-	Owner     string      `json:"owner"`
-	Repo      string      `json:"repo,omitempty"`
-	ProjectID int         `json:"project_id"`
-	ViewID    int         `json:"view_id,omitempty"`
-	Type      ProjectType `json:"type"`
-	URL       string      `json:"url,omitempty"`
+	// Host is the GitHub host the project lives on; empty means github.com
+	Host      string       `json:"host,omitempty"`
+	Owner     string       `json:"owner"`
+	Repo      string       `json:"repo,omitempty"`
+	ProjectID int          `json:"project_id"`
+	ViewID    int          `json:"view_id,omitempty"`
+	Type      ProjectType  `json:"type"`
+	URL       string       `json:"url,omitempty"`
+	View      *ProjectView `json:"view,omitempty"`
+	// Warnings collects problems that make the report incomplete, such as truncated results
+	Warnings []string `json:"warnings,omitempty"`
+	// Incomplete is set when fetching stopped early, e.g. on Ctrl-C or when the run timed out
+	Incomplete bool `json:"incomplete"`
+}
+
+// ProjectView describes the saved project view a report was generated from
+type ProjectView struct {
+	Number  int      `json:"number"`
+	Name    string   `json:"name"`
+	Layout  string   `json:"layout,omitempty"`
+	Filter  string   `json:"filter,omitempty"`
+	SortBy  []string `json:"sort_by,omitempty"`
+	GroupBy []string `json:"group_by,omitempty"`
 }
 
 // IsOrganizationProject returns true if this is an organization project
@@ -28,11 +47,66 @@ func (p *ProjectInfo) IsRepositoryProject() bool {
 	return p.Type == ProjectTypeRepository
 }
 
+// AddWarning records a problem that should be shown in the report
+func (p *ProjectInfo) AddWarning(format string, args ...interface{}) {
+	p.Warnings = append(p.Warnings, fmt.Sprintf(format, args...))
+}
+
+// MarkIncomplete flags the report as built from partially fetched data
+func (p *ProjectInfo) MarkIncomplete(reason string) {
+	p.Incomplete = true
+	p.AddWarning("This report is incomplete: %s before all data was fetched", reason)
+}
+
 // HasView returns true if the project has a specific view
 func (p *ProjectInfo) HasView() bool {
 	return p.ViewID > 0
 }
 
+// WebURL returns the link to the project, or to its view when one was given
+func (p *ProjectInfo) WebURL() string {
+	host := p.Host
+	if host == "" {
+		host = DefaultHost
+	}
+
+	var link string
+	if p.IsRepositoryProject() {
+		link = fmt.Sprintf("https://%s/%s/%s/projects/%d", host, p.Owner, p.Repo, p.ProjectID)
+	} else {
+		link = fmt.Sprintf("https://%s/orgs/%s/projects/%d", host, p.Owner, p.ProjectID)
+	}
+	if p.HasView() {
+		link += fmt.Sprintf("/views/%d", p.ViewID)
+	}
+	return link
+}
+
+// ProjectSummary describes a ProjectV2 board for discovering the project_url to configure
+type ProjectSummary struct {
+	Number int                   `json:"number"`
+	Title  string                `json:"title"`
+	URL    string                `json:"url"`
+	Closed bool                  `json:"closed,omitempty"`
+	Views  []ProjectViewSummary  `json:"views"`
+	Fields []ProjectFieldSummary `json:"fields"`
+}
+
+// ProjectViewSummary names a saved view and the URL that selects it
+type ProjectViewSummary struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	Layout string `json:"layout,omitempty"`
+	URL    string `json:"url"`
+}
+
+// ProjectFieldSummary describes a custom project field
+type ProjectFieldSummary struct {
+	Name     string   `json:"name"`
+	DataType string   `json:"data_type"`
+	Options  []string `json:"options,omitempty"`
+}
+
 // Project represents a complete project with objectives and metadata
 type Project struct {
 	Info       *ProjectInfo           `json:"info"`
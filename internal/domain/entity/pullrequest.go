@@ -0,0 +1,77 @@
+package entity
+
+import "time"
+
+// PullRequestState is the state of a pull request linked to an issue
+type PullRequestState string
+
+const (
+	PullRequestOpen   PullRequestState = "open"
+	PullRequestClosed PullRequestState = "closed"
+	PullRequestMerged PullRequestState = "merged"
+)
+
+// PullRequest is a pull request that references or closes an issue
+type PullRequest struct {
+	// Repository is the owner/repo the pull request lives in
+	Repository string           `json:"repository"`
+	Number     int              `json:"number"`
+	Title      string           `json:"title"`
+	URL        string           `json:"url"`
+	State      PullRequestState `json:"state"`
+	CreatedAt  time.Time        `json:"created_at"`
+	MergedAt   *time.Time       `json:"merged_at,omitempty"`
+	// Closes is set when merging the pull request closes the issue
+	Closes bool `json:"closes,omitempty"`
+}
+
+// IsMerged returns true if the pull request was merged
+func (p *PullRequest) IsMerged() bool {
+	return p.State == PullRequestMerged && p.MergedAt != nil
+}
+
+// EvidenceWindow is how far back a merged pull request still counts as recent progress
+const EvidenceWindow = 28 * 24 * time.Hour
+
+// PullRequestEvidence summarizes the pull requests linked to an issue as of the report date
+type PullRequestEvidence struct {
+	Linked int `json:"linked"`
+	Open   int `json:"open"`
+	Merged int `json:"merged"`
+	// MergedSinceUpdate counts merges on or after the day of the latest weekly update,
+	// or every merge when there is no update
+	MergedSinceUpdate int `json:"merged_since_update"`
+	// MergedRecently counts merges within EvidenceWindow of the report date
+	MergedRecently int `json:"merged_recently"`
+	// NoRecentMerges flags a key result reported on track without a merge within EvidenceWindow
+	NoRecentMerges bool `json:"no_recent_merges,omitempty"`
+}
+
+// SummarizePullRequests computes the pull request evidence of the issue as of now
+func (n *IssueWithUpdates) SummarizePullRequests(now time.Time) *PullRequestEvidence {
+	var since time.Time
+	if n.LatestUpdate != nil {
+		since, _ = time.Parse("2006-01-02", n.LatestUpdate.Date)
+	}
+
+	evidence := &PullRequestEvidence{Linked: len(n.Issue.PullRequests)}
+	for _, pr := range n.Issue.PullRequests {
+		if pr.State == PullRequestOpen {
+			evidence.Open++
+		}
+		if !pr.IsMerged() {
+			continue
+		}
+		evidence.Merged++
+		if !pr.MergedAt.Before(since) {
+			evidence.MergedSinceUpdate++
+		}
+		if !pr.MergedAt.Before(now.Add(-EvidenceWindow)) && !pr.MergedAt.After(now) {
+			evidence.MergedRecently++
+		}
+	}
+
+	evidence.NoRecentMerges = n.Issue.IsKeyResult() && evidence.MergedRecently == 0 &&
+		n.GetRolledUpStatus() == StatusOnTrack
+	return evidence
+}
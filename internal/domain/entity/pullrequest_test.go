@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSummarizePullRequests(t *testing.T) {
+	asOf := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	merged := func(year int, month time.Month, day int) PullRequest {
+		at := time.Date(year, month, day, 15, 0, 0, 0, time.UTC)
+		return PullRequest{State: PullRequestMerged, MergedAt: &at}
+	}
+
+	onTrack := node(IssueTypeKeyResult, "open", StatusOnTrack)
+	onTrack.LatestUpdate.Date = "2025-01-13"
+	onTrack.Issue.PullRequests = []PullRequest{
+		merged(2024, 12, 20),
+		merged(2025, 1, 13), // Same day as the update
+		{State: PullRequestOpen},
+		{State: PullRequestClosed},
+	}
+	stale := node(IssueTypeKeyResult, "open", StatusOnTrack)
+	stale.Issue.PullRequests = []PullRequest{merged(2024, 12, 20)}
+	atRisk := node(IssueTypeKeyResult, "open", StatusAtRisk)
+	initiative := node(IssueTypeInitiative, "open", StatusOnTrack)
+
+	tests := []struct {
+		name string
+		node IssueWithUpdates
+		want PullRequestEvidence
+	}{
+		{"merged since the update", onTrack, PullRequestEvidence{Linked: 4, Open: 1, Merged: 2, MergedSinceUpdate: 1, MergedRecently: 1}},
+		{"on track without recent merges", stale, PullRequestEvidence{Linked: 1, Merged: 1, MergedSinceUpdate: 1, NoRecentMerges: true}},
+		{"at risk is not flagged", atRisk, PullRequestEvidence{}},
+		{"initiatives are not flagged", initiative, PullRequestEvidence{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.node.SummarizePullRequests(asOf); *got != tt.want {
+				t.Errorf("SummarizePullRequests() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
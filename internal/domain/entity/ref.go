@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultHost is the host of public GitHub
+const DefaultHost = "github.com"
+
+// IssueRef is a fully qualified reference to a GitHub issue
+type IssueRef struct {
+	Host   string `json:"host,omitempty"`
+	Owner  string `json:"owner"`
+	Repo   string `json:"repo"`
+	Number int    `json:"number"`
+}
+
+var (
+	issueURLPattern     = regexp.MustCompile(`^https?://([^/\s]+)/([\w.-]+)/([\w.-]+)/issues/(\d+)`)
+	qualifiedRefPattern = regexp.MustCompile(`^([\w.-]+)/([\w.-]+)#(\d+)$`)
+	shortRefPattern     = regexp.MustCompile(`^#(\d+)$`)
+)
+
+// ParseIssueRef parses an issue URL, an "owner/repo#N" reference or a "#N" reference.
+// Relative references inherit host, owner and repo from base.
+func ParseIssueRef(value string, base IssueRef) (IssueRef, error) {
+	value = strings.TrimSpace(value)
+
+	if matches := issueURLPattern.FindStringSubmatch(value); matches != nil {
+		number, _ := strconv.Atoi(matches[4])
+		return IssueRef{Host: matches[1], Owner: matches[2], Repo: matches[3], Number: number}, nil
+	}
+
+	if matches := qualifiedRefPattern.FindStringSubmatch(value); matches != nil {
+		number, _ := strconv.Atoi(matches[3])
+		return IssueRef{Host: base.Host, Owner: matches[1], Repo: matches[2], Number: number}, nil
+	}
+
+	if matches := shortRefPattern.FindStringSubmatch(value); matches != nil {
+		if base.Owner == "" || base.Repo == "" {
+			return IssueRef{}, fmt.Errorf("relative issue reference %q needs a repository", value)
+		}
+		number, _ := strconv.Atoi(matches[1])
+		return IssueRef{Host: base.Host, Owner: base.Owner, Repo: base.Repo, Number: number}, nil
+	}
+
+	return IssueRef{}, fmt.Errorf("invalid issue reference: %q", value)
+}
+
+// String returns the reference as "owner/repo#N", prefixed with the host outside github.com
+func (r IssueRef) String() string {
+	if r.Owner == "" || r.Repo == "" {
+		return fmt.Sprintf("#%d", r.Number)
+	}
+	if r.Host != "" && !strings.EqualFold(r.Host, DefaultHost) {
+		return fmt.Sprintf("%s/%s/%s#%d", r.Host, r.Owner, r.Repo, r.Number)
+	}
+	return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number)
+}
+
+// IsZero returns true if the reference does not point at an issue
+func (r IssueRef) IsZero() bool {
+	return r.Number == 0
+}
+
+// Key returns the canonical form of the reference for use as a map or cache key.
+// GitHub treats host, owner and repository names case-insensitively.
+func (r IssueRef) Key() IssueRef {
+	host := r.Host
+	if host == "" {
+		host = DefaultHost
+	}
+	return IssueRef{
+		Host:   strings.ToLower(host),
+		Owner:  strings.ToLower(r.Owner),
+		Repo:   strings.ToLower(r.Repo),
+		Number: r.Number,
+	}
+}
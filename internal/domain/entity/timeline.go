@@ -0,0 +1,114 @@
+package entity
+
+import "time"
+
+// IssueEventType is the kind of an event in an issue's timeline
+type IssueEventType string
+
+const (
+	EventOpened    IssueEventType = "opened"
+	EventClosed    IssueEventType = "closed"
+	EventReopened  IssueEventType = "reopened"
+	EventLabeled   IssueEventType = "labeled"
+	EventUnlabeled IssueEventType = "unlabeled"
+	// EventProjectStatusChanged records a change of the Status field on a project board
+	EventProjectStatusChanged IssueEventType = "project_status_changed"
+)
+
+// IssueEvent is one entry in the history of an issue
+type IssueEvent struct {
+	Type  IssueEventType `json:"type"`
+	At    time.Time      `json:"at"`
+	Actor string         `json:"actor,omitempty"`
+	// Label is the label added or removed
+	Label string `json:"label,omitempty"`
+	// StateReason tells why an issue was closed, e.g. "completed" or "not_planned"
+	StateReason string `json:"state_reason,omitempty"`
+	// Project, FromStatus and ToStatus describe a project status change
+	Project    string `json:"project,omitempty"`
+	FromStatus string `json:"from_status,omitempty"`
+	ToStatus   string `json:"to_status,omitempty"`
+}
+
+// CloseReasonCompleted is the StateReason of an issue closed as done, as opposed to e.g. "not_planned"
+const CloseReasonCompleted = "completed"
+
+// IssueTimeline is the history of an issue together with the pull requests linked to it
+type IssueTimeline struct {
+	Events       []IssueEvent
+	PullRequests []PullRequest
+}
+
+// OpenedAt returns when the issue was opened according to its timeline, falling back to
+// CreatedAt, or the zero time when neither is known
+func (i *Issue) OpenedAt() time.Time {
+	for _, event := range i.Timeline {
+		if event.Type == EventOpened {
+			return event.At
+		}
+	}
+	if i.CreatedAt == nil {
+		return time.Time{}
+	}
+	return *i.CreatedAt
+}
+
+// CompletedAt returns when a closed issue was last closed as completed. Open issues, issues
+// last closed for another reason such as "not_planned", and closed issues whose timeline was
+// not fetched have no completion date. Closes without a reason predate close reasons and count
+// as completed.
+func (i *Issue) CompletedAt() (time.Time, bool) {
+	if i.State != "closed" {
+		return time.Time{}, false
+	}
+	for idx := len(i.Timeline) - 1; idx >= 0; idx-- {
+		event := i.Timeline[idx]
+		if event.Type != EventClosed {
+			continue
+		}
+		if event.StateReason != "" && event.StateReason != CloseReasonCompleted {
+			return time.Time{}, false
+		}
+		return event.At, true
+	}
+	return time.Time{}, false
+}
+
+// ReopenCount returns how often the issue was reopened after being closed
+func (i *Issue) ReopenCount() int {
+	count := 0
+	for _, event := range i.Timeline {
+		if event.Type == EventReopened {
+			count++
+		}
+	}
+	return count
+}
+
+// CycleTime returns how long a completed issue took from being opened to its last close,
+// including any time it spent reopened
+func (i *Issue) CycleTime() (time.Duration, bool) {
+	completed, ok := i.CompletedAt()
+	opened := i.OpenedAt()
+	if !ok || opened.IsZero() || completed.Before(opened) {
+		return 0, false
+	}
+	return completed.Sub(opened), true
+}
+
+// AverageCycleTime returns the mean cycle time of the completed issues among keyResults
+// together with how many of them had a known cycle time
+func AverageCycleTime(keyResults []*IssueWithUpdates) (time.Duration, int) {
+	var total time.Duration
+	var count int
+	for _, kr := range keyResults {
+		if cycleTime, ok := kr.Issue.CycleTime(); ok {
+			total += cycleTime
+			count++
+		}
+	}
+	if count == 0 {
+		return 0, 0
+	}
+	return total / time.Duration(count), count
+}
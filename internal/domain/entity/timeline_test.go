@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+// withTimeline gives an issue in the given state a timeline of event types, one day apart from January 1st
+func withTimeline(state string, types ...IssueEventType) IssueWithUpdates {
+	n := node(IssueTypeKeyResult, state)
+	for i, eventType := range types {
+		n.Issue.Timeline = append(n.Issue.Timeline, IssueEvent{Type: eventType, At: time.Date(2025, 1, 1+i, 0, 0, 0, 0, time.UTC)})
+	}
+	return n
+}
+
+// closedAs gives a key result opened on January 1st and closed the next day for reason
+func closedAs(reason string) IssueWithUpdates {
+	n := withTimeline("closed", EventOpened, EventClosed)
+	n.Issue.Timeline[1].StateReason = reason
+	return n
+}
+
+func TestIssueTimeline(t *testing.T) {
+	tests := []struct {
+		name      string
+		kr        IssueWithUpdates
+		completed string
+		cycleTime time.Duration
+		reopened  int
+	}{
+		{"closed once", withTimeline("closed", EventOpened, EventLabeled, EventClosed), "2025-01-03", 48 * time.Hour, 0},
+		{"last close counts", withTimeline("closed", EventOpened, EventClosed, EventReopened, EventClosed), "2025-01-04", 72 * time.Hour, 1},
+		{"reopened and still open", withTimeline("open", EventOpened, EventClosed, EventReopened), "", 0, 1},
+		{"closed without a timeline", node(IssueTypeKeyResult, "closed"), "", 0, 0},
+		{"closed without an opened event", withTimeline("closed", EventClosed), "2025-01-01", 0, 0},
+		{"closed as completed", closedAs(CloseReasonCompleted), "2025-01-02", 24 * time.Hour, 0},
+		{"closed as not planned", closedAs("not_planned"), "", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completed, ok := tt.kr.Issue.CompletedAt()
+			if got := completed.Format("2006-01-02"); ok != (tt.completed != "") || (ok && got != tt.completed) {
+				t.Errorf("CompletedAt() = %s, %v; want %q", got, ok, tt.completed)
+			}
+			if got, _ := tt.kr.Issue.CycleTime(); got != tt.cycleTime {
+				t.Errorf("CycleTime() = %s, want %s", got, tt.cycleTime)
+			}
+			if got := tt.kr.Issue.ReopenCount(); got != tt.reopened {
+				t.Errorf("ReopenCount() = %d, want %d", got, tt.reopened)
+			}
+		})
+	}
+}
+
+func TestAverageCycleTime(t *testing.T) {
+	fast := withTimeline("closed", EventOpened, EventClosed)
+	slow := withTimeline("closed", EventOpened, EventLabeled, EventLabeled, EventClosed)
+	open := withTimeline("open", EventOpened)
+	dropped := closedAs("not_planned")
+
+	average, count := AverageCycleTime([]*IssueWithUpdates{&fast, &open, &slow, &dropped})
+	if average != 48*time.Hour || count != 2 {
+		t.Errorf("AverageCycleTime() = %s over %d, want 48h0m0s over 2", average, count)
+	}
+	if average, count := AverageCycleTime([]*IssueWithUpdates{&open}); average != 0 || count != 0 {
+		t.Errorf("AverageCycleTime() without completed key results = %s over %d, want 0 over 0", average, count)
+	}
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultMaxConcurrency is used when performance.max_concurrency is not configured
+const defaultMaxConcurrency = 10
+
+// runConcurrently calls work for every index in [0, count) on at most limit goroutines.
+// Each call should only write to its own index of a result slice, which keeps results
+// in input order no matter which call finishes first. Work that has not started when
+// ctx is cancelled is skipped.
+func runConcurrently(ctx context.Context, limit, count int, work func(i int)) {
+	if limit <= 0 {
+		limit = 1
+	}
+	if limit > count {
+		limit = count
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < limit; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				work(i)
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
@@ -48,6 +48,15 @@ func (s *ConfigService) ValidateConfig(config *entity.Config) error {
 	if config.GitHub.ProjectURL == "" && config.GitHub.Owner == "" {
 		return fmt.Errorf("either github.project_url or github.owner is required")
 	}
+	if config.Output.GroupBy != "" && config.Output.GroupBy != entity.GroupByOwner {
+		return fmt.Errorf("output.group_by must be %q, got %q", entity.GroupByOwner, config.Output.GroupBy)
+	}
+	if err := config.Hierarchy.Validate(); err != nil {
+		return err
+	}
+	if schema := config.Output.JSONSchema; schema != 0 && schema != entity.JSONSchemaObjectives && schema != entity.JSONSchemaWithProject {
+		return fmt.Errorf("output.json_schema must be %d or %d, got %d", entity.JSONSchemaObjectives, entity.JSONSchemaWithProject, schema)
+	}
 	
 	// Additional validation can be added here
 	return nil
@@ -2,11 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 
 	"github-okr-fetcher/internal/domain/entity"
@@ -16,8 +16,12 @@ import (
 // OKRService implements the main business logic for OKR operations
 type OKRService struct {
 	githubRepo ports.GitHubRepository
+	config     *entity.Config
 }
 
+// maxHierarchyDepth bounds how far parents are followed above the fetched issues
+const maxHierarchyDepth = 10
+
 // NewOKRService creates a new OKR service
 func NewOKRService(githubRepo ports.GitHubRepository) *OKRService {
 	return &OKRService{
@@ -25,14 +29,32 @@ func NewOKRService(githubRepo ports.GitHubRepository) *OKRService {
 	}
 }
 
+// NewOKRServiceWithConfig creates a new OKR service with configuration
+func NewOKRServiceWithConfig(githubRepo ports.GitHubRepository, config *entity.Config) *OKRService {
+	return &OKRService{
+		githubRepo: githubRepo,
+		config:     config,
+	}
+}
+
 // FetchOKRData retrieves and processes OKR data from GitHub
 func (s *OKRService) FetchOKRData(ctx context.Context, config *entity.Config) ([]*entity.IssueWithUpdates, *entity.ProjectInfo, error) {
+	// Issues are classified by depth, which needs a known key result level
+	if err := s.hierarchy().Validate(); err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
 	// Parse project URL
 	projectInfo, err := s.githubRepo.ParseProjectURL(config.GitHub.ProjectURL)
 	if err != nil {
 		return nil, nil, fmt.Errorf("error parsing project URL: %w", err)
 	}
 
+	// Check access up front: a token without the needed scopes otherwise yields an empty report
+	if err := s.checkAccess(ctx, projectInfo); err != nil {
+		return nil, nil, err
+	}
+
 	// Fetch issues
 	var issues []*entity.Issue
 	if config.ShouldUseSearch() {
@@ -46,7 +68,10 @@ func (s *OKRService) FetchOKRData(ctx context.Context, config *entity.Config) ([
 			repo = "microservices" // Default
 		}
 
-		issues, err = s.githubRepo.FetchIssuesBySearch(ctx, owner, repo, searchQuery)
+		issues, err = s.searchIssues(ctx, projectInfo, config.GetSearchScopes(owner, repo), searchQuery)
+		if ctx.Err() != nil && len(issues) == 0 {
+			return nil, nil, fmt.Errorf("error searching issues: %w", ctx.Err())
+		}
 		if err != nil || len(issues) == 0 {
 			// Fallback to project-based query
 			issues, err = s.githubRepo.FetchProjectIssues(ctx, projectInfo)
@@ -61,15 +86,41 @@ func (s *OKRService) FetchOKRData(ctx context.Context, config *entity.Config) ([
 		}
 	}
 
+	// Let the configured project field decide KR status ahead of comment heuristics
+	s.applyProjectStatus(issues, config)
+
 	// Process issues
 	objectives, err := s.ProcessOKRIssues(ctx, issues, config.GetLabels())
 	if err != nil {
 		return nil, nil, fmt.Errorf("error processing issues: %w", err)
 	}
 
+	// Whatever was fetched before cancellation still makes a report, flagged as partial
+	if ctx.Err() != nil {
+		reason := "the run was interrupted"
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			reason = "the run timed out"
+		}
+		log.Printf("🛑 Stopped early (%v); building a partial report", ctx.Err())
+		projectInfo.MarkIncomplete(reason)
+	}
+
 	return objectives, projectInfo, nil
 }
 
+// checkAccess verifies the token's scopes and access to the project's organization. A check
+// passed within the cache TTL is not repeated, so runs served from the cache make no API calls.
+func (s *OKRService) checkAccess(ctx context.Context, projectInfo *entity.ProjectInfo) error {
+	org := ""
+	if projectInfo.IsOrganizationProject() {
+		org = projectInfo.Owner
+	}
+	if err := s.githubRepo.CheckAccess(ctx, org); err != nil {
+		return fmt.Errorf("error checking GitHub access: %w", err)
+	}
+	return nil
+}
+
 // ProcessOKRIssues processes a list of issues and organizes them into objectives and key results
 func (s *OKRService) ProcessOKRIssues(ctx context.Context, issues []*entity.Issue, requiredLabels []string) ([]*entity.IssueWithUpdates, error) {
 	log.Printf("🔄 Processing %d issues with %d required labels", len(issues), len(requiredLabels))
@@ -88,9 +139,12 @@ func (s *OKRService) ProcessOKRIssues(ctx context.Context, issues []*entity.Issu
 	if err != nil {
 		log.Printf("⚠️  Error building parent-child relationships: %v", err)
 		// Continue without relationships
-		parentChildMap = make(map[int][]*entity.Issue)
+		parentChildMap = make(map[entity.IssueRef][]*entity.Issue)
 	}
 
+	// Sub-issue parents outside the fetched set (e.g. in another repository) still head their subtree
+	filteredIssues = s.includeMissingParents(ctx, filteredIssues, parentChildMap)
+
 	// Identify objectives (issues without parents) and key results (issues with parents)
 	parentIssues, err := s.IdentifyObjectivesAndKeyResults(filteredIssues, parentChildMap)
 	if err != nil {
@@ -106,13 +160,19 @@ func (s *OKRService) ProcessOKRIssues(ctx context.Context, issues []*entity.Issu
 		}
 	}
 
-	// Process each objective with its key results
+	// Fetch the comments and timeline of every issue in the trees up front, in parallel
+	treeIssues := s.collectTreeIssues(parentIssues, parentChildMap)
+	updates := s.fetchUpdates(ctx, treeIssues)
+	if s.config == nil || !s.config.GitHub.SkipTimelines {
+		s.fetchTimelines(ctx, treeIssues)
+	}
+
+	// Build the tree below each objective, however deep it goes
 	var objectives []*entity.IssueWithUpdates
 	for _, objective := range parentIssues {
-		children := parentChildMap[objective.Number]
-		objectiveWithUpdates, err := s.processObjectiveWithChildren(ctx, objective, children)
+		objectiveWithUpdates, err := s.buildIssueTree(ctx, objective, 0, parentChildMap, updates, make(map[entity.IssueRef]bool))
 		if err != nil {
-			log.Printf("⚠️  Error processing objective #%d: %v", objective.Number, err)
+			log.Printf("⚠️  Error processing objective %s: %v", objective.Ref(), err)
 			continue
 		}
 		objectives = append(objectives, objectiveWithUpdates)
@@ -123,28 +183,181 @@ func (s *OKRService) ProcessOKRIssues(ctx context.Context, issues []*entity.Issu
 	return objectives, nil
 }
 
+// searchIssues runs the query in every scope and merges the results, dropping duplicates
+// found by overlapping scopes. Each issue keeps the owner/repo it really lives in.
+// Scopes whose results were cut short are recorded as warnings on the project info.
+// MaxIssuesLimit caps the merged issues of all scopes together, not each scope.
+func (s *OKRService) searchIssues(ctx context.Context, projectInfo *entity.ProjectInfo, scopes []string, query string) ([]*entity.Issue, error) {
+	var merged []*entity.Issue
+	seen := make(map[entity.IssueRef]bool)
+
+	limit := 0
+	if s.config != nil {
+		limit = s.config.GitHub.MaxIssuesLimit
+	}
+
+	for i, scope := range scopes {
+		if limit > 0 && len(merged) >= limit {
+			log.Printf("⚠️  Limiting results to %d issues; not searching %d more scopes", limit, len(scopes)-i)
+			projectInfo.AddWarning("Search stopped at %d issues (max_issues_limit); not searched: %s",
+				limit, strings.Join(scopes[i:], ", "))
+			break
+		}
+
+		result, err := s.githubRepo.FetchIssuesBySearch(ctx, scope, query)
+		if err != nil && ctx.Err() != nil {
+			break // Interrupted: keep the scopes already searched
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error searching %s: %w", scope, err)
+		}
+
+		if result.Truncated {
+			projectInfo.AddWarning("Search in %s matched %d issues but only %d were collected; the report may be incomplete",
+				scope, result.TotalCount, len(result.Issues))
+		}
+
+		for _, issue := range result.Issues {
+			key := issue.Ref().Key()
+			if seen[key] {
+				continue
+			}
+			if limit > 0 && len(merged) >= limit {
+				projectInfo.AddWarning("Search in %s was cut off at %d issues across all scopes (max_issues_limit)", scope, limit)
+				break
+			}
+			seen[key] = true
+			merged = append(merged, issue)
+		}
+	}
+
+	if len(scopes) > 1 {
+		log.Printf("🔀 Merged %d unique issues from %d search scopes", len(merged), len(scopes))
+	}
+	return merged, nil
+}
+
 // countTotalKeyResults counts the total number of key results across all objectives
 func (s *OKRService) countTotalKeyResults(objectives []*entity.IssueWithUpdates) int {
-	total := 0
-	for _, obj := range objectives {
-		total += len(obj.ChildIssues)
+	return len(entity.CollectKeyResults(objectives))
+}
+
+// hierarchy returns the configured OKR levels
+func (s *OKRService) hierarchy() entity.HierarchyConfig {
+	if s.config == nil {
+		return entity.HierarchyConfig{}
+	}
+	return s.config.Hierarchy
+}
+
+// maxConcurrency returns how many requests may run in parallel
+func (s *OKRService) maxConcurrency() int {
+	if s.config != nil && s.config.Performance.MaxConcurrency > 0 {
+		return s.config.Performance.MaxConcurrency
 	}
-	return total
+	return defaultMaxConcurrency
 }
 
-// processIssueWithUpdates processes a single issue and fetches its updates
-func (s *OKRService) processIssueWithUpdates(ctx context.Context, issue *entity.Issue) (*entity.IssueWithUpdates, error) {
-	// Extract owner and repo from issue URL
-	owner, repo := s.githubRepo.ExtractOwnerRepoFromIssue(issue)
-	if owner == "" || repo == "" {
+// collectTreeIssues returns every issue in the trees below roots, each once, in tree order
+func (s *OKRService) collectTreeIssues(roots []*entity.Issue, parentChildMap map[entity.IssueRef][]*entity.Issue) []*entity.Issue {
+	var issues []*entity.Issue
+	seen := make(map[entity.IssueRef]bool)
+
+	var walk func(issue *entity.Issue)
+	walk = func(issue *entity.Issue) {
+		key := issue.Ref().Key()
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		issues = append(issues, issue)
+		for _, child := range parentChildMap[key] {
+			walk(child)
+		}
+	}
+	for _, root := range roots {
+		walk(root)
+	}
+
+	return issues
+}
+
+// fetchUpdates fetches the weekly updates of the issues with up to max_concurrency requests in flight
+func (s *OKRService) fetchUpdates(ctx context.Context, issues []*entity.Issue) map[entity.IssueRef][]*entity.WeeklyUpdate {
+	results := make([][]*entity.WeeklyUpdate, len(issues))
+	runConcurrently(ctx, s.maxConcurrency(), len(issues), func(i int) {
+		ref := issues[i].Ref()
+		if ref.Owner == "" || ref.Repo == "" {
+			return
+		}
+
+		updates, err := s.githubRepo.FetchIssueComments(ctx, ref)
+		if err != nil {
+			log.Printf("⚠️  Error fetching updates for issue %s: %v", ref, err)
+			updates = []*entity.WeeklyUpdate{} // Continue with empty updates
+		}
+		results[i] = updates
+	})
+
+	updates := make(map[entity.IssueRef][]*entity.WeeklyUpdate, len(issues))
+	for i, issue := range issues {
+		if results[i] != nil {
+			updates[issue.Ref().Key()] = results[i]
+		}
+	}
+	log.Printf("📝 Fetched updates for %d issues with up to %d parallel requests", len(updates), s.maxConcurrency())
+	return updates
+}
+
+// fetchTimelines stores the history and linked pull requests of each issue on it, with up to max_concurrency requests in flight.
+// Issues whose timeline cannot be fetched are reported without completion dates.
+func (s *OKRService) fetchTimelines(ctx context.Context, issues []*entity.Issue) {
+	fetched := make([]bool, len(issues))
+	runConcurrently(ctx, s.maxConcurrency(), len(issues), func(i int) {
+		ref := issues[i].Ref()
+		if ref.Owner == "" || ref.Repo == "" {
+			return
+		}
+
+		timeline, err := s.githubRepo.FetchIssueTimeline(ctx, ref)
+		if err != nil {
+			log.Printf("⚠️  Error fetching timeline for issue %s: %v", ref, err)
+			return
+		}
+		issues[i].Timeline = timeline.Events
+		issues[i].PullRequests = timeline.PullRequests
+		fetched[i] = true
+	})
+
+	count := 0
+	for _, ok := range fetched {
+		if ok {
+			count++
+		}
+	}
+	log.Printf("🕒 Fetched timelines for %d issues", count)
+}
+
+// processIssueWithUpdates processes a single issue together with its updates.
+// Updates not fetched ahead of time are fetched here.
+func (s *OKRService) processIssueWithUpdates(ctx context.Context, issue *entity.Issue, prefetched map[entity.IssueRef][]*entity.WeeklyUpdate) (*entity.IssueWithUpdates, error) {
+	// Resolve the fully qualified reference from the issue URL
+	ref := issue.Ref()
+	if ref.Owner == "" || ref.Repo == "" {
 		return nil, fmt.Errorf("could not extract owner/repo from issue #%d", issue.Number)
 	}
 
 	// Fetch updates for this issue
-	updates, err := s.githubRepo.FetchIssueComments(ctx, owner, repo, issue.Number)
-	if err != nil {
-		log.Printf("⚠️  Error fetching updates for issue #%d: %v", issue.Number, err)
-		updates = []*entity.WeeklyUpdate{} // Continue with empty updates
+	updates, found := prefetched[ref.Key()]
+	if !found && ctx.Err() != nil {
+		updates = []*entity.WeeklyUpdate{} // Cancelled before this issue was reached
+	} else if !found {
+		var err error
+		updates, err = s.githubRepo.FetchIssueComments(ctx, ref)
+		if err != nil {
+			log.Printf("⚠️  Error fetching updates for issue %s: %v", ref, err)
+			updates = []*entity.WeeklyUpdate{} // Continue with empty updates
+		}
 	}
 
 	// Convert to the format expected by IssueWithUpdates
@@ -163,30 +376,46 @@ func (s *OKRService) processIssueWithUpdates(ctx context.Context, issue *entity.
 		Issue:        *issue,
 		LatestUpdate: latestUpdate,
 		AllUpdates:   allUpdates,
-		ChildIssues:  []entity.IssueWithUpdates{}, // No child issues for now
+		ChildIssues:  []entity.IssueWithUpdates{}, // Filled in by buildIssueTree
 	}, nil
 }
 
 // BuildParentChildRelationships analyzes issues to build parent-child relationships
-func (s *OKRService) BuildParentChildRelationships(ctx context.Context, issues []*entity.Issue) (map[int][]*entity.Issue, error) {
-	parentChildMap := make(map[int][]*entity.Issue)
+// Parents are keyed by their canonical IssueRef so equal numbers in different repositories never collide.
+func (s *OKRService) BuildParentChildRelationships(ctx context.Context, issues []*entity.Issue) (map[entity.IssueRef][]*entity.Issue, error) {
+	parentChildMap := make(map[entity.IssueRef][]*entity.Issue)
+
+	// GitHub's native sub-issue relationship takes precedence over text references.
+	// Parents are looked up in parallel and applied in input order afterwards.
+	parents := make([]*entity.Issue, len(issues))
+	runConcurrently(ctx, s.maxConcurrency(), len(issues), func(i int) {
+		ref := issues[i].Ref()
+		if issues[i].Parent != nil || ref.Owner == "" || ref.Repo == "" {
+			return
+		}
+		parent, err := s.githubRepo.FindParentIssue(ctx, ref)
+		if err != nil {
+			log.Printf("⚠️  Could not look up sub-issue parent of %s: %v", ref, err)
+		}
+		parents[i] = parent
+	})
 
-	for _, issue := range issues {
-		parentNum := s.extractParentIssueNumber(issue)
-
-		// If no parent found in body, try to find relationships via GitHub API
-		if parentNum == 0 {
-			owner, repo := s.githubRepo.ExtractOwnerRepoFromIssue(issue)
-			if owner != "" && repo != "" {
-				apiParentNum, err := s.githubRepo.FindParentIssue(ctx, owner, repo, issue.Number)
-				if err == nil && apiParentNum > 0 {
-					parentNum = apiParentNum
-				}
-			}
+	for i, issue := range issues {
+		if issue.Parent == nil {
+			issue.Parent = parents[i]
+		}
+
+		var parentRef entity.IssueRef
+		if issue.Parent != nil {
+			parentRef = issue.Parent.Ref()
+		} else {
+			// Fall back to "Parent Issue: #N" style references in the title or body
+			parentRef = s.extractParentIssueRef(issue)
 		}
 
-		if parentNum > 0 {
-			parentChildMap[parentNum] = append(parentChildMap[parentNum], issue)
+		if !parentRef.IsZero() {
+			key := parentRef.Key()
+			parentChildMap[key] = append(parentChildMap[key], issue)
 		}
 	}
 
@@ -194,12 +423,12 @@ func (s *OKRService) BuildParentChildRelationships(ctx context.Context, issues [
 }
 
 // IdentifyObjectivesAndKeyResults identifies which issues are objectives vs key results
-func (s *OKRService) IdentifyObjectivesAndKeyResults(issues []*entity.Issue, parentChildMap map[int][]*entity.Issue) ([]*entity.Issue, error) {
+func (s *OKRService) IdentifyObjectivesAndKeyResults(issues []*entity.Issue, parentChildMap map[entity.IssueRef][]*entity.Issue) ([]*entity.Issue, error) {
 	var parentIssues []*entity.Issue
 
 	for _, issue := range issues {
 		// Check if this issue has children but no parent
-		hasChildren := len(parentChildMap[issue.Number]) > 0
+		hasChildren := len(parentChildMap[issue.Ref().Key()]) > 0
 		hasParent := s.hasParentIssue(issue, parentChildMap)
 
 		if hasChildren && !hasParent {
@@ -288,6 +517,22 @@ func (s *OKRService) DetectStatusFromContent(content string) entity.WeeklyUpdate
 
 // Helper methods
 
+// applyProjectStatus records the status each issue carries in the configured project status field
+func (s *OKRService) applyProjectStatus(issues []*entity.Issue, config *entity.Config) {
+	if config.ProjectFields.StatusField == "" {
+		return
+	}
+
+	resolved := 0
+	for _, issue := range issues {
+		issue.ProjectStatus = config.ResolveProjectStatus(issue)
+		if issue.ProjectStatus != entity.StatusUnknown {
+			resolved++
+		}
+	}
+	log.Printf("🏷️  %d/%d issues have a status in project field %q", resolved, len(issues), config.ProjectFields.StatusField)
+}
+
 func (s *OKRService) filterIssuesByLabels(issues []*entity.Issue, requiredLabels []string) []*entity.Issue {
 	if len(requiredLabels) == 0 {
 		return issues
@@ -303,105 +548,123 @@ func (s *OKRService) filterIssuesByLabels(issues []*entity.Issue, requiredLabels
 	return filtered
 }
 
-func (s *OKRService) extractParentIssueNumber(issue *entity.Issue) int {
+// parentRefPattern matches "#N", "owner/repo#N" and issue URLs on any GitHub host
+const parentRefPattern = `(https?://[^\s/]+/[\w.-]+/[\w.-]+/issues/\d+|[\w.-]+/[\w.-]+#\d+|#\d+)`
+
+func (s *OKRService) extractParentIssueRef(issue *entity.Issue) entity.IssueRef {
 	// Check both title and body for parent references
 	textToSearch := issue.Title + "\n" + issue.Body
 
 	// Patterns to look for parent issue references
 	bodyPatterns := []string{
-		`(?i)parent\s*(?:issue)?\s*:?\s*#(\d+)`,
-		`(?i)parent\s*(?:issue)?\s*:?\s*https://github\.com/[^/]+/[^/]+/issues/(\d+)`,
-		`(?i)part\s*of\s*#(\d+)`,
-		`(?i)child\s*of\s*#(\d+)`,
-		`(?i)subtask\s*of\s*#(\d+)`,
-		`(?i)depends\s*on\s*#(\d+)`,
-		`(?i)relates\s*to\s*#(\d+)`,
-		`(?i)blocking\s*#(\d+)`,
-		`(?i)blocked\s*by\s*#(\d+)`,
+		`(?i)parent\s*(?:issue)?\s*:?\s*` + parentRefPattern,
+		`(?i)part\s*of\s*` + parentRefPattern,
+		`(?i)child\s*of\s*` + parentRefPattern,
+		`(?i)subtask\s*of\s*` + parentRefPattern,
+		`(?i)depends\s*on\s*` + parentRefPattern,
+		`(?i)relates\s*to\s*` + parentRefPattern,
+		`(?i)blocking\s*` + parentRefPattern,
+		`(?i)blocked\s*by\s*` + parentRefPattern,
 	}
 
 	for _, pattern := range bodyPatterns {
 		regex := regexp.MustCompile(pattern)
 		matches := regex.FindStringSubmatch(textToSearch)
 		if len(matches) >= 2 {
-			if parentNum, err := strconv.Atoi(matches[1]); err == nil {
-				log.Printf("📎 Found parent reference in issue #%d: parent is #%d", issue.Number, parentNum)
-				return parentNum
+			// Short references like "#12" point into the issue's own repository
+			if parentRef, err := entity.ParseIssueRef(matches[1], issue.Ref()); err == nil {
+				log.Printf("📎 Found parent reference in issue %s: parent is %s", issue.Ref(), parentRef)
+				return parentRef
 			}
 		}
 	}
 
-	return 0
+	return entity.IssueRef{}
 }
 
-func (s *OKRService) hasParentIssue(issue *entity.Issue, parentChildMap map[int][]*entity.Issue) bool {
-	parentNum := s.extractParentIssueNumber(issue)
-	return parentNum > 0
+func (s *OKRService) hasParentIssue(issue *entity.Issue, parentChildMap map[entity.IssueRef][]*entity.Issue) bool {
+	if issue.Parent != nil {
+		return true
+	}
+	return !s.extractParentIssueRef(issue).IsZero()
 }
 
-func (s *OKRService) processObjectiveWithChildren(ctx context.Context, objective *entity.Issue, children []*entity.Issue) (*entity.IssueWithUpdates, error) {
-	// Fetch updates for objective
-	owner, repo := s.githubRepo.ExtractOwnerRepoFromIssue(objective)
-	if owner == "" || repo == "" {
-		return nil, fmt.Errorf("could not extract owner/repo from issue #%d", objective.Number)
+// includeMissingParents adds sub-issue parents that are not part of the fetched issues,
+// following them upwards so every level above the fetched issues is present
+func (s *OKRService) includeMissingParents(ctx context.Context, issues []*entity.Issue, parentChildMap map[entity.IssueRef][]*entity.Issue) []*entity.Issue {
+	seen := make(map[entity.IssueRef]bool)
+	for _, issue := range issues {
+		seen[issue.Ref().Key()] = true
 	}
 
-	updates, err := s.githubRepo.FetchIssueComments(ctx, owner, repo, objective.Number)
-	if err != nil {
-		log.Printf("Warning: Could not fetch comments for issue #%d: %v", objective.Number, err)
-	}
+	result := issues
+	pending := issues
+	for level := 0; level < maxHierarchyDepth && len(pending) > 0; level++ {
+		var added []*entity.Issue
+		for _, issue := range pending {
+			parent := issue.Parent
+			if parent == nil || seen[parent.Ref().Key()] {
+				continue
+			}
+			seen[parent.Ref().Key()] = true
+			log.Printf("📎 Added sub-issue parent %s for issue %s", parent.Ref(), issue.Ref())
+			added = append(added, parent)
+		}
+		if len(added) == 0 {
+			break
+		}
 
-	var latestUpdate *entity.WeeklyUpdate
-	if len(updates) > 0 {
-		latestUpdate = updates[0]
-	}
+		// Resolve the parents of the newly added issues as well
+		addedMap, err := s.BuildParentChildRelationships(ctx, added)
+		if err != nil {
+			log.Printf("⚠️  Error building parent-child relationships: %v", err)
+		}
+		for parentRef, children := range addedMap {
+			parentChildMap[parentRef] = append(parentChildMap[parentRef], children...)
+		}
 
-	// Convert slice to match entity structure
-	var allUpdates []entity.WeeklyUpdate
-	for _, update := range updates {
-		allUpdates = append(allUpdates, *update)
+		result = append(result, added...)
+		pending = added
 	}
+	return result
+}
 
-	objectiveWithUpdates := &entity.IssueWithUpdates{
-		Issue:        *objective,
-		LatestUpdate: latestUpdate,
-		AllUpdates:   allUpdates,
-	}
+// buildIssueTree fetches the updates of an issue and recursively attaches its descendants,
+// naming and classifying each issue by its depth. The path set guards against cycles.
+func (s *OKRService) buildIssueTree(ctx context.Context, issue *entity.Issue, depth int, parentChildMap map[entity.IssueRef][]*entity.Issue, updates map[entity.IssueRef][]*entity.WeeklyUpdate, path map[entity.IssueRef]bool) (*entity.IssueWithUpdates, error) {
+	key := issue.Ref().Key()
+	path[key] = true
+	defer delete(path, key)
 
-	// Process children (key results)
-	for _, child := range children {
-		child.Type = entity.IssueTypeKeyResult
+	hierarchy := s.hierarchy()
+	issue.Depth = depth
+	issue.Level = hierarchy.LevelName(depth)
+	issue.Type = hierarchy.TypeForDepth(depth)
 
-		childOwner, childRepo := s.githubRepo.ExtractOwnerRepoFromIssue(child)
-		if childOwner == "" || childRepo == "" {
-			log.Printf("Warning: Could not extract owner/repo from child issue #%d", child.Number)
+	node, err := s.processIssueWithUpdates(ctx, issue, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, child := range parentChildMap[key] {
+		if path[child.Ref().Key()] {
+			log.Printf("⚠️  Skipping %s below %s: parent-child cycle", child.Ref(), issue.Ref())
 			continue
 		}
 
-		childUpdates, err := s.githubRepo.FetchIssueComments(ctx, childOwner, childRepo, child.Number)
+		childNode, err := s.buildIssueTree(ctx, child, depth+1, parentChildMap, updates, path)
 		if err != nil {
-			log.Printf("Warning: Could not fetch comments for issue #%d: %v", child.Number, err)
-		}
-
-		var childLatestUpdate *entity.WeeklyUpdate
-		if len(childUpdates) > 0 {
-			childLatestUpdate = childUpdates[0]
-		}
-
-		// Convert child updates slice
-		var childAllUpdates []entity.WeeklyUpdate
-		for _, update := range childUpdates {
-			childAllUpdates = append(childAllUpdates, *update)
-		}
-
-		childWithUpdates := entity.IssueWithUpdates{
-			Issue:        *child,
-			LatestUpdate: childLatestUpdate,
-			AllUpdates:   childAllUpdates,
+			log.Printf("Warning: Could not process child issue %s: %v", child.Ref(), err)
+			continue
 		}
+		node.ChildIssues = append(node.ChildIssues, *childNode)
+	}
 
-		objectiveWithUpdates.ChildIssues = append(objectiveWithUpdates.ChildIssues, childWithUpdates)
+	// Pull requests are only known for issues whose timeline was fetched. They are summarized
+	// once the children are in place, since a key result's status may roll up from them.
+	if len(node.Issue.Timeline) > 0 {
+		node.PullRequestEvidence = node.SummarizePullRequests(s.config.Now())
 	}
 
-	return objectiveWithUpdates, nil
+	return node, nil
 }
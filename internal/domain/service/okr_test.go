@@ -0,0 +1,518 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github-okr-fetcher/internal/adapters/github"
+	"github-okr-fetcher/internal/adapters/github/githubtest"
+	"github-okr-fetcher/internal/adapters/output"
+	"github-okr-fetcher/internal/domain/entity"
+	"github-okr-fetcher/internal/ports"
+)
+
+// newTestService creates an OKR service backed by the fake server, with the project URL and
+// required labels already configured
+func newTestService(t *testing.T, server *githubtest.Server, configure func(*entity.Config)) (*OKRService, *entity.Config) {
+	t.Helper()
+	config := &entity.Config{}
+	config.GitHub.ProjectURL = server.ProjectURL("acme", "", 1, 0)
+	config.Labels.Required = []string{"okr"}
+	server.Configure(config)
+	if configure != nil {
+		configure(config)
+	}
+
+	repo, err := github.NewRepository("test-token", config)
+	if err != nil {
+		t.Fatalf("NewRepository: %v", err)
+	}
+	return NewOKRServiceWithConfig(repo, config), config
+}
+
+// describeTree renders a tree as "ref type status" lines indented by depth
+func describeTree(objectives []*entity.IssueWithUpdates) string {
+	var sb strings.Builder
+	var walk func(node *entity.IssueWithUpdates, depth int)
+	walk = func(node *entity.IssueWithUpdates, depth int) {
+		status := node.GetRolledUpStatus()
+		if node.Issue.IsObjective() {
+			status = node.GetObjectiveStatus()
+		}
+		fmt.Fprintf(&sb, "%s%s %s %s\n", strings.Repeat("  ", depth), node.Issue.Ref(), node.Issue.Type, status)
+		for i := range node.ChildIssues {
+			walk(&node.ChildIssues[i], depth+1)
+		}
+	}
+	for _, objective := range objectives {
+		walk(objective, 0)
+	}
+	return sb.String()
+}
+
+// okrBoard adds two objectives with key results linked as sub-issues, through a
+// "Parent Issue:" reference and across repositories, plus an issue without the OKR label
+func okrBoard(server *githubtest.Server) {
+	server.AddIssue(
+		githubtest.Issue{Ref: "acme/okrs#1", Title: "Faster checkout", Labels: []string{"okr"}},
+		githubtest.Issue{Ref: "acme/okrs#2", Title: "p95 latency below 300ms", Labels: []string{"okr"}, Parent: "acme/okrs#1", State: "closed",
+			CreatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+			Events:    []githubtest.Event{{Type: entity.EventClosed, At: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), StateReason: "COMPLETED"}}},
+		githubtest.Issue{Ref: "acme/okrs#3", Title: "Zero downtime deploys", Labels: []string{"okr"}, Body: "Parent Issue: #1",
+			Comments: []githubtest.Comment{
+				{Author: "alice", Body: "# Weekly update 2025-01-06\n🟢 On track"},
+				{Author: "alice", Body: "# Weekly update 2025-01-13\n🟡 Caution: the load balancer change slipped"},
+			}},
+		githubtest.Issue{Ref: "acme/api#5", Title: "API error budget", Labels: []string{"okr"}, Parent: "acme/okrs#1",
+			Comments: []githubtest.Comment{{Author: "bob", Body: "# Weekly update 2025-01-13\n🟢 On track"}}},
+		githubtest.Issue{Ref: "acme/okrs#10", Title: "Reliable payments", Labels: []string{"okr"}},
+		githubtest.Issue{Ref: "acme/okrs#11", Title: "Payment retries", Labels: []string{"okr"}, Parent: "acme/okrs#10",
+			Comments: []githubtest.Comment{{Author: "carol", Body: "# Weekly update 2025-01-13\nBlocked on the PSP contract"}}},
+		githubtest.Issue{Ref: "acme/okrs#12", Title: "Retry queue", Labels: []string{"okr"}, Parent: "acme/okrs#11"},
+		githubtest.Issue{Ref: "acme/okrs#20", Title: "Unrelated bug", Parent: "acme/okrs#10"},
+	)
+
+	project := githubtest.Project{Owner: "acme", Number: 1, Title: "OKRs"}
+	for _, ref := range []string{"acme/okrs#1", "acme/okrs#2", "acme/okrs#3", "acme/api#5", "acme/okrs#10", "acme/okrs#11", "acme/okrs#12", "acme/okrs#20"} {
+		project.Items = append(project.Items, githubtest.Item{Issue: ref})
+	}
+	server.AddProject(project)
+}
+
+func TestFetchOKRDataBuildsHierarchy(t *testing.T) {
+	server := githubtest.NewServer(t)
+	okrBoard(server)
+	okrService, config := newTestService(t, server, nil)
+
+	objectives, projectInfo, err := okrService.FetchOKRData(context.Background(), config)
+	if err != nil {
+		t.Fatalf("FetchOKRData: %v", err)
+	}
+	if projectInfo.Owner != "acme" || projectInfo.ProjectID != 1 || projectInfo.Incomplete {
+		t.Errorf("project info = %+v", projectInfo)
+	}
+
+	want := `acme/okrs#1 objective caution
+  acme/okrs#2 kr completed
+  acme/okrs#3 kr caution
+  acme/api#5 kr on-track
+acme/okrs#10 objective blocked
+  acme/okrs#11 kr blocked
+    acme/okrs#12 initiative unknown
+`
+	if got := describeTree(objectives); got != want {
+		t.Errorf("tree:\n%s\nwant:\n%s", got, want)
+	}
+
+	kr := objectives[0].ChildIssues[1]
+	if kr.LatestUpdate == nil || kr.LatestUpdate.Date != "2025-01-13" || len(kr.AllUpdates) != 2 {
+		t.Errorf("updates of %s = latest %+v, %d in total; want the 2025-01-13 update of 2", kr.Issue.Ref(), kr.LatestUpdate, len(kr.AllUpdates))
+	}
+	if kr.Issue.Level != "Key Result" || kr.Issue.Depth != 1 {
+		t.Errorf("level = %q at depth %d, want Key Result at depth 1", kr.Issue.Level, kr.Issue.Depth)
+	}
+	if progress := objectives[0].GetProgress(); fmt.Sprintf("%.2f", progress) != "0.33" {
+		t.Errorf("progress = %.2f, want 0.33", progress)
+	}
+
+	// Every issue in the tree has its timeline, so completed key results have a cycle time
+	if n := server.CountRequests("POST graphql timeline"); n != 7 {
+		t.Errorf("timeline lookups = %d, want 7", n)
+	}
+	if cycleTime, ok := objectives[0].ChildIssues[0].Issue.CycleTime(); !ok || cycleTime != 18*24*time.Hour {
+		t.Errorf("cycle time of acme/okrs#2 = %s, %v; want 432h0m0s", cycleTime, ok)
+	}
+
+	// Board items carry their parents, so no sub-issue lookups are needed
+	if n := server.CountRequests("POST graphql parent"); n != 0 {
+		t.Errorf("parent lookups = %d, want 0", n)
+	}
+}
+
+func TestFetchOKRDataChecksAccessUpFront(t *testing.T) {
+	// A token missing read:org would see an empty board; the check fails before any fetching
+	empty := githubtest.NewServer(t)
+	empty.AddProject(githubtest.Project{Owner: "acme", Number: 1, Title: "OKRs"})
+	empty.Scopes = "repo, read:project"
+	okrService, config := newTestService(t, empty, nil)
+
+	_, _, err := okrService.FetchOKRData(context.Background(), config)
+	var scopeErr *github.ScopeError
+	if !errors.As(err, &scopeErr) {
+		t.Fatalf("error = %v, want a ScopeError", err)
+	}
+	if n := empty.CountRequests("POST graphql items"); n != 0 {
+		t.Errorf("item pages fetched with a failing token = %d, want 0", n)
+	}
+
+	// A passed check is cached with the responses, so a re-render makes no API calls
+	server := githubtest.NewServer(t)
+	okrBoard(server)
+	cacheDir := t.TempDir()
+	configure := func(config *entity.Config) {
+		config.Cache.Enabled = true
+		config.Cache.Dir = cacheDir
+	}
+	for run := 1; run <= 2; run++ {
+		okrService, config := newTestService(t, server, configure)
+		if _, _, err := okrService.FetchOKRData(context.Background(), config); err != nil {
+			t.Fatalf("run %d: FetchOKRData: %v", run, err)
+		}
+		if n := server.CountRequests("GET /orgs/acme"); n != 1 {
+			t.Errorf("after run %d: access checks = %d, want 1", run, n)
+		}
+	}
+}
+
+func TestFetchOKRDataSkipsTimelines(t *testing.T) {
+	server := githubtest.NewServer(t)
+	okrBoard(server)
+	okrService, config := newTestService(t, server, func(config *entity.Config) { config.GitHub.SkipTimelines = true })
+
+	objectives, _, err := okrService.FetchOKRData(context.Background(), config)
+	if err != nil {
+		t.Fatalf("FetchOKRData: %v", err)
+	}
+	if n := server.CountRequests("POST graphql timeline"); n != 0 {
+		t.Errorf("timeline lookups = %d, want 0", n)
+	}
+	if _, ok := objectives[0].ChildIssues[0].Issue.CompletedAt(); ok {
+		t.Errorf("acme/okrs#2 has a completion date without a timeline")
+	}
+}
+
+func TestFetchOKRDataSummarizesPullRequests(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	server := githubtest.NewServer(t)
+	server.AddIssue(
+		githubtest.Issue{Ref: "acme/okrs#1", Title: "Faster checkout", Labels: []string{"okr"}},
+		githubtest.Issue{Ref: "acme/okrs#3", Title: "Zero downtime deploys", Labels: []string{"okr"}, Parent: "acme/okrs#1",
+			Comments: []githubtest.Comment{{Author: "alice", Body: "# Weekly update 2025-01-13\n🟡 Caution"}},
+			PullRequests: []githubtest.PullRequest{
+				{Ref: "acme/deploy#8", State: "MERGED", CreatedAt: date(2025, 1, 15), MergedAt: date(2025, 1, 20), Closes: true},
+				{Ref: "acme/deploy#9", CreatedAt: date(2025, 1, 21)},
+			}},
+		githubtest.Issue{Ref: "acme/api#5", Title: "API error budget", Labels: []string{"okr"}, Parent: "acme/okrs#1",
+			Comments:     []githubtest.Comment{{Author: "bob", Body: "# Weekly update 2025-01-13\n🟢 On track"}},
+			PullRequests: []githubtest.PullRequest{{Ref: "acme/api#40", State: "MERGED", CreatedAt: date(2024, 12, 1), MergedAt: date(2024, 12, 20)}}},
+	)
+	project := githubtest.Project{Owner: "acme", Number: 1, Title: "OKRs"}
+	for _, ref := range []string{"acme/okrs#1", "acme/okrs#3", "acme/api#5"} {
+		project.Items = append(project.Items, githubtest.Item{Issue: ref})
+	}
+	server.AddProject(project)
+	okrService, config := newTestService(t, server, func(config *entity.Config) {
+		config.Clock = entity.FixedClock(date(2025, 1, 31))
+	})
+
+	objectives, _, err := okrService.FetchOKRData(context.Background(), config)
+	if err != nil {
+		t.Fatalf("FetchOKRData: %v", err)
+	}
+
+	var got []string
+	for _, kr := range entity.CollectKeyResults(objectives) {
+		evidence := kr.PullRequestEvidence
+		if evidence == nil {
+			t.Fatalf("%s has no pull request evidence", kr.Issue.Ref())
+		}
+		got = append(got, fmt.Sprintf("%s linked=%d since-update=%d flagged=%v",
+			kr.Issue.Ref(), evidence.Linked, evidence.MergedSinceUpdate, evidence.NoRecentMerges))
+	}
+	want := []string{
+		"acme/okrs#3 linked=2 since-update=1 flagged=false",
+		"acme/api#5 linked=1 since-update=0 flagged=true",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("evidence = %q, want %q", got, want)
+	}
+}
+
+func TestFetchOKRDataWithHierarchyLevels(t *testing.T) {
+	server := githubtest.NewServer(t)
+	okrBoard(server)
+	okrService, config := newTestService(t, server, func(config *entity.Config) {
+		config.Hierarchy.Levels = []string{"Objective", "Key Result", "Initiative"}
+	})
+
+	objectives, _, err := okrService.FetchOKRData(context.Background(), config)
+	if err != nil {
+		t.Fatalf("FetchOKRData: %v", err)
+	}
+
+	initiative := objectives[1].ChildIssues[0].ChildIssues[0]
+	if initiative.Issue.Level != "Initiative" || !initiative.Issue.IsInitiative() || initiative.Issue.Depth != 2 {
+		t.Errorf("issue %s = level %q, type %q, depth %d; want an Initiative at depth 2",
+			initiative.Issue.Ref(), initiative.Issue.Level, initiative.Issue.Type, initiative.Issue.Depth)
+	}
+	if n := len(entity.CollectKeyResults(objectives)); n != 4 {
+		t.Errorf("key results = %d, want 4", n)
+	}
+}
+
+func TestFetchOKRDataUsesProjectStatusField(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(
+		githubtest.Issue{Ref: "acme/okrs#1", Title: "Objective", Labels: []string{"okr"}},
+		githubtest.Issue{Ref: "acme/okrs#2", Title: "KR", Labels: []string{"okr"}, Parent: "acme/okrs#1",
+			Comments: []githubtest.Comment{{Author: "alice", Body: "# Weekly update 2025-01-13\n🟢 On track"}}},
+	)
+	server.AddProject(githubtest.Project{Owner: "acme", Number: 1, Items: []githubtest.Item{
+		{Issue: "acme/okrs#1"},
+		{Issue: "acme/okrs#2", Fields: []githubtest.FieldValue{{Name: "Health", Type: entity.FieldTypeSingleSelect, Text: "Needs attention"}}},
+	}})
+	okrService, config := newTestService(t, server, func(config *entity.Config) {
+		config.ProjectFields.StatusField = "Health"
+		config.ProjectFields.StatusMapping = map[string]string{"Needs attention": "at-risk"}
+	})
+
+	objectives, _, err := okrService.FetchOKRData(context.Background(), config)
+	if err != nil {
+		t.Fatalf("FetchOKRData: %v", err)
+	}
+
+	want := "acme/okrs#1 objective at-risk\n  acme/okrs#2 kr at-risk\n"
+	if got := describeTree(objectives); got != want {
+		t.Errorf("tree:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestFetchOKRDataBySearch(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(
+		// The objective lives in another repository and lacks the label, so search cannot find it
+		githubtest.Issue{Ref: "acme/okrs#1", Title: "Faster checkout"},
+		githubtest.Issue{Ref: "acme/api#5", Title: "API error budget", Labels: []string{"okr"}, Parent: "acme/okrs#1"},
+		githubtest.Issue{Ref: "acme/api#6", Title: "Cache hit rate", Labels: []string{"okr"}, Parent: "acme/okrs#1", State: "closed"},
+		githubtest.Issue{Ref: "acme/api#7", Title: "Not an OKR"},
+	)
+	okrService, config := newTestService(t, server, func(config *entity.Config) {
+		config.Filter.UseSearch = true
+		config.Filter.Repositories = []string{"acme/api"}
+	})
+
+	objectives, _, err := okrService.FetchOKRData(context.Background(), config)
+	if err != nil {
+		t.Fatalf("FetchOKRData: %v", err)
+	}
+
+	want := "acme/okrs#1 objective on-track\n  acme/api#5 kr unknown\n  acme/api#6 kr completed\n"
+	if got := describeTree(objectives); got != want {
+		t.Errorf("tree:\n%s\nwant:\n%s", got, want)
+	}
+	if n := server.CountRequests("GET /search/issues"); n != 1 {
+		t.Errorf("searches = %d, want 1", n)
+	}
+	if n := server.CountRequests("POST graphql items"); n != 0 {
+		t.Errorf("board pages = %d, want 0 when search finds issues", n)
+	}
+}
+
+func TestSearchIssuesLimitsAllScopesTogether(t *testing.T) {
+	tests := []struct {
+		limit        int
+		want         int
+		wantSearches int
+		wantWarning  string
+	}{
+		{3, 3, 2, "Search in repo:acme/web was cut off at 3 issues across all scopes"},
+		{2, 2, 1, "Search stopped at 2 issues (max_issues_limit); not searched: repo:acme/web"},
+		{0, 4, 2, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.limit), func(t *testing.T) {
+			server := githubtest.NewServer(t)
+			server.AddIssue(
+				githubtest.Issue{Ref: "acme/api#1", Title: "KR one", Labels: []string{"okr"}},
+				githubtest.Issue{Ref: "acme/api#2", Title: "KR two", Labels: []string{"okr"}},
+				githubtest.Issue{Ref: "acme/web#3", Title: "KR three", Labels: []string{"okr"}},
+				githubtest.Issue{Ref: "acme/web#4", Title: "KR four", Labels: []string{"okr"}},
+			)
+			okrService, config := newTestService(t, server, func(config *entity.Config) {
+				config.GitHub.MaxIssuesLimit = tt.limit
+			})
+			projectInfo := &entity.ProjectInfo{}
+
+			issues, err := okrService.searchIssues(context.Background(), projectInfo, []string{"repo:acme/api", "repo:acme/web"}, config.GetSearchQuery())
+			if err != nil {
+				t.Fatalf("searchIssues: %v", err)
+			}
+			if len(issues) != tt.want {
+				t.Errorf("issues = %d, want %d", len(issues), tt.want)
+			}
+			if n := server.CountRequests("GET /search/issues"); n != tt.wantSearches {
+				t.Errorf("searches = %d, want %d", n, tt.wantSearches)
+			}
+			warnings := strings.Join(projectInfo.Warnings, "\n")
+			if (tt.wantWarning == "") != (warnings == "") || !strings.Contains(warnings, tt.wantWarning) {
+				t.Errorf("warnings = %q, want %q", warnings, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestFetchOKRDataReportsInterruptedSearch(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(
+		githubtest.Issue{Ref: "acme/api#5", Title: "API error budget", Labels: []string{"okr"}},
+		githubtest.Issue{Ref: "acme/api#6", Title: "Cache hit rate", Labels: []string{"okr"}},
+		githubtest.Issue{Ref: "acme/api#7", Title: "Retry budget", Labels: []string{"okr"}},
+	)
+	cacheDir := t.TempDir()
+	configure := func(config *entity.Config) {
+		config.Filter.UseSearch = true
+		config.Filter.Repositories = []string{"acme/api"}
+		// Two results per page leave the third issue for a second page
+		config.GitHub.PageSize = 2
+		config.Cache.Enabled = true
+		config.Cache.Dir = cacheDir
+	}
+
+	// The second page never answers; the run is cancelled while waiting for it, like on Ctrl-C
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stall atomic.Bool
+	stall.Store(true)
+	server.Intercept = func(w http.ResponseWriter, r *http.Request) bool {
+		if !stall.Load() || r.URL.Path != "/api/v3/search/issues" || r.URL.Query().Get("page") != "2" {
+			return false
+		}
+		cancel()
+		<-r.Context().Done()
+		return true
+	}
+
+	okrService, config := newTestService(t, server, configure)
+	objectives, projectInfo, err := okrService.FetchOKRData(ctx, config)
+	if err != nil {
+		t.Fatalf("FetchOKRData: %v", err)
+	}
+	if !projectInfo.Incomplete || len(objectives) != 2 {
+		t.Fatalf("got %d objectives (incomplete: %v), want the 2 found before the interruption", len(objectives), projectInfo.Incomplete)
+	}
+	if warnings := strings.Join(projectInfo.Warnings, "\n"); !strings.Contains(warnings, "matched 3 issues but only 2 were collected") {
+		t.Errorf("warnings = %q, want the truncated search", warnings)
+	}
+
+	filename := filepath.Join(t.TempDir(), "report.md")
+	if err := output.NewReportGeneratorWithConfig(config).GenerateReport(objectives, projectInfo, ports.OutputFormatMarkdown, filename); err != nil {
+		t.Fatalf("GenerateReport: %v", err)
+	}
+	if report, err := os.ReadFile(filename); err != nil || !strings.HasPrefix(string(report), "# OKR Report (incomplete)") {
+		t.Errorf("report starts %.40q (%v), want it marked incomplete", report, err)
+	}
+
+	// The partial result was not cached, so the next run searches again and gets everything
+	stall.Store(false)
+	okrService, config = newTestService(t, server, configure)
+	objectives, projectInfo, err = okrService.FetchOKRData(context.Background(), config)
+	if err != nil {
+		t.Fatalf("FetchOKRData after the interruption: %v", err)
+	}
+	if projectInfo.Incomplete || len(objectives) != 3 {
+		t.Errorf("got %d objectives (incomplete: %v), want 3 complete ones", len(objectives), projectInfo.Incomplete)
+	}
+	// The stalled page was never served: one page in the first run, both in the second
+	if n := server.CountRequests("GET /search/issues"); n != 3 {
+		t.Errorf("search pages served = %d, want 3", n)
+	}
+}
+
+func TestProcessOKRIssuesSurvivesCycles(t *testing.T) {
+	server := githubtest.NewServer(t)
+	server.AddIssue(
+		githubtest.Issue{Ref: "acme/okrs#1", Title: "A", Parent: "acme/okrs#2"},
+		githubtest.Issue{Ref: "acme/okrs#2", Title: "B", Parent: "acme/okrs#1"},
+	)
+	okrService, _ := newTestService(t, server, nil)
+	issues := []*entity.Issue{
+		{Number: 1, Title: "A", URL: "https://github.com/acme/okrs/issues/1"},
+		{Number: 2, Title: "B", URL: "https://github.com/acme/okrs/issues/2"},
+	}
+	issues[0].Parent = issues[1]
+	issues[1].Parent = issues[0]
+
+	objectives, err := okrService.ProcessOKRIssues(context.Background(), issues, nil)
+	if err != nil {
+		t.Fatalf("ProcessOKRIssues: %v", err)
+	}
+	if len(objectives) != 2 {
+		t.Fatalf("objectives = %d, want both issues as objectives", len(objectives))
+	}
+	for _, objective := range objectives {
+		if len(objective.ChildIssues) != 1 || len(objective.ChildIssues[0].ChildIssues) != 0 {
+			t.Errorf("%s should have its partner as only child, without recursing", objective.Issue.Ref())
+		}
+	}
+}
+
+func TestExtractParentIssueRef(t *testing.T) {
+	okrService := NewOKRService(nil)
+	tests := []struct {
+		body string
+		want string
+	}{
+		{"Parent Issue: #12", "acme/okrs#12"},
+		{"parent: acme/roadmap#3", "acme/roadmap#3"},
+		{"This is part of https://github.com/acme/roadmap/issues/4", "acme/roadmap#4"},
+		{"Child of #7 and relates to #8", "acme/okrs#7"},
+		{"Mentions #9 without a relationship", "#0"},
+	}
+
+	for _, tt := range tests {
+		issue := &entity.Issue{Number: 1, URL: "https://github.com/acme/okrs/issues/1", Body: tt.body}
+		if got := okrService.extractParentIssueRef(issue).String(); got != tt.want {
+			t.Errorf("extractParentIssueRef(%q) = %s, want %s", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestExtractWeeklyUpdates(t *testing.T) {
+	okrService := NewOKRService(nil)
+	updates := okrService.ExtractWeeklyUpdates([]string{
+		"# Weekly update 2025-01-06\nGood progress",
+		"Not an update",
+		"# weekly update 2025-01-20\nAll done ✅",
+		"# Weekly Update 2025-01-13\nWe are behind schedule",
+	})
+
+	var got []string
+	for _, update := range updates {
+		got = append(got, fmt.Sprintf("%s %s", update.Date, update.Status))
+	}
+	want := "[2025-01-20 completed 2025-01-13 at-risk 2025-01-06 on-track]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("updates = %v, want %s", got, want)
+	}
+}
+
+func TestDetectStatusFromContent(t *testing.T) {
+	okrService := NewOKRService(nil)
+	tests := []struct {
+		content string
+		want    entity.WeeklyUpdateStatus
+	}{
+		{"Migration finished", entity.StatusCompleted},
+		{"Stuck waiting for legal", entity.StatusBlocked},
+		{"Launch is delayed by a week", entity.StatusAtRisk},
+		{"🟢 moving along", entity.StatusOnTrack},
+		{"Nothing to report", entity.StatusOnTrack},
+	}
+
+	for _, tt := range tests {
+		if got := okrService.DetectStatusFromContent(tt.content); got != tt.want {
+			t.Errorf("DetectStatusFromContent(%q) = %s, want %s", tt.content, got, tt.want)
+		}
+	}
+}
@@ -13,16 +13,22 @@ type GitHubRepository interface {
 	FetchProjectIssues(ctx context.Context, projectInfo *entity.ProjectInfo) ([]*entity.Issue, error)
 	
 	// Issue operations
-	FetchIssuesBySearch(ctx context.Context, owner, repo, query string) ([]*entity.Issue, error)
-	FetchIssueComments(ctx context.Context, owner, repo string, issueNumber int) ([]*entity.WeeklyUpdate, error)
+	FetchIssuesBySearch(ctx context.Context, scope, query string) (*entity.SearchResult, error)
+	FetchIssueComments(ctx context.Context, ref entity.IssueRef) ([]*entity.WeeklyUpdate, error)
+	// FetchIssueTimeline returns the issue's history, oldest first, starting with when it was opened,
+	// and the pull requests that reference or close it
+	FetchIssueTimeline(ctx context.Context, ref entity.IssueRef) (*entity.IssueTimeline, error)
 	
 	// Relationship operations
-	FindParentIssue(ctx context.Context, owner, repo string, issueNumber int) (int, error)
+	FindParentIssue(ctx context.Context, ref entity.IssueRef) (*entity.Issue, error)
 	
 	// Utility operations
 	ExtractOwnerRepoFromIssue(issue *entity.Issue) (owner, repo string)
+	// TestBasicAccess checks the token and its scopes, and access to org unless it is empty
 	TestBasicAccess(ctx context.Context, org string) error
-	ListOrganizationProjects(ctx context.Context, org string) error
+	// CheckAccess is TestBasicAccess, except that a check passed recently may be answered from the cache
+	CheckAccess(ctx context.Context, org string) error
+	ListOrganizationProjects(ctx context.Context, org string) ([]*entity.ProjectSummary, error)
 }
 
 // GitHubService defines high-level GitHub operations
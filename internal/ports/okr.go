@@ -13,8 +13,8 @@ type OKRService interface {
 	ProcessOKRIssues(ctx context.Context, issues []*entity.Issue, requiredLabels []string) ([]*entity.IssueWithUpdates, error)
 	
 	// Issue relationship operations
-	BuildParentChildRelationships(ctx context.Context, issues []*entity.Issue) (map[int][]*entity.Issue, error)
-	IdentifyObjectivesAndKeyResults(issues []*entity.Issue, parentChildMap map[int][]*entity.Issue) ([]*entity.Issue, error)
+	BuildParentChildRelationships(ctx context.Context, issues []*entity.Issue) (map[entity.IssueRef][]*entity.Issue, error)
+	IdentifyObjectivesAndKeyResults(issues []*entity.Issue, parentChildMap map[entity.IssueRef][]*entity.Issue) ([]*entity.Issue, error)
 	
 	// Weekly update operations
 	ExtractWeeklyUpdates(updates []string) []*entity.WeeklyUpdate
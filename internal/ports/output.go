@@ -14,7 +14,7 @@ const (
 // OutputWriter defines the interface for writing output
 type OutputWriter interface {
 	WriteMarkdown(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo, filename string) error
-	WriteJSON(objectives []*entity.IssueWithUpdates, filename string) error
+	WriteJSON(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo, filename string) error
 	WriteGoogleDocs(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo, documentURL, clientID, clientSecret string) error
 }
 
@@ -23,6 +23,6 @@ type ReportGenerator interface {
 	GenerateReport(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo, format OutputFormat, filename string) error
 	GenerateReportWithGoogleDocs(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo, format OutputFormat, filename, documentURL, clientID, clientSecret string) error
 	FormatAsMarkdown(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo) string
-	FormatAsJSON(objectives []*entity.IssueWithUpdates) (string, error)
+	FormatAsJSON(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo) (string, error)
 	FormatAsGoogleDocs(objectives []*entity.IssueWithUpdates, projectInfo *entity.ProjectInfo) string
 }
\ No newline at end of file